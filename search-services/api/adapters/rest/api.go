@@ -1,12 +1,14 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"search-service/api/core"
 	"strconv"
 )
@@ -14,7 +16,28 @@ import (
 const (
 	paramPhrase = "phrase"
 	paramLimit  = "limit"
-	searchLimit = 10
+	paramOffset = "offset"
+	paramPrefix = "prefix"
+	paramID     = "id"
+	// explain и debug - синонимы: к комиксам добавляется разбор оценки
+	paramExplain = "explain"
+	paramDebug   = "debug"
+	// фильтры поиска: годы публикации и номера включительно, признаки комикса
+	paramYearFrom = "year_from"
+	paramYearTo   = "year_to"
+	paramIDFrom   = "id_from"
+	paramIDTo     = "id_to"
+	paramLink     = "link"
+	paramNews     = "news"
+	paramSort     = "sort"
+
+	suggestLimit = 10
+	searchLimit  = 10
+
+	// по идентификатору клиента запросы закрепляются за группой A/B эксперимента
+	headerClientID = "X-Client-ID"
+
+	maxNormBodySize = 2 << 20 // 2MB, words принимает фразы до 1MB
 )
 
 func encodeReply(w io.Writer, reply any) error {
@@ -75,68 +98,265 @@ func NewLoginHandler(log *slog.Logger, auth core.Authenticator) http.HandlerFunc
 }
 
 func NewSearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return newSearchHandler(log, searcher.Search)
+}
+
+func NewISearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return newSearchHandler(log, searcher.ISearch)
+}
+
+type searchFunc func(ctx context.Context, req core.SearchRequest) (core.SearchResult, error)
+
+func newSearchHandler(log *slog.Logger, search searchFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		phrase := r.URL.Query().Get(paramPhrase)
-		if phrase == "" {
-			w.WriteHeader(http.StatusBadRequest)
+		req, ok := parseSearchRequest(r)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
-		limitStr := r.URL.Query().Get(paramLimit)
-		limit, err := strconv.Atoi(limitStr)
+
+		result, err := search(r.Context(), req)
 		if err != nil {
-			if limitStr != "" {
+			var queryErr *core.QueryError
+			switch {
+			case errors.As(err, &queryErr):
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				if err := encodeReply(w, queryErr); err != nil {
+					log.Error("failed to encode", "error", err)
+				}
+			case errors.Is(err, core.ErrBadArguments):
 				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-				return
+			case errors.Is(err, core.ErrServiceUnavailable):
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			default:
+				log.Warn("service search failed", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			}
-			limit = searchLimit
+			return
+		}
+		result.Total = int64(len(result.Comics))
+		result.Offset = req.Offset
+		w.Header().Set("Content-Type", "application/json")
+		if err := encodeReply(w, result); err != nil {
+			log.Error("failed to encode", "error", err)
 		}
-		if limit <= 0 {
-			w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// parseSearchRequest разбирает параметры поиска: phrase обязателен,
+// limit по умолчанию searchLimit, offset по умолчанию 0.
+func parseSearchRequest(r *http.Request) (core.SearchRequest, bool) {
+	query := r.URL.Query()
+	phrase := query.Get(paramPhrase)
+	if phrase == "" {
+		return core.SearchRequest{}, false
+	}
+	limit, ok := parseInt(query.Get(paramLimit), searchLimit)
+	if !ok || limit <= 0 {
+		return core.SearchRequest{}, false
+	}
+	offset, ok := parseInt(query.Get(paramOffset), 0)
+	if !ok || offset < 0 {
+		return core.SearchRequest{}, false
+	}
+	explain, ok := parseBool(query.Get(paramExplain))
+	if !ok {
+		return core.SearchRequest{}, false
+	}
+	debug, ok := parseBool(query.Get(paramDebug))
+	if !ok {
+		return core.SearchRequest{}, false
+	}
+	filters, ok := parseFilters(query)
+	if !ok {
+		return core.SearchRequest{}, false
+	}
+	return core.SearchRequest{
+		Phrase:   phrase,
+		Limit:    limit,
+		Offset:   offset,
+		ClientID: r.Header.Get(headerClientID),
+		Explain:  explain || debug,
+		Filters:  filters,
+		Sort:     query.Get(paramSort),
+	}, true
+}
+
+// parseFilters разбирает фильтры поиска, отсутствующий параметр ничего не
+// ограничивает. Согласованность границ проверяет сервис поиска.
+func parseFilters(query url.Values) (core.Filters, bool) {
+	var bounds [4]int64
+	for i, param := range [...]string{paramYearFrom, paramYearTo, paramIDFrom, paramIDTo} {
+		n, ok := parseInt(query.Get(param), 0)
+		if !ok || n < 0 {
+			return core.Filters{}, false
+		}
+		bounds[i] = n
+	}
+	link, ok := parseBool(query.Get(paramLink))
+	if !ok {
+		return core.Filters{}, false
+	}
+	news, ok := parseBool(query.Get(paramNews))
+	if !ok {
+		return core.Filters{}, false
+	}
+	return core.Filters{
+		YearFrom: int(bounds[0]),
+		YearTo:   int(bounds[1]),
+		IDFrom:   bounds[2],
+		IDTo:     bounds[3],
+		Link:     link,
+		News:     news,
+	}, true
+}
+
+func parseBool(value string) (bool, bool) {
+	if value == "" {
+		return false, true
+	}
+	b, err := strconv.ParseBool(value)
+	return b, err == nil
+}
+
+func parseInt(value string, defaultValue int64) (int64, bool) {
+	if value == "" {
+		return defaultValue, true
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	return n, err == nil
+}
+
+// NewSimilarHandler возвращает комиксы, похожие на комикс из пути запроса.
+func NewSimilarHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue(paramID), 10, 64)
+		if err != nil || id <= 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		limit, ok := parseInt(r.URL.Query().Get(paramLimit), searchLimit)
+		if !ok || limit <= 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
 
-		comics, err := searcher.Search(r.Context(), phrase, int64(limit))
+		result, err := searcher.Similar(r.Context(), id, limit)
 		if err != nil {
 			switch {
 			case errors.Is(err, core.ErrBadArguments):
 				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			case errors.Is(err, core.ErrNotFound):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 			case errors.Is(err, core.ErrServiceUnavailable):
 				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 			default:
-				log.Warn("service search failed", "error", err)
+				log.Warn("service similar failed", "error", err)
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			}
 			return
 		}
+		result.Total = int64(len(result.Comics))
 		w.Header().Set("Content-Type", "application/json")
-		if err := encodeReply(w, core.SearchResult{Comics: comics, Total: int64(len(comics))}); err != nil {
+		if err := encodeReply(w, result); err != nil {
 			log.Error("failed to encode", "error", err)
 		}
 	}
 }
 
-func NewISearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+// NewComicHandler возвращает карточку комикса из пути запроса.
+func NewComicHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		phrase := r.URL.Query().Get(paramPhrase)
-		if phrase == "" {
+		id, err := strconv.ParseInt(r.PathValue(paramID), 10, 64)
+		if err != nil || id <= 0 {
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
-		limitStr := r.URL.Query().Get(paramLimit)
-		limit, err := strconv.Atoi(limitStr)
+		detail, err := searcher.GetComic(r.Context(), id)
+		writeComicDetail(w, log, detail, err)
+	}
+}
+
+// NewRandomComicHandler возвращает карточку случайного комикса.
+func NewRandomComicHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		detail, err := searcher.RandomComic(r.Context())
+		writeComicDetail(w, log, detail, err)
+	}
+}
+
+func writeComicDetail(w http.ResponseWriter, log *slog.Logger, detail core.ComicDetail, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, core.ErrBadArguments):
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		case errors.Is(err, core.ErrNotFound):
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		case errors.Is(err, core.ErrServiceUnavailable):
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		default:
+			log.Warn("service comic lookup failed", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := encodeReply(w, detail); err != nil {
+		log.Error("failed to encode", "error", err)
+	}
+}
+
+// NewSuggestHandler дополняет начало слова, которое вводит пользователь.
+func NewSuggestHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		prefix := query.Get(paramPrefix)
+		limit, ok := parseInt(query.Get(paramLimit), suggestLimit)
+		if prefix == "" || !ok || limit <= 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		suggestions, err := searcher.Suggest(r.Context(), prefix, limit)
 		if err != nil {
-			if limitStr != "" {
+			switch {
+			case errors.Is(err, core.ErrBadArguments):
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			case errors.Is(err, core.ErrServiceUnavailable):
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			default:
+				log.Warn("service suggest failed", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := encodeReply(w, core.SuggestResult{Suggestions: suggestions}); err != nil {
+			log.Error("failed to encode", "error", err)
+		}
+	}
+}
+
+// NewNormHandler возвращает нормализованные слова фразы и разбор по токенам.
+// Фраза передается параметром phrase в GET или в JSON-теле POST для длинных текстов.
+func NewNormHandler(log *slog.Logger, normalizer core.Normalizer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		phrase := r.URL.Query().Get(paramPhrase)
+		if r.Method == http.MethodPost {
+			var req core.NormRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxNormBodySize)).Decode(&req); err != nil {
 				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 				return
 			}
-			limit = searchLimit
+			phrase = req.Phrase
 		}
-		if limit <= 0 {
+		if phrase == "" {
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
 
-		comics, err := searcher.ISearch(r.Context(), phrase, int64(limit))
+		words, err := normalizer.Norm(r.Context(), phrase)
 		if err != nil {
 			switch {
 			case errors.Is(err, core.ErrBadArguments):
@@ -144,13 +364,24 @@ func NewISearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFun
 			case errors.Is(err, core.ErrServiceUnavailable):
 				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 			default:
-				log.Warn("service search failed", "error", err)
+				log.Warn("service words failed", "error", err)
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			}
 			return
 		}
+
+		if words == nil {
+			words = []string{}
+		}
+
+		// разбор по токенам необязателен: без него ответ содержит только слова
+		tokens, err := normalizer.Tokenize(r.Context(), phrase)
+		if err != nil {
+			log.Debug("tokenize failed, reply without tokens", "error", err)
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		if err := encodeReply(w, core.SearchResult{Comics: comics, Total: int64(len(comics))}); err != nil {
+		if err := encodeReply(w, core.NormResult{Words: words, Tokens: tokens}); err != nil {
 			log.Error("failed to encode", "error", err)
 		}
 	}
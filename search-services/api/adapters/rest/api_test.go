@@ -189,26 +189,34 @@ func TestSearchHandler(t *testing.T) {
 			desc: "success - returns comics",
 			url:  "/search?phrase=test&limit=5",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test", int64(5)).Return([]core.Comic{
-					{ID: 1, URL: "url1"},
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 5}).Return(core.SearchResult{Comics: []core.Comic{
+					{ID: 1, URL: "url1", Title: "Test", Matches: []core.FieldMatch{
+						{Field: "title", Terms: []string{"test"}, Snippet: "<mark>Test</mark>"},
+					}},
 					{ID: 2, URL: "url2"},
-				}, nil)
+				}, Ranker: "bm25"}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			wantBody:       true,
 			expectedBody: core.SearchResult{
-				Comics: []core.Comic{{ID: 1, URL: "url1"}, {ID: 2, URL: "url2"}},
+				Comics: []core.Comic{
+					{ID: 1, URL: "url1", Title: "Test", Matches: []core.FieldMatch{
+						{Field: "title", Terms: []string{"test"}, Snippet: "<mark>Test</mark>"},
+					}},
+					{ID: 2, URL: "url2"},
+				},
 				Total:  2,
+				Ranker: "bm25",
 			},
 		},
 		{
 			desc: "success - default limit",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test", int64(10)).Return([]core.Comic{
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{Comics: []core.Comic{
 					{ID: 1, URL: "url1"},
 					{ID: 2, URL: "url2"},
-				}, nil)
+				}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			wantBody:       true,
@@ -217,12 +225,126 @@ func TestSearchHandler(t *testing.T) {
 				Total:  2,
 			},
 		},
+		{
+			desc: "success - second page",
+			url:  "/search?phrase=test&limit=2&offset=2",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 2, Offset: 2}).Return(core.SearchResult{Comics: []core.Comic{
+					{ID: 3, URL: "url3"},
+				}, TotalHits: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantBody:       true,
+			expectedBody: core.SearchResult{
+				Comics:    []core.Comic{{ID: 3, URL: "url3"}},
+				Total:     1,
+				TotalHits: 3,
+				Offset:    2,
+			},
+		},
+		{
+			desc: "success - corrected query",
+			url:  "/search?phrase=relativty",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "relativty", Limit: 10}).Return(core.SearchResult{Comics: []core.Comic{
+					{ID: 1, URL: "url1"},
+				}, TotalHits: 1, CorrectedQuery: "relat"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantBody:       true,
+			expectedBody: core.SearchResult{
+				Comics:         []core.Comic{{ID: 1, URL: "url1"}},
+				Total:          1,
+				TotalHits:      1,
+				CorrectedQuery: "relat",
+			},
+		},
+		{
+			desc: "success - explain",
+			url:  "/search?phrase=test&debug=true",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10, Explain: true}).Return(core.SearchResult{Comics: []core.Comic{
+					{ID: 1, URL: "url1", Score: 1.5, MatchedTerms: []string{"test"}, Explanation: &core.Explanation{
+						Score: 1.5,
+						Terms: []core.TermScore{{Term: "test", TF: 1, DF: 1, IDF: 1.5, FieldWeight: 1, Score: 1.5}},
+						Boost: 1,
+					}},
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantBody:       true,
+			expectedBody: core.SearchResult{
+				Comics: []core.Comic{
+					{ID: 1, URL: "url1", Score: 1.5, MatchedTerms: []string{"test"}, Explanation: &core.Explanation{
+						Score: 1.5,
+						Terms: []core.TermScore{{Term: "test", TF: 1, DF: 1, IDF: 1.5, FieldWeight: 1, Score: 1.5}},
+						Boost: 1,
+					}},
+				},
+				Total: 1,
+			},
+		},
+		{
+			desc: "success - filters, sort and year facets",
+			url:  "/search?phrase=test&year_from=2008&year_to=2010&id_from=100&id_to=900&link=true&sort=date",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10, Filters: core.Filters{
+					YearFrom: 2008, YearTo: 2010, IDFrom: 100, IDTo: 900, Link: true,
+				}, Sort: "date"}).Return(core.SearchResult{
+					Comics: []core.Comic{{ID: 353, URL: "url353", Published: "2008-05-03", Link: "http://example.com"}},
+					Years:  []core.YearFacet{{Year: 2007, Count: 2}, {Year: 2008, Count: 1}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantBody:       true,
+			expectedBody: core.SearchResult{
+				Comics: []core.Comic{{ID: 353, URL: "url353", Published: "2008-05-03", Link: "http://example.com"}},
+				Total:  1,
+				Years:  []core.YearFacet{{Year: 2007, Count: 2}, {Year: 2008, Count: 1}},
+			},
+		},
+		{
+			desc:           "error - alpha year",
+			url:            "/search?phrase=test&year_from=abc",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - negative id bound",
+			url:            "/search?phrase=test&id_to=-5",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - bad flag",
+			url:            "/search?phrase=test&news=maybe",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - bad explain",
+			url:            "/search?phrase=test&explain=maybe",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			desc:           "error - no phrase",
 			url:            "/search?phrase=",
 			prepare:        func(s *core.MockSearcher) {},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			desc:           "error - alpha offset",
+			url:            "/search?phrase=test&offset=abc",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - negative offset",
+			url:            "/search?phrase=test&offset=-1",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			desc:           "error - alpha limit",
 			url:            "/search?phrase=test&limit=abc",
@@ -245,7 +367,7 @@ func TestSearchHandler(t *testing.T) {
 			desc: "error - service unavailable",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test", int64(10)).Return(nil, core.ErrServiceUnavailable)
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{}, core.ErrServiceUnavailable)
 			},
 			expectedStatus: http.StatusServiceUnavailable,
 		},
@@ -253,7 +375,15 @@ func TestSearchHandler(t *testing.T) {
 			desc: "error - bad arguments",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test", int64(10)).Return(nil, core.ErrBadArguments)
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{}, core.ErrBadArguments)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "error - unknown sort",
+			url:  "/search?phrase=test&sort=popular",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10, Sort: "popular"}).Return(core.SearchResult{}, core.ErrBadArguments)
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -261,7 +391,7 @@ func TestSearchHandler(t *testing.T) {
 			desc: "error - internal error",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test", int64(10)).Return(nil, errors.New("internal"))
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{}, errors.New("internal"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
@@ -294,6 +424,44 @@ func TestSearchHandler(t *testing.T) {
 	}
 }
 
+func TestSearchHandlerClientID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10, ClientID: "client-1"}).
+		Return(core.SearchResult{Comics: []core.Comic{}, Ranker: "recency"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/isearch?phrase=test", nil)
+	req.Header.Set("X-Client-ID", "client-1")
+	w := httptest.NewRecorder()
+
+	rest.NewISearchHandler(slog.Default(), mockSearcher)(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result core.SearchResult
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	require.Equal(t, "recency", result.Ranker)
+}
+
+func TestSearchHandlerQueryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "linux AND", Limit: 10}).
+		Return(core.SearchResult{}, &core.QueryError{Message: "missing operand after AND", Position: 6})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?phrase=linux+AND", nil)
+	w := httptest.NewRecorder()
+
+	rest.NewSearchHandler(slog.Default(), mockSearcher)(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"error": "missing operand after AND", "position": 6}`, w.Body.String())
+}
+
 func TestISearchHandler(t *testing.T) {
 	testCases := []struct {
 		desc           string
@@ -307,9 +475,9 @@ func TestISearchHandler(t *testing.T) {
 			desc: "success - returns comics",
 			url:  "/isearch?phrase=test&limit=5",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().ISearch(gomock.Any(), "test", int64(5)).Return([]core.Comic{
+				s.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 5}).Return(core.SearchResult{Comics: []core.Comic{
 					{ID: 1, URL: "url1"},
-				}, nil)
+				}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			wantBody:       true,
@@ -319,7 +487,7 @@ func TestISearchHandler(t *testing.T) {
 			desc: "success - default limit",
 			url:  "/isearch?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().ISearch(gomock.Any(), "test", int64(10)).Return([]core.Comic{}, nil)
+				s.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{Comics: []core.Comic{}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			wantBody:       true,
@@ -347,7 +515,7 @@ func TestISearchHandler(t *testing.T) {
 			desc: "error - service unavailable",
 			url:  "/isearch?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().ISearch(gomock.Any(), "test", int64(10)).Return(nil, core.ErrServiceUnavailable)
+				s.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{}, core.ErrServiceUnavailable)
 			},
 			expectedStatus: http.StatusServiceUnavailable,
 		},
@@ -355,7 +523,7 @@ func TestISearchHandler(t *testing.T) {
 			desc: "error - bad arguments",
 			url:  "/isearch?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().ISearch(gomock.Any(), "test", int64(10)).Return(nil, core.ErrBadArguments)
+				s.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{}, core.ErrBadArguments)
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -363,7 +531,7 @@ func TestISearchHandler(t *testing.T) {
 			desc: "error - internal error",
 			url:  "/isearch?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().ISearch(gomock.Any(), "test", int64(10)).Return(nil, errors.New("internal"))
+				s.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 10}).Return(core.SearchResult{}, errors.New("internal"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
@@ -396,6 +564,410 @@ func TestISearchHandler(t *testing.T) {
 	}
 }
 
+func TestSimilarHandler(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		id             string
+		url            string
+		prepare        func(*core.MockSearcher)
+		expectedStatus int
+		expectedBody   core.SearchResult
+	}{
+		{
+			desc: "success - returns similar comics",
+			id:   "1",
+			url:  "/api/comics/1/similar?limit=5",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Similar(gomock.Any(), int64(1), int64(5)).Return(core.SearchResult{
+					Comics:    []core.Comic{{ID: 2, URL: "url2", Score: 0.5, MatchedTerms: []string{"linux"}}},
+					TotalHits: 3,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: core.SearchResult{
+				Comics:    []core.Comic{{ID: 2, URL: "url2", Score: 0.5, MatchedTerms: []string{"linux"}}},
+				Total:     1,
+				TotalHits: 3,
+			},
+		},
+		{
+			desc: "success - default limit",
+			id:   "1",
+			url:  "/api/comics/1/similar",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Similar(gomock.Any(), int64(1), int64(10)).Return(core.SearchResult{Comics: []core.Comic{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   core.SearchResult{Comics: []core.Comic{}},
+		},
+		{
+			desc:           "error - bad id",
+			id:             "abc",
+			url:            "/api/comics/abc/similar",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - negative limit",
+			id:             "1",
+			url:            "/api/comics/1/similar?limit=-1",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "error - comic not found",
+			id:   "42",
+			url:  "/api/comics/42/similar",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Similar(gomock.Any(), int64(42), int64(10)).Return(core.SearchResult{}, core.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc: "error - service unavailable",
+			id:   "1",
+			url:  "/api/comics/1/similar",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Similar(gomock.Any(), int64(1), int64(10)).Return(core.SearchResult{}, core.ErrServiceUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			tc.prepare(mockSearcher)
+
+			handler := rest.NewSimilarHandler(slog.Default(), mockSearcher)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			req.SetPathValue("id", tc.id)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var result core.SearchResult
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+				require.Equal(t, tc.expectedBody, result)
+			}
+		})
+	}
+}
+
+func TestComicHandler(t *testing.T) {
+	detail := core.ComicDetail{
+		Comic:  core.Comic{ID: 2, URL: "url2", Title: "Cat", Alt: "meow", Transcript: "[[a cat]]"},
+		PrevID: 1,
+		NextID: 3,
+	}
+	testCases := []struct {
+		desc           string
+		id             string
+		prepare        func(*core.MockSearcher)
+		expectedStatus int
+	}{
+		{
+			desc: "success - returns comic",
+			id:   "2",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().GetComic(gomock.Any(), int64(2)).Return(detail, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "error - bad id",
+			id:             "abc",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "error - comic not found",
+			id:   "42",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().GetComic(gomock.Any(), int64(42)).Return(core.ComicDetail{}, core.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc: "error - internal error",
+			id:   "2",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().GetComic(gomock.Any(), int64(2)).Return(core.ComicDetail{}, errors.New("internal"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			tc.prepare(mockSearcher)
+
+			handler := rest.NewComicHandler(slog.Default(), mockSearcher)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/comics/"+tc.id, nil)
+			req.SetPathValue("id", tc.id)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var result core.ComicDetail
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+				require.Equal(t, detail, result)
+			}
+		})
+	}
+}
+
+func TestRandomComicHandler(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		detail         core.ComicDetail
+		err            error
+		expectedStatus int
+	}{
+		{
+			desc:           "success - returns comic",
+			detail:         core.ComicDetail{Comic: core.Comic{ID: 7, URL: "url7"}, PrevID: 6},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "error - empty index",
+			err:            core.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "error - service unavailable",
+			err:            core.ErrServiceUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			mockSearcher.EXPECT().RandomComic(gomock.Any()).Return(tc.detail, tc.err)
+
+			handler := rest.NewRandomComicHandler(slog.Default(), mockSearcher)
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, "/api/comics/random", nil))
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var result core.ComicDetail
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+				require.Equal(t, tc.detail, result)
+			}
+		})
+	}
+}
+
+func TestSuggestHandler(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		url            string
+		prepare        func(*core.MockSearcher)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc: "success - default limit",
+			url:  "/suggest?prefix=li",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Suggest(gomock.Any(), "li", int64(10)).Return([]core.Suggestion{
+					{Text: "linux", Count: 3},
+					{Text: "line", Count: 2},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"suggestions": [{"text": "linux", "count": 3}, {"text": "line", "count": 2}]}`,
+		},
+		{
+			desc: "success - no suggestions",
+			url:  "/suggest?prefix=xkcd&limit=5",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Suggest(gomock.Any(), "xkcd", int64(5)).Return([]core.Suggestion{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"suggestions": []}`,
+		},
+		{
+			desc:           "error - no prefix",
+			url:            "/suggest",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - bad limit",
+			url:            "/suggest?prefix=li&limit=-1",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "error - service unavailable",
+			url:  "/suggest?prefix=li",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Suggest(gomock.Any(), "li", int64(10)).Return(nil, core.ErrServiceUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			tc.prepare(mockSearcher)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			w := httptest.NewRecorder()
+
+			rest.NewSuggestHandler(slog.Default(), mockSearcher)(w, req)
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				require.JSONEq(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNormHandler(t *testing.T) {
+	tokens := []core.Token{
+		{Text: "Running", Start: 0, End: 7, Stem: "run", Position: 0},
+		{Text: "the", Start: 8, End: 11, Stem: "the", StopWord: true, Position: 1},
+		{Text: "tests", Start: 12, End: 17, Stem: "test", Position: 2},
+	}
+	testCases := []struct {
+		desc           string
+		method         string
+		url            string
+		body           string
+		prepare        func(*core.MockNormalizer)
+		expectedStatus int
+		expectedBody   *core.NormResult
+	}{
+		{
+			desc:   "success - get with tokens",
+			method: http.MethodGet,
+			url:    "/api/words/norm?phrase=Running+the+tests",
+			prepare: func(n *core.MockNormalizer) {
+				n.EXPECT().Norm(gomock.Any(), "Running the tests").Return([]string{"run", "test"}, nil)
+				n.EXPECT().Tokenize(gomock.Any(), "Running the tests").Return(tokens, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   &core.NormResult{Words: []string{"run", "test"}, Tokens: tokens},
+		},
+		{
+			desc:   "success - post long text",
+			method: http.MethodPost,
+			url:    "/api/words/norm",
+			body:   `{"phrase": "Running the tests"}`,
+			prepare: func(n *core.MockNormalizer) {
+				n.EXPECT().Norm(gomock.Any(), "Running the tests").Return([]string{"run", "test"}, nil)
+				n.EXPECT().Tokenize(gomock.Any(), "Running the tests").Return(tokens, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   &core.NormResult{Words: []string{"run", "test"}, Tokens: tokens},
+		},
+		{
+			desc:   "success - tokenize failed",
+			method: http.MethodGet,
+			url:    "/api/words/norm?phrase=the",
+			prepare: func(n *core.MockNormalizer) {
+				n.EXPECT().Norm(gomock.Any(), "the").Return(nil, nil)
+				n.EXPECT().Tokenize(gomock.Any(), "the").Return(nil, errors.New("unimplemented"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   &core.NormResult{Words: []string{}},
+		},
+		{
+			desc:           "error - no phrase",
+			method:         http.MethodGet,
+			url:            "/api/words/norm",
+			prepare:        func(n *core.MockNormalizer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - invalid body",
+			method:         http.MethodPost,
+			url:            "/api/words/norm",
+			body:           "phrase",
+			prepare:        func(n *core.MockNormalizer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:   "error - phrase too large",
+			method: http.MethodGet,
+			url:    "/api/words/norm?phrase=test",
+			prepare: func(n *core.MockNormalizer) {
+				n.EXPECT().Norm(gomock.Any(), "test").Return(nil, core.ErrBadArguments)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:   "error - service unavailable",
+			method: http.MethodGet,
+			url:    "/api/words/norm?phrase=test",
+			prepare: func(n *core.MockNormalizer) {
+				n.EXPECT().Norm(gomock.Any(), "test").Return(nil, core.ErrServiceUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:   "error - internal error",
+			method: http.MethodGet,
+			url:    "/api/words/norm?phrase=test",
+			prepare: func(n *core.MockNormalizer) {
+				n.EXPECT().Norm(gomock.Any(), "test").Return(nil, errors.New("internal"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockNormalizer := core.NewMockNormalizer(ctrl)
+			tc.prepare(mockNormalizer)
+
+			handler := rest.NewNormHandler(slog.Default(), mockNormalizer)
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != nil {
+				require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				var result core.NormResult
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+				require.Equal(t, *tc.expectedBody, result)
+			}
+		})
+	}
+}
+
 func TestUpdateHandler(t *testing.T) {
 	testCases := []struct {
 		desc           string
@@ -2,17 +2,22 @@ package search
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"search-service/api/core"
 	searchpb "search-service/proto/search"
+	"strconv"
 	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -61,49 +66,226 @@ func (c *Client) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (c *Client) Search(ctx context.Context, phrase string, limite int64) ([]core.Comic, error) {
-	stream, err := c.client.Search(ctx, &searchpb.SearchRequest{Phrase: phrase, Limit: limite})
+func (c *Client) Search(ctx context.Context, req core.SearchRequest) (core.SearchResult, error) {
+	stream, err := c.client.Search(ctx, makeRequest(req))
 	if err != nil {
-		switch status.Code(err) {
-		case codes.Unavailable:
-			return nil, core.ErrServiceUnavailable
-		case codes.InvalidArgument, codes.ResourceExhausted:
-			return nil, core.ErrBadArguments
-		default:
-			return nil, err
-		}
+		return core.SearchResult{}, makeError(err)
 	}
-	comics, err := collectCommics(stream)
-	return comics, err
+	return receiveResult(stream)
 }
 
-func (c *Client) ISearch(ctx context.Context, phrase string, limite int64) ([]core.Comic, error) {
-	stream, err := c.client.ISearch(ctx, &searchpb.SearchRequest{Phrase: phrase, Limit: limite})
+func (c *Client) ISearch(ctx context.Context, req core.SearchRequest) (core.SearchResult, error) {
+	stream, err := c.client.ISearch(ctx, makeRequest(req))
 	if err != nil {
-		switch status.Code(err) {
-		case codes.Unavailable:
-			return nil, core.ErrServiceUnavailable
-		case codes.InvalidArgument, codes.ResourceExhausted:
-			return nil, core.ErrBadArguments
-		default:
-			return nil, err
-		}
+		return core.SearchResult{}, makeError(err)
 	}
-	comics, err := collectCommics(stream)
-	return comics, err
+	return receiveResult(stream)
 }
 
-func collectCommics(stream grpc.ServerStreamingClient[searchpb.SearchReply]) ([]core.Comic, error) {
+// receiveResult читает комиксы из потока, а сводку поиска - из заголовка ответа.
+func receiveResult(stream grpc.ServerStreamingClient[searchpb.Comic]) (core.SearchResult, error) {
 	var comics []core.Comic
 	for {
-		reply, err := stream.Recv()
+		comic, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return core.SearchResult{}, makeError(err)
+		}
+		comics = append(comics, makeComic(comic))
+	}
+
+	header, err := stream.Header()
+	if err != nil {
+		return core.SearchResult{}, makeError(err)
+	}
+	summary := &searchpb.SearchSummary{}
+	if values := header.Get(searchpb.HeaderSummary); len(values) > 0 {
+		if err := proto.Unmarshal([]byte(values[0]), summary); err != nil {
+			return core.SearchResult{}, fmt.Errorf("failed to decode search summary: %w", err)
+		}
+	}
+	return core.SearchResult{
+		Comics:           comics,
+		TotalHits:        summary.GetTotalHits(),
+		Ranker:           summary.GetRanker(),
+		CorrectedQuery:   summary.GetCorrectedQuery(),
+		DidYouMean:       summary.GetDidYouMean(),
+		IndexGeneration:  summary.GetIndexGeneration(),
+		Years:            makeYears(summary.GetYearFacets()),
+		HighlightPreTag:  summary.GetHighlightPreTag(),
+		HighlightPostTag: summary.GetHighlightPostTag(),
+	}, nil
+}
+
+func (c *Client) Similar(ctx context.Context, id int64, limit int64) (core.SearchResult, error) {
+	reply, err := c.client.Similar(ctx, &searchpb.SimilarRequest{Id: id, Limit: limit})
+	if err != nil {
+		return core.SearchResult{}, makeError(err)
+	}
+	return makeResult(reply), nil
+}
+
+func (c *Client) GetComic(ctx context.Context, id int64) (core.ComicDetail, error) {
+	reply, err := c.client.GetComic(ctx, &searchpb.ComicRequest{Id: id})
+	if err != nil {
+		return core.ComicDetail{}, makeError(err)
+	}
+	return makeDetail(reply), nil
+}
+
+func (c *Client) RandomComic(ctx context.Context) (core.ComicDetail, error) {
+	reply, err := c.client.RandomComic(ctx, &emptypb.Empty{})
+	if err != nil {
+		return core.ComicDetail{}, makeError(err)
+	}
+	return makeDetail(reply), nil
+}
+
+func (c *Client) Suggest(ctx context.Context, prefix string, limit int64) ([]core.Suggestion, error) {
+	reply, err := c.client.Suggest(ctx, &searchpb.SuggestRequest{Prefix: prefix, Limit: limit})
+	if err != nil {
+		return nil, makeError(err)
+	}
+	suggestions := make([]core.Suggestion, len(reply.GetSuggestions()))
+	for i, suggestion := range reply.GetSuggestions() {
+		suggestions[i] = core.Suggestion{Text: suggestion.GetText(), Count: suggestion.GetCount()}
+	}
+	return suggestions, nil
+}
+
+func makeRequest(req core.SearchRequest) *searchpb.SearchRequest {
+	return &searchpb.SearchRequest{
+		Phrase:   req.Phrase,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+		ClientId: req.ClientID,
+		Explain:  req.Explain,
+		YearFrom: int32(req.Filters.YearFrom),
+		YearTo:   int32(req.Filters.YearTo),
+		IdFrom:   req.Filters.IDFrom,
+		IdTo:     req.Filters.IDTo,
+		Link:     req.Filters.Link,
+		News:     req.Filters.News,
+		Sort:     req.Sort,
+	}
+}
+
+func makeResult(reply *searchpb.SimilarReply) core.SearchResult {
+	comics := make([]core.Comic, len(reply.GetComics()))
+	for i, comic := range reply.GetComics() {
+		comics[i] = makeComic(comic)
+	}
+	return core.SearchResult{
+		Comics:          comics,
+		TotalHits:       reply.GetTotalHits(),
+		Ranker:          reply.GetRanker(),
+		CorrectedQuery:  reply.GetCorrectedQuery(),
+		DidYouMean:      reply.GetDidYouMean(),
+		IndexGeneration: reply.GetIndexGeneration(),
+		Years:           makeYears(reply.GetYearFacets()),
+	}
+}
+
+func makeYears(facets []*searchpb.YearFacet) []core.YearFacet {
+	var years []core.YearFacet
+	for _, facet := range facets {
+		years = append(years, core.YearFacet{Year: int(facet.GetYear()), Count: facet.GetCount()})
+	}
+	return years
+}
+
+func makeComic(comic *searchpb.Comic) core.Comic {
+	result := core.Comic{
+		ID:           comic.GetId(),
+		URL:          comic.GetUrl(),
+		Title:        comic.GetTitle(),
+		Alt:          comic.GetAlt(),
+		Transcript:   comic.GetTranscript(),
+		Published:    comic.GetPublished(),
+		Link:         comic.GetLink(),
+		News:         comic.GetNews(),
+		Score:        comic.GetScore(),
+		MatchedTerms: comic.GetMatchedTerms(),
+		Explanation:  makeExplanation(comic.GetExplanation()),
+	}
+	for _, match := range comic.GetMatches() {
+		result.Matches = append(result.Matches, core.FieldMatch{
+			Field:   match.GetField(),
+			Terms:   match.GetTerms(),
+			Snippet: match.GetSnippet(),
+		})
+	}
+	return result
+}
+
+func makeDetail(reply *searchpb.ComicReply) core.ComicDetail {
+	return core.ComicDetail{
+		Comic:  makeComic(reply.GetComic()),
+		PrevID: reply.GetPrevId(),
+		NextID: reply.GetNextId(),
+	}
+}
+
+func makeExplanation(explanation *searchpb.Explanation) *core.Explanation {
+	if explanation == nil {
+		return nil
+	}
+	result := &core.Explanation{
+		Score:    explanation.GetScore(),
+		Boost:    explanation.GetBoost(),
+		Bonus:    explanation.GetBonus(),
+		Phonetic: explanation.GetPhonetic(),
+	}
+	for _, term := range explanation.GetTerms() {
+		result.Terms = append(result.Terms, core.TermScore{
+			Term:        term.GetTerm(),
+			TF:          term.GetTf(),
+			DF:          term.GetDf(),
+			IDF:         term.GetIdf(),
+			FieldWeight: term.GetFieldWeight(),
+			Score:       term.GetScore(),
+		})
+	}
+	return result
+}
+
+func makeError(err error) error {
+	if queryErr := makeQueryError(err); queryErr != nil {
+		return queryErr
+	}
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return core.ErrServiceUnavailable
+	case codes.InvalidArgument, codes.ResourceExhausted:
+		return core.ErrBadArguments
+	case codes.NotFound:
+		return core.ErrNotFound
+	default:
+		return err
+	}
+}
+
+// makeQueryError извлекает позицию синтаксической ошибки из деталей статуса.
+func makeQueryError(err error) *core.QueryError {
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		return nil
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetReason() != searchpb.ReasonQuerySyntax {
+			continue
+		}
+		position, err := strconv.Atoi(info.GetMetadata()[searchpb.MetadataPosition])
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return nil, err
+			return nil
+		}
+		return &core.QueryError{
+			Message:  info.GetMetadata()[searchpb.MetadataMessage],
+			Position: position,
 		}
-		comics = append(comics, core.Comic{ID: reply.GetId(), URL: reply.GetUrl()})
 	}
-	return comics, nil
+	return nil
 }
@@ -74,3 +74,29 @@ func (c *Client) Norm(ctx context.Context, phrase string) ([]string, error) {
 	}
 	return reply.GetWords(), nil
 }
+
+func (c *Client) Tokenize(ctx context.Context, phrase string) ([]core.Token, error) {
+	reply, err := c.client.Tokenize(ctx, &wordspb.WordsRequest{Phrase: phrase})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable:
+			return nil, core.ErrServiceUnavailable
+		case codes.ResourceExhausted:
+			return nil, core.ErrBadArguments
+		default:
+			return nil, err
+		}
+	}
+	tokens := make([]core.Token, len(reply.GetTokens()))
+	for i, token := range reply.GetTokens() {
+		tokens[i] = core.Token{
+			Text:     token.GetText(),
+			Start:    token.GetStart(),
+			End:      token.GetEnd(),
+			Stem:     token.GetStem(),
+			StopWord: token.GetStopword(),
+			Position: token.GetPosition(),
+		}
+	}
+	return tokens, nil
+}
@@ -1,10 +1,29 @@
 package core
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrBadArguments       = errors.New("arguments are not acceptable")
 	ErrAlreadyExists      = errors.New("resource or task already exists")
 	ErrServiceUnavailable = errors.New("service is currently unavailable")
+	ErrNotFound           = errors.New("resource not found")
 )
+
+// QueryError - синтаксическая ошибка в поисковом запросе.
+// Position - позиция в символах от начала фразы.
+type QueryError struct {
+	Message  string `json:"error"`
+	Position int    `json:"position"`
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query syntax error at position %d: %s", e.Position, e.Message)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrBadArguments
+}
@@ -55,6 +55,21 @@ func (mr *MockNormalizerMockRecorder) Norm(ctx, phrase any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Norm", reflect.TypeOf((*MockNormalizer)(nil).Norm), ctx, phrase)
 }
 
+// Tokenize mocks base method.
+func (m *MockNormalizer) Tokenize(ctx context.Context, phrase string) ([]Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tokenize", ctx, phrase)
+	ret0, _ := ret[0].([]Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tokenize indicates an expected call of Tokenize.
+func (mr *MockNormalizerMockRecorder) Tokenize(ctx, phrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tokenize", reflect.TypeOf((*MockNormalizer)(nil).Tokenize), ctx, phrase)
+}
+
 // MockPinger is a mock of Pinger interface.
 type MockPinger struct {
 	ctrl     *gomock.Controller
@@ -199,34 +214,94 @@ func (m *MockSearcher) EXPECT() *MockSearcherMockRecorder {
 	return m.recorder
 }
 
+// GetComic mocks base method.
+func (m *MockSearcher) GetComic(ctx context.Context, id int64) (ComicDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComic", ctx, id)
+	ret0, _ := ret[0].(ComicDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComic indicates an expected call of GetComic.
+func (mr *MockSearcherMockRecorder) GetComic(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComic", reflect.TypeOf((*MockSearcher)(nil).GetComic), ctx, id)
+}
+
 // ISearch mocks base method.
-func (m *MockSearcher) ISearch(ctx context.Context, phrase string, limit int64) ([]Comic, error) {
+func (m *MockSearcher) ISearch(ctx context.Context, req SearchRequest) (SearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ISearch", ctx, phrase, limit)
-	ret0, _ := ret[0].([]Comic)
+	ret := m.ctrl.Call(m, "ISearch", ctx, req)
+	ret0, _ := ret[0].(SearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ISearch indicates an expected call of ISearch.
-func (mr *MockSearcherMockRecorder) ISearch(ctx, phrase, limit any) *gomock.Call {
+func (mr *MockSearcherMockRecorder) ISearch(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ISearch", reflect.TypeOf((*MockSearcher)(nil).ISearch), ctx, req)
+}
+
+// RandomComic mocks base method.
+func (m *MockSearcher) RandomComic(ctx context.Context) (ComicDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RandomComic", ctx)
+	ret0, _ := ret[0].(ComicDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RandomComic indicates an expected call of RandomComic.
+func (mr *MockSearcherMockRecorder) RandomComic(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ISearch", reflect.TypeOf((*MockSearcher)(nil).ISearch), ctx, phrase, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RandomComic", reflect.TypeOf((*MockSearcher)(nil).RandomComic), ctx)
 }
 
 // Search mocks base method.
-func (m *MockSearcher) Search(ctx context.Context, phrase string, limit int64) ([]Comic, error) {
+func (m *MockSearcher) Search(ctx context.Context, req SearchRequest) (SearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", ctx, phrase, limit)
-	ret0, _ := ret[0].([]Comic)
+	ret := m.ctrl.Call(m, "Search", ctx, req)
+	ret0, _ := ret[0].(SearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockSearcherMockRecorder) Search(ctx, phrase, limit any) *gomock.Call {
+func (mr *MockSearcherMockRecorder) Search(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearcher)(nil).Search), ctx, req)
+}
+
+// Similar mocks base method.
+func (m *MockSearcher) Similar(ctx context.Context, id, limit int64) (SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Similar", ctx, id, limit)
+	ret0, _ := ret[0].(SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Similar indicates an expected call of Similar.
+func (mr *MockSearcherMockRecorder) Similar(ctx, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Similar", reflect.TypeOf((*MockSearcher)(nil).Similar), ctx, id, limit)
+}
+
+// Suggest mocks base method.
+func (m *MockSearcher) Suggest(ctx context.Context, prefix string, limit int64) ([]Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, prefix, limit)
+	ret0, _ := ret[0].([]Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockSearcherMockRecorder) Suggest(ctx, prefix, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearcher)(nil).Search), ctx, phrase, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockSearcher)(nil).Suggest), ctx, prefix, limit)
 }
 
 // MockAuthenticator is a mock of Authenticator interface.
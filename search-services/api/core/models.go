@@ -28,8 +28,55 @@ type LoginRequest struct {
 }
 
 type Comic struct {
-	ID  int64  `json:"id"`
-	URL string `json:"url"`
+	ID      int64        `json:"id"`
+	URL     string       `json:"url"`
+	Title   string       `json:"title,omitempty"`
+	Matches []FieldMatch `json:"matches,omitempty"`
+	// только в карточке комикса
+	Alt        string `json:"alt,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	// дата публикации 2006-01-02, ссылка больших комиксов и новость анонсов
+	Published string `json:"published,omitempty"`
+	Link      string `json:"link,omitempty"`
+	News      string `json:"news,omitempty"`
+
+	Score        float64      `json:"score"`
+	MatchedTerms []string     `json:"matched_terms,omitempty"`
+	Explanation  *Explanation `json:"explanation,omitempty"`
+}
+
+// ComicDetail - карточка комикса с номерами соседних комиксов.
+type ComicDetail struct {
+	Comic
+	PrevID int64 `json:"prev_id,omitempty"`
+	NextID int64 `json:"next_id,omitempty"`
+}
+
+// Explanation - разбор оценки комикса: сумма вкладов слов, умноженная
+// на boost, плюс bonus. Phonetic - комикс найден только по звучанию.
+type Explanation struct {
+	Score    float64     `json:"score"`
+	Terms    []TermScore `json:"terms"`
+	Boost    float64     `json:"boost"`
+	Bonus    float64     `json:"bonus"`
+	Phonetic bool        `json:"phonetic,omitempty"`
+}
+
+type TermScore struct {
+	Term        string  `json:"term"`
+	TF          int64   `json:"tf"`
+	DF          int64   `json:"df"`
+	IDF         float64 `json:"idf"`
+	FieldWeight float64 `json:"field_weight"`
+	Score       float64 `json:"score"`
+}
+
+// FieldMatch - совпавшие с запросом основы поля комикса и фрагмент его текста
+// с отмеченными совпадениями.
+type FieldMatch struct {
+	Field   string   `json:"field"`
+	Terms   []string `json:"terms"`
+	Snippet string   `json:"snippet"`
 }
 
 type UpdateStats struct {
@@ -39,7 +86,72 @@ type UpdateStats struct {
 	ComicsTotal   int64 `json:"comics_total"`
 }
 
+type SearchRequest struct {
+	Phrase   string
+	Limit    int64
+	Offset   int64
+	ClientID string
+	Explain  bool
+	Filters  Filters
+	Sort     string // relevance, id_desc, id_asc или date; проверяет сервис поиска
+}
+
+// Filters - ограничения выдачи, нулевые значения ничего не ограничивают.
+type Filters struct {
+	YearFrom int
+	YearTo   int
+	IDFrom   int64
+	IDTo     int64
+	Link     bool
+	News     bool
+}
+
+// YearFacet - количество найденных комиксов за год.
+type YearFacet struct {
+	Year  int   `json:"year"`
+	Count int64 `json:"count"`
+}
+
 type SearchResult struct {
-	Comics []Comic `json:"comics"`
-	Total  int64   `json:"total"`
+	Comics         []Comic `json:"comics"`
+	Total          int64   `json:"total"`
+	TotalHits      int64   `json:"total_hits"`
+	Offset         int64   `json:"offset"`
+	Ranker         string  `json:"ranker,omitempty"`
+	CorrectedQuery string  `json:"corrected_query,omitempty"`
+	DidYouMean     string  `json:"did_you_mean,omitempty"`
+	// версия индекса, по которой выполнен поиск
+	IndexGeneration uint64 `json:"index_generation,omitempty"`
+	// распределение найденных по годам без учета фильтра по году
+	Years []YearFacet `json:"years,omitempty"`
+	// теги, которыми в фрагментах matches отмечены совпадения
+	HighlightPreTag  string `json:"highlight_pre_tag,omitempty"`
+	HighlightPostTag string `json:"highlight_post_tag,omitempty"`
+}
+
+type Suggestion struct {
+	Text  string `json:"text"`
+	Count int64  `json:"count"`
+}
+
+type SuggestResult struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+type NormRequest struct {
+	Phrase string `json:"phrase"`
+}
+
+type Token struct {
+	Text     string `json:"text"`
+	Start    int64  `json:"start"`
+	End      int64  `json:"end"`
+	Stem     string `json:"stem"`
+	StopWord bool   `json:"stopword"`
+	Position int64  `json:"position"`
+}
+
+type NormResult struct {
+	Words  []string `json:"words"`
+	Tokens []Token  `json:"tokens,omitempty"`
 }
@@ -8,6 +8,7 @@ import (
 
 type Normalizer interface {
 	Norm(ctx context.Context, phrase string) ([]string, error)
+	Tokenize(ctx context.Context, phrase string) ([]Token, error)
 }
 
 type Pinger interface {
@@ -22,8 +23,12 @@ type Updater interface {
 }
 
 type Searcher interface {
-	Search(ctx context.Context, phrase string, limit int64) ([]Comic, error)
-	ISearch(ctx context.Context, phrase string, limit int64) ([]Comic, error)
+	Search(ctx context.Context, req SearchRequest) (SearchResult, error)
+	ISearch(ctx context.Context, req SearchRequest) (SearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int64) ([]Suggestion, error)
+	Similar(ctx context.Context, id int64, limit int64) (SearchResult, error)
+	GetComic(ctx context.Context, id int64) (ComicDetail, error)
+	RandomComic(ctx context.Context) (ComicDetail, error)
 }
 
 type Authenticator interface {
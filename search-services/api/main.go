@@ -78,6 +78,12 @@ func run(cfg config.Config, log *slog.Logger) error {
 	mux.Handle("POST /api/login", rest.NewLoginHandler(log, jwtAth))
 	mux.Handle("GET /api/search", searchConcLimiter.Limit(rest.NewSearchHandler(log, search)))
 	mux.Handle("GET /api/isearch", searchRateLimiter.Limit(rest.NewISearchHandler(log, search)))
+	mux.Handle("GET /api/suggest", rest.NewSuggestHandler(log, search))
+	mux.Handle("GET /api/comics/{id}", rest.NewComicHandler(log, search))
+	mux.Handle("GET /api/comics/random", rest.NewRandomComicHandler(log, search))
+	mux.Handle("GET /api/comics/{id}/similar", searchRateLimiter.Limit(rest.NewSimilarHandler(log, search)))
+	mux.Handle("GET /api/words/norm", rest.NewNormHandler(log, words))
+	mux.Handle("POST /api/words/norm", rest.NewNormHandler(log, words))
 
 	// API admin endpoints (requires JWT)
 	mux.Handle("POST /api/db/update", jwtAth.CheckToken(rest.NewUpdateHandler(log, update)))
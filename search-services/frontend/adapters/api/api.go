@@ -9,14 +9,16 @@ import (
 	"net/http"
 	"net/url"
 	"search-service/frontend/core"
+	"strconv"
 	"time"
 )
 
 const (
 	pingEndpoint = "/api/ping"
 
-	searchEndpoint = "/api/search"
-	maxSearchLimit = 10000
+	searchEndpoint  = "/api/search"
+	suggestEndpoint = "/api/suggest"
+	headerClientID  = "X-Client-ID"
 
 	statusEndpoint = "/api/db/status"
 	statsEndpoint  = "/api/db/stats"
@@ -47,7 +49,7 @@ func (c *Client) Ping(ctx context.Context) (core.PingResponse, error) {
 	return reply, nil
 }
 
-func (c *Client) Search(ctx context.Context, phrase string) (core.SearchResult, error) {
+func (c *Client) Search(ctx context.Context, req core.SearchRequest) (core.SearchResult, error) {
 	u, err := url.JoinPath(c.address, searchEndpoint)
 	if err != nil {
 		return core.SearchResult{}, fmt.Errorf("cannot join url path: %w", err)
@@ -59,17 +61,77 @@ func (c *Client) Search(ctx context.Context, phrase string) (core.SearchResult,
 	}
 
 	q := parsedURL.Query()
-	q.Set("phrase", phrase)
-	q.Set("limit", fmt.Sprintf("%d", maxSearchLimit))
+	q.Set("phrase", req.Phrase)
+	q.Set("limit", strconv.FormatInt(req.Limit, 10))
+	q.Set("offset", strconv.FormatInt(req.Offset, 10))
+	if req.Explain {
+		q.Set("explain", "true")
+	}
+	setFilters(q, req.Filters)
+	if req.Sort != "" {
+		q.Set("sort", req.Sort)
+	}
 	parsedURL.RawQuery = q.Encode()
 
+	header := http.Header{}
+	if req.ClientID != "" {
+		header.Set(headerClientID, req.ClientID)
+	}
+
 	var reply core.SearchResult
-	if err := c.doGet(ctx, parsedURL.String(), &reply); err != nil {
+	if err := c.doGet(ctx, parsedURL.String(), header, &reply); err != nil {
 		return core.SearchResult{}, fmt.Errorf("failed to get search result: %w", err)
 	}
 	return reply, nil
 }
 
+// setFilters передает только заданные фильтры.
+func setFilters(q url.Values, filters core.Filters) {
+	bounds := []struct {
+		name  string
+		value int64
+	}{
+		{"year_from", int64(filters.YearFrom)},
+		{"year_to", int64(filters.YearTo)},
+		{"id_from", filters.IDFrom},
+		{"id_to", filters.IDTo},
+	}
+	for _, bound := range bounds {
+		if bound.value != 0 {
+			q.Set(bound.name, strconv.FormatInt(bound.value, 10))
+		}
+	}
+	if filters.Link {
+		q.Set("link", "true")
+	}
+	if filters.News {
+		q.Set("news", "true")
+	}
+}
+
+func (c *Client) Suggest(ctx context.Context, prefix string, limit int64) ([]core.Suggestion, error) {
+	u, err := url.JoinPath(c.address, suggestEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("cannot join url path: %w", err)
+	}
+
+	parsedURL, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse url: %w", err)
+	}
+
+	q := parsedURL.Query()
+	q.Set("prefix", prefix)
+	q.Set("limit", strconv.FormatInt(limit, 10))
+	parsedURL.RawQuery = q.Encode()
+
+	var reply core.SuggestResult
+	if err := c.doGet(ctx, parsedURL.String(), nil, &reply); err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+	return reply.Suggestions, nil
+}
+
 func (c *Client) GetUpdateStats(ctx context.Context) (core.UpdateStats, error) {
 	var reply core.UpdateStats
 	if err := c.doGetEndpoint(ctx, statsEndpoint, &reply); err != nil {
@@ -93,14 +155,17 @@ func (c *Client) doGetEndpoint(ctx context.Context, endpoint string, result inte
 	if err != nil {
 		return fmt.Errorf("cannot join url path: %w", err)
 	}
-	return c.doGet(ctx, fullURL, result)
+	return c.doGet(ctx, fullURL, nil, result)
 }
 
-func (c *Client) doGet(ctx context.Context, fullURL string, result interface{}) error {
+func (c *Client) doGet(ctx context.Context, fullURL string, header http.Header, result interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
 	if err != nil {
 		return fmt.Errorf("cannot create request: %w", err)
 	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -111,6 +176,12 @@ func (c *Client) doGet(ctx context.Context, fullURL string, result interface{})
 	if resp.StatusCode != http.StatusOK {
 		switch resp.StatusCode {
 		case http.StatusBadRequest:
+			// синтаксические ошибки запроса приходят в JSON с позицией
+			var queryErr core.QueryError
+			if resp.Header.Get("Content-Type") == "application/json" &&
+				json.NewDecoder(resp.Body).Decode(&queryErr) == nil {
+				return &queryErr
+			}
 			return core.ErrBadArguments
 		case http.StatusServiceUnavailable:
 			return core.ErrServiceUnavailable
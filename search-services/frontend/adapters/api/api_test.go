@@ -81,6 +81,20 @@ func TestSearch(t *testing.T) {
 				Total:  1,
 			},
 		},
+		{
+			desc:         "success - comics with matches",
+			phrase:       "test",
+			serverStatus: http.StatusOK,
+			serverReply: core.SearchResult{
+				Comics: []core.Comic{{ID: 1, URL: "url1", Title: "Test", Matches: []core.FieldMatch{
+					{Field: "title", Terms: []string{"test"}, Snippet: "<mark>Test</mark>"},
+				}}},
+				Total:            1,
+				CorrectedQuery:   "test",
+				HighlightPreTag:  "<mark>",
+				HighlightPostTag: "</mark>",
+			},
+		},
 		{
 			desc:         "error - bad request",
 			phrase:       "",
@@ -103,7 +117,9 @@ func TestSearch(t *testing.T) {
 				require.Equal(t, "/api/search", r.URL.Path)
 				require.Equal(t, http.MethodGet, r.Method)
 				require.Equal(t, tc.phrase, r.URL.Query().Get("phrase"))
-				require.Equal(t, "10000", r.URL.Query().Get("limit"))
+				require.Equal(t, "20", r.URL.Query().Get("limit"))
+				require.Equal(t, "40", r.URL.Query().Get("offset"))
+				require.Equal(t, "client", r.Header.Get("X-Client-ID"))
 
 				w.WriteHeader(tc.serverStatus)
 				if tc.serverStatus == http.StatusOK {
@@ -113,7 +129,12 @@ func TestSearch(t *testing.T) {
 			defer server.Close()
 
 			client := api.NewClient(server.URL, time.Second, slog.Default())
-			result, err := client.Search(context.Background(), tc.phrase)
+			result, err := client.Search(context.Background(), core.SearchRequest{
+				Phrase:   tc.phrase,
+				Limit:    20,
+				Offset:   40,
+				ClientID: "client",
+			})
 
 			if tc.wantErr {
 				require.ErrorIs(t, err, tc.expectedErr)
@@ -125,6 +146,66 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+func TestSearchFiltersAndSort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		require.Equal(t, "2008", query.Get("year_from"))
+		require.Equal(t, "100", query.Get("id_to"))
+		require.Equal(t, "true", query.Get("link"))
+		// незаданные фильтры не передаются
+		require.False(t, query.Has("year_to"))
+		require.False(t, query.Has("id_from"))
+		require.False(t, query.Has("news"))
+		require.Equal(t, "id_desc", query.Get("sort"))
+
+		_ = json.NewEncoder(w).Encode(core.SearchResult{Years: []core.YearFacet{{Year: 2008, Count: 2}}})
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, time.Second, slog.Default())
+	result, err := client.Search(context.Background(), core.SearchRequest{
+		Phrase:  "test",
+		Limit:   20,
+		Filters: core.Filters{YearFrom: 2008, IDTo: 100, Link: true},
+		Sort:    "id_desc",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []core.YearFacet{{Year: 2008, Count: 2}}, result.Years)
+}
+
+func TestSearchQueryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "unterminated quote", "position": 3}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, time.Second, slog.Default())
+	_, err := client.Search(context.Background(), core.SearchRequest{Phrase: `cat"`, Limit: 20})
+
+	require.ErrorIs(t, err, core.ErrBadArguments)
+	var queryErr *core.QueryError
+	require.ErrorAs(t, err, &queryErr)
+	require.Equal(t, core.QueryError{Message: "unterminated quote", Position: 3}, *queryErr)
+}
+
+func TestSuggest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/suggest", r.URL.Path)
+		require.Equal(t, "li", r.URL.Query().Get("prefix"))
+		require.Equal(t, "8", r.URL.Query().Get("limit"))
+		_, _ = w.Write([]byte(`{"suggestions": [{"text": "linux", "count": 3}]}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, time.Second, slog.Default())
+	suggestions, err := client.Suggest(context.Background(), "li", 8)
+
+	require.NoError(t, err)
+	require.Equal(t, []core.Suggestion{{Text: "linux", Count: 3}}, suggestions)
+}
+
 func TestGetUpdateStats(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -1,25 +1,54 @@
-async function search() {
-  const phrase = document.getElementById("searchInput").value.trim();
+const PAGE_SIZE = 20;
+
+let currentPhrase = "";
+// год, выбранный на гистограмме; null - все годы
+let currentYear = null;
+
+async function search(offset = 0) {
+  const input = document.getElementById("searchInput").value.trim();
+  // при переходе по страницам ищем исходную фразу, даже если поле изменилось
+  const phrase = offset === 0 ? input : currentPhrase;
   if (!phrase) return;
+  // новая фраза сбрасывает фильтр по году
+  if (phrase !== currentPhrase) currentYear = null;
+  currentPhrase = phrase;
 
   const results = document.getElementById("results");
+  const pager = document.getElementById("pager");
   results.innerHTML = '<div class="loading">Searching...</div>';
+  pager.innerHTML = "";
+  document.getElementById("spelling").innerHTML = "";
+  document.getElementById("years").innerHTML = "";
+
+  const sort = document.getElementById("sortSelect").value;
+  let url = `/api/search?phrase=${encodeURIComponent(phrase)}&limit=${PAGE_SIZE}&offset=${offset}&sort=${sort}`;
+  if (currentYear) url += `&year_from=${currentYear}&year_to=${currentYear}`;
 
   try {
-    const response = await fetch(
-      `/api/search?phrase=${encodeURIComponent(phrase)}`
-    );
-    if (!response.ok) throw new Error("Search failed");
+    const response = await fetch(url);
+    if (!response.ok) {
+      const error = response.headers.get("Content-Type") === "application/json"
+        ? await response.json()
+        : null;
+      if (error && error.error) {
+        throw new Error(`${error.error} (position ${error.position + 1})`);
+      }
+      throw new Error("Search failed");
+    }
 
     const data = await response.json();
+    renderSpelling(data);
+    renderYears(data.years);
 
     if (data.comics && data.comics.length > 0) {
       results.innerHTML = data.comics
         .map(
           (comic) => `
                 <div class="comic" onclick="openImage(this.querySelector('img'))">
-                    <img src="${comic.url}" alt="${comic.title || "Comic"}" loading="eager" />
-                    <h3>${comic.title || `Comic #${comic.id}`}</h3>
+                    <img src="${comic.url}" alt="${escapeHTML(comic.title || "Comic")}" loading="eager" />
+                    <h3>${escapeHTML(comic.title || `Comic #${comic.id}`)}</h3>
+                    ${comic.published ? `<span class="published">${comic.published}</span>` : ""}
+                    ${renderMatches(comic.matches, data.highlight_pre_tag, data.highlight_post_tag)}
                 </div>
             `
         )
@@ -29,6 +58,7 @@ async function search() {
         const img = new Image();
         img.src = comic.url;
       });
+      renderPager(data.offset, data.total_hits);
     } else {
       results.innerHTML = '<div class="error">No comics found</div>';
     }
@@ -37,6 +67,89 @@ async function search() {
   }
 }
 
+// показывает исправленную фразу: по ней уже найдены результаты
+// или по ней нашлось бы больше
+function renderSpelling(data) {
+  const spelling = document.getElementById("spelling");
+  const phrase = data.corrected_query || data.did_you_mean;
+  if (!phrase) return;
+
+  const link = document.createElement("a");
+  link.textContent = phrase;
+  link.onclick = () => {
+    document.getElementById("searchInput").value = phrase;
+    search();
+  };
+  spelling.append(data.corrected_query ? "Showing results for " : "Did you mean ", link);
+  if (!data.corrected_query) spelling.append("?");
+}
+
+// гистограмма найденных комиксов по годам; щелчок по году оставляет только
+// его комиксы, повторный щелчок снимает фильтр
+function renderYears(years) {
+  const histogram = document.getElementById("years");
+  if (!years || years.length === 0) return;
+
+  const most = Math.max(...years.map((y) => y.count));
+  histogram.innerHTML = years
+    .map(
+      (y) => `
+        <div class="year ${y.year === currentYear ? "selected" : ""}"
+             title="${y.count} comics" onclick="filterYear(${y.year})">
+          <div class="bar" style="height: ${Math.max(4, Math.round((y.count / most) * 60))}px"></div>
+          <span>${y.year}</span>
+        </div>
+      `
+    )
+    .join("");
+}
+
+function filterYear(year) {
+  currentYear = currentYear === year ? null : year;
+  document.getElementById("searchInput").value = currentPhrase;
+  search();
+}
+
+// фрагменты полей с совпадениями; поиск отмечает слова тегами из настроек
+// и присылает их вместе с выдачей, они показываются как <mark>,
+// остальной текст экранируется
+function renderMatches(matches, preTag, postTag) {
+  if (!matches || matches.length === 0) return "";
+  const mark = (snippet) => {
+    let html = escapeHTML(snippet);
+    if (preTag && postTag) {
+      html = html
+        .replaceAll(escapeHTML(preTag), "<mark>")
+        .replaceAll(escapeHTML(postTag), "</mark>");
+    }
+    return html;
+  };
+  return `<ul class="matches">${matches
+    .map((match) => `<li><span class="field">${match.field}</span> ${mark(match.snippet)}</li>`)
+    .join("")}</ul>`;
+}
+
+function escapeHTML(text) {
+  return text
+    .replaceAll("&", "&amp;")
+    .replaceAll("<", "&lt;")
+    .replaceAll(">", "&gt;")
+    .replaceAll('"', "&quot;");
+}
+
+function renderPager(offset, totalHits) {
+  const pager = document.getElementById("pager");
+  const pages = Math.ceil(totalHits / PAGE_SIZE);
+  if (pages <= 1) return;
+
+  const page = Math.floor(offset / PAGE_SIZE) + 1;
+  pager.innerHTML = `
+    <button ${page === 1 ? "disabled" : ""} onclick="search(${offset - PAGE_SIZE})">Prev</button>
+    <span>Page ${page} of ${pages} (${totalHits} comics)</span>
+    <button ${page === pages ? "disabled" : ""} onclick="search(${offset + PAGE_SIZE})">Next</button>
+  `;
+}
+
 function openImage(img) {
   let overlay = document.querySelector('.overlay');
   let closeBtn = document.querySelector('.close-btn');
@@ -75,6 +188,43 @@ function closeImage() {
   if (closeBtn) closeBtn.classList.remove('active');
 }
 
+let suggestTimer = null;
+
+// дополняет последнее слово фразы словами из индекса
+async function suggest() {
+  const value = document.getElementById("searchInput").value;
+  const list = document.getElementById("suggestions");
+  const match = value.match(/([\p{L}\p{N}]+)$/u);
+  if (!match || match[1].length < 2) {
+    list.innerHTML = "";
+    return;
+  }
+
+  try {
+    const response = await fetch(
+      `/api/suggest?prefix=${encodeURIComponent(match[1])}`
+    );
+    if (!response.ok) return;
+
+    const data = await response.json();
+    const head = value.slice(0, value.length - match[1].length);
+    list.innerHTML = "";
+    (data.suggestions || []).forEach((s) => {
+      const option = document.createElement("option");
+      option.value = head + s.text;
+      option.label = `${s.count} comics`;
+      list.appendChild(option);
+    });
+  } catch (error) {
+    list.innerHTML = "";
+  }
+}
+
+document.getElementById("searchInput").addEventListener("input", () => {
+  clearTimeout(suggestTimer);
+  suggestTimer = setTimeout(suggest, 150);
+});
+
 document.getElementById("searchInput").addEventListener("keypress", (e) => {
   if (e.key === "Enter") search();
 });
@@ -1,6 +1,8 @@
 package web
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,14 +10,36 @@ import (
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"search-service/frontend/core"
+	"strconv"
 	"time"
 )
 
 const (
 	paramPhrase = "phrase"
+	paramLimit  = "limit"
+	paramOffset = "offset"
+	paramPrefix = "prefix"
+	// explain и debug - синонимы: к комиксам добавляется разбор оценки
+	paramExplain = "explain"
+	paramDebug   = "debug"
+	// фильтры поиска: годы публикации и номера включительно, признаки комикса
+	paramYearFrom = "year_from"
+	paramYearTo   = "year_to"
+	paramIDFrom   = "id_from"
+	paramIDTo     = "id_to"
+	paramLink     = "link"
+	paramNews     = "news"
+	paramSort     = "sort"
+
+	defaultPageSize = 20
+	suggestLimit    = 8
 
 	cookieName = "jwt_token"
+
+	clientCookieName   = "client_id"
+	clientCookieMaxAge = 365 * 24 * 60 * 60
 )
 
 func encodeReply(w io.Writer, reply any) error {
@@ -83,15 +107,57 @@ func NewLoginHandler(log *slog.Logger, auth core.Authenticator, tokenTTL time.Du
 
 func NewSearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		phrase := r.URL.Query().Get(paramPhrase)
+		query := r.URL.Query()
+		phrase := query.Get(paramPhrase)
 		if phrase == "" {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		limit, ok := parseInt(query.Get(paramLimit), defaultPageSize)
+		if !ok || limit <= 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		offset, ok := parseInt(query.Get(paramOffset), 0)
+		if !ok || offset < 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		explain, ok := parseBool(query.Get(paramExplain))
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		debug, ok := parseBool(query.Get(paramDebug))
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		filters, ok := parseFilters(query)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
 
-		reply, err := searcher.Search(r.Context(), phrase)
+		req := core.SearchRequest{
+			Phrase:   phrase,
+			Limit:    limit,
+			Offset:   offset,
+			ClientID: clientID(w, r),
+			Explain:  explain || debug,
+			Filters:  filters,
+			Sort:     query.Get(paramSort),
+		}
+		reply, err := searcher.Search(r.Context(), req)
 		if err != nil {
+			var queryErr *core.QueryError
 			switch {
+			case errors.As(err, &queryErr):
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				if err := encodeReply(w, queryErr); err != nil {
+					log.Error("cannot encode reply", "error", err)
+				}
 			case errors.Is(err, core.ErrBadArguments):
 				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			case errors.Is(err, core.ErrServiceUnavailable):
@@ -109,6 +175,102 @@ func NewSearchHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc
 	}
 }
 
+func NewSuggestHandler(log *slog.Logger, searcher core.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get(paramPrefix)
+		if prefix == "" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		suggestions, err := searcher.Suggest(r.Context(), prefix, suggestLimit)
+		if err != nil {
+			switch {
+			case errors.Is(err, core.ErrBadArguments):
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			case errors.Is(err, core.ErrServiceUnavailable):
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			default:
+				log.Warn("service suggest failed", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := encodeReply(w, core.SuggestResult{Suggestions: suggestions}); err != nil {
+			log.Error("cannot encode reply", "error", err)
+		}
+	}
+}
+
+// parseFilters разбирает фильтры поиска, отсутствующий параметр ничего не
+// ограничивает. Согласованность границ проверяет сервис поиска.
+func parseFilters(query url.Values) (core.Filters, bool) {
+	var bounds [4]int64
+	for i, param := range [...]string{paramYearFrom, paramYearTo, paramIDFrom, paramIDTo} {
+		n, ok := parseInt(query.Get(param), 0)
+		if !ok || n < 0 {
+			return core.Filters{}, false
+		}
+		bounds[i] = n
+	}
+	link, ok := parseBool(query.Get(paramLink))
+	if !ok {
+		return core.Filters{}, false
+	}
+	news, ok := parseBool(query.Get(paramNews))
+	if !ok {
+		return core.Filters{}, false
+	}
+	return core.Filters{
+		YearFrom: int(bounds[0]),
+		YearTo:   int(bounds[1]),
+		IDFrom:   bounds[2],
+		IDTo:     bounds[3],
+		Link:     link,
+		News:     news,
+	}, true
+}
+
+func parseBool(value string) (bool, bool) {
+	if value == "" {
+		return false, true
+	}
+	b, err := strconv.ParseBool(value)
+	return b, err == nil
+}
+
+func parseInt(value string, defaultValue int64) (int64, bool) {
+	if value == "" {
+		return defaultValue, true
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	return n, err == nil
+}
+
+// clientID возвращает постоянный идентификатор браузера из cookie,
+// выдавая новый при первом поиске. По нему search закрепляет клиента
+// за группой эксперимента ранжирования.
+func clientID(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(clientCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	id := hex.EncodeToString(buf)
+	http.SetCookie(w, &http.Cookie{
+		Name:     clientCookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   clientCookieMaxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
 type statistics struct {
 	Stats  core.UpdateStats  `json:"stats"`
 	Status core.UpdateStatus `json:"status"`
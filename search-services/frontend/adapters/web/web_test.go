@@ -1,6 +1,7 @@
 package web_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -163,7 +164,7 @@ func TestSearchHandler(t *testing.T) {
 			desc: "success - returns comics",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test").Return(core.SearchResult{
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 20, ClientID: "client"}).Return(core.SearchResult{
 					Comics: []core.Comic{{ID: 1, URL: "url1"}},
 					Total:  1,
 				}, nil)
@@ -175,6 +176,82 @@ func TestSearchHandler(t *testing.T) {
 				Total:  1,
 			},
 		},
+		{
+			desc: "success - requested page",
+			url:  "/search?phrase=test&limit=1&offset=1",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 1, Offset: 1, ClientID: "client"}).Return(core.SearchResult{
+					Comics:    []core.Comic{{ID: 2, URL: "url2"}},
+					Total:     1,
+					TotalHits: 2,
+					Offset:    1,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantBody:       true,
+			expectedBody: core.SearchResult{
+				Comics:    []core.Comic{{ID: 2, URL: "url2"}},
+				Total:     1,
+				TotalHits: 2,
+				Offset:    1,
+			},
+		},
+		{
+			desc: "success - explain",
+			url:  "/search?phrase=test&explain=1",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 20, ClientID: "client", Explain: true}).Return(core.SearchResult{
+					Comics: []core.Comic{{ID: 1, URL: "url1", Score: 2, Explanation: &core.Explanation{Score: 2, Boost: 1}}},
+					Total:  1,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantBody:       true,
+			expectedBody: core.SearchResult{
+				Comics: []core.Comic{{ID: 1, URL: "url1", Score: 2, Explanation: &core.Explanation{Score: 2, Boost: 1}}},
+				Total:  1,
+			},
+		},
+		{
+			desc: "success - year filter, sort and facets",
+			url:  "/search?phrase=test&year_from=2010&year_to=2010&news=true&sort=date",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{
+					Phrase: "test", Limit: 20, ClientID: "client",
+					Filters: core.Filters{YearFrom: 2010, YearTo: 2010, News: true},
+					Sort:    "date",
+				}).Return(core.SearchResult{
+					Comics: []core.Comic{{ID: 1, URL: "url1", Published: "2010-01-01", News: "announcement"}},
+					Total:  1,
+					Years:  []core.YearFacet{{Year: 2009, Count: 3}, {Year: 2010, Count: 1}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantBody:       true,
+			expectedBody: core.SearchResult{
+				Comics: []core.Comic{{ID: 1, URL: "url1", Published: "2010-01-01", News: "announcement"}},
+				Total:  1,
+				Years:  []core.YearFacet{{Year: 2009, Count: 3}, {Year: 2010, Count: 1}},
+			},
+		},
+		{
+			desc:           "error - bad year",
+			url:            "/search?phrase=test&year_to=-1",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - bad limit",
+			url:            "/search?phrase=test&limit=0",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "error - bad offset",
+			url:            "/search?phrase=test&offset=-1",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			desc:           "error - empty phrase",
 			url:            "/search?phrase=",
@@ -191,7 +268,16 @@ func TestSearchHandler(t *testing.T) {
 			desc: "error - bad arguments",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test").Return(core.SearchResult{}, core.ErrBadArguments)
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 20, ClientID: "client"}).Return(core.SearchResult{}, core.ErrBadArguments)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "error - query syntax",
+			url:  "/search?phrase=test",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 20, ClientID: "client"}).
+					Return(core.SearchResult{}, &core.QueryError{Message: "unexpected )", Position: 4})
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -199,7 +285,7 @@ func TestSearchHandler(t *testing.T) {
 			desc: "error - service unavailable",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test").Return(core.SearchResult{}, core.ErrServiceUnavailable)
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 20, ClientID: "client"}).Return(core.SearchResult{}, core.ErrServiceUnavailable)
 			},
 			expectedStatus: http.StatusServiceUnavailable,
 		},
@@ -207,7 +293,7 @@ func TestSearchHandler(t *testing.T) {
 			desc: "error - internal error",
 			url:  "/search?phrase=test",
 			prepare: func(s *core.MockSearcher) {
-				s.EXPECT().Search(gomock.Any(), "test").Return(core.SearchResult{}, errors.New("internal"))
+				s.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: "test", Limit: 20, ClientID: "client"}).Return(core.SearchResult{}, errors.New("internal"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
@@ -224,6 +310,7 @@ func TestSearchHandler(t *testing.T) {
 			handler := web.NewSearchHandler(slog.Default(), mockSearcher)
 
 			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			req.AddCookie(&http.Cookie{Name: "client_id", Value: "client"})
 			w := httptest.NewRecorder()
 
 			handler(w, req)
@@ -240,6 +327,91 @@ func TestSearchHandler(t *testing.T) {
 	}
 }
 
+func TestSearchHandlerClientCookie(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var clientIDs []string
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req core.SearchRequest) (core.SearchResult, error) {
+			clientIDs = append(clientIDs, req.ClientID)
+			return core.SearchResult{}, nil
+		}).Times(2)
+
+	handler := web.NewSearchHandler(slog.Default(), mockSearcher)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/search?phrase=test", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, "client_id", cookies[0].Name)
+	require.NotEmpty(t, cookies[0].Value)
+
+	// повторный запрос с выданной cookie не выдает новую
+	req := httptest.NewRequest(http.MethodGet, "/search?phrase=test", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	handler(w, req)
+	require.Empty(t, w.Result().Cookies())
+
+	require.Equal(t, []string{cookies[0].Value, cookies[0].Value}, clientIDs)
+}
+
+func TestSuggestHandler(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		url            string
+		prepare        func(*core.MockSearcher)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc: "success - returns suggestions",
+			url:  "/suggest?prefix=li",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Suggest(gomock.Any(), "li", int64(8)).Return([]core.Suggestion{{Text: "linux", Count: 3}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"suggestions": [{"text": "linux", "count": 3}]}`,
+		},
+		{
+			desc:           "error - missing prefix",
+			url:            "/suggest",
+			prepare:        func(s *core.MockSearcher) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "error - internal error",
+			url:  "/suggest?prefix=li",
+			prepare: func(s *core.MockSearcher) {
+				s.EXPECT().Suggest(gomock.Any(), "li", int64(8)).Return(nil, errors.New("internal"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			tc.prepare(mockSearcher)
+
+			w := httptest.NewRecorder()
+			web.NewSuggestHandler(slog.Default(), mockSearcher)(w, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				require.JSONEq(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
 const statusUpdateIdle core.UpdateStatus = "idle"
 
 func TestStatisticsHandler(t *testing.T) {
@@ -1,6 +1,9 @@
 package core
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
@@ -8,3 +11,17 @@ var (
 	ErrAlreadyExists      = errors.New("resource or task already exists")
 	ErrServiceUnavailable = errors.New("service is currently unavailable")
 )
+
+// QueryError - синтаксическая ошибка в поисковом запросе от API.
+type QueryError struct {
+	Message  string `json:"error"`
+	Position int    `json:"position"`
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query syntax error at position %d: %s", e.Position, e.Message)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrBadArguments
+}
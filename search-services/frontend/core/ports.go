@@ -21,7 +21,8 @@ type Updater interface {
 }
 
 type Searcher interface {
-	Search(ctx context.Context, phrase string) (SearchResult, error)
+	Search(ctx context.Context, req SearchRequest) (SearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int64) ([]Suggestion, error)
 }
 
 type Authenticator interface {
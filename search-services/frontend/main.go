@@ -71,6 +71,7 @@ func run(cfg config.Config, log *slog.Logger) error {
 
 	// API endpoints
 	mux.Handle("GET /api/search", web.NewSearchHandler(log, api))
+	mux.Handle("GET /api/suggest", web.NewSuggestHandler(log, api))
 	mux.Handle("POST /api/login", web.NewLoginHandler(log, jwtAth, cfg.Auth.TokenTtl))
 	mux.Handle("GET /api/ping", web.NewPingHandler(log, api))
 
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats - счетчики кэша с момента запуска.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64 // вытеснены по размеру или удалены по истечении TTL
+	Size      int64
+}
+
+// LRU - кэш ограниченного размера с вытеснением давно неиспользуемых записей
+// и временем жизни записи. Нулевой размер отключает кэширование.
+type LRU[V any] struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // в начале - последние использованные записи
+	entries map[string]*list.Element
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+type entry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+// New создает кэш на size записей. При ttl <= 0 записи живут до вытеснения.
+func New[V any](size int, ttl time.Duration) *LRU[V] {
+	return &LRU[V]{
+		size:    max(size, 0),
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Key сворачивает части ключа в хэш фиксированной длины, чтобы кэш не хранил
+// сами фразы: они бывают до мегабайта.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return string(h.Sum(nil))
+}
+
+func (c *LRU[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return zero, false
+	}
+	e := elem.Value.(*entry[V])
+	if c.ttl > 0 && time.Now().After(e.expires) {
+		c.remove(elem)
+		c.misses.Add(1)
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	c.hits.Add(1)
+	return e.value, true
+}
+
+func (c *LRU[V]) Add(key string, value V) {
+	if c.size == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry[V])
+		e.value, e.expires = value, expires
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&entry[V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Purge удаляет все записи, например после перезагрузки словарей.
+func (c *LRU[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+}
+
+func (c *LRU[V]) Stats() Stats {
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      int64(size),
+	}
+}
+
+func (c *LRU[V]) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry[V]).key)
+	c.evictions.Add(1)
+}
@@ -0,0 +1,81 @@
+package cache_test
+
+import (
+	"crypto/sha256"
+	"search-service/pkg/cache"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	c := cache.New[int](2, time.Minute)
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	value, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	// "b" использовался раньше "a" и вытесняется первым
+	c.Add("c", 3)
+	_, ok = c.Get("b")
+	require.False(t, ok)
+	value, ok = c.Get("c")
+	require.True(t, ok)
+	require.Equal(t, 3, value)
+
+	c.Add("a", 10)
+	value, ok = c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 10, value)
+
+	require.Equal(t, cache.Stats{Hits: 3, Misses: 2, Evictions: 1, Size: 2}, c.Stats())
+}
+
+func TestLRUTTL(t *testing.T) {
+	c := cache.New[string](10, 10*time.Millisecond)
+	c.Add("key", "value")
+
+	_, ok := c.Get("key")
+	require.True(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = c.Get("key")
+	require.False(t, ok)
+	require.Equal(t, cache.Stats{Hits: 1, Misses: 1, Evictions: 1, Size: 0}, c.Stats())
+}
+
+func TestLRUPurge(t *testing.T) {
+	c := cache.New[int](10, 0)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Purge()
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	require.Equal(t, int64(0), c.Stats().Size)
+}
+
+func TestLRUDisabled(t *testing.T) {
+	c := cache.New[int](0, time.Minute)
+	c.Add("a", 1)
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	require.Equal(t, cache.Stats{Misses: 1}, c.Stats())
+}
+
+func TestKey(t *testing.T) {
+	key := cache.Key("opts", "true", strings.Repeat("long phrase ", 10_000))
+	require.Len(t, key, sha256.Size)
+	require.Equal(t, key, cache.Key("opts", "true", strings.Repeat("long phrase ", 10_000)))
+
+	// части не склеиваются друг с другом
+	require.NotEqual(t, cache.Key("ab", "c"), cache.Key("a", "bc"))
+}
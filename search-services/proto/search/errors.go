@@ -0,0 +1,11 @@
+package search
+
+// Детали ошибки InvalidArgument для синтаксической ошибки в запросе:
+// google.rpc.ErrorInfo с позицией и описанием ошибки в метаданных.
+const (
+	ErrorDomain       = "search"
+	ReasonQuerySyntax = "QUERY_SYNTAX"
+
+	MetadataPosition = "position"
+	MetadataMessage  = "message"
+)
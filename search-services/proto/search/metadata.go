@@ -0,0 +1,6 @@
+package search
+
+// HeaderSummary - ключ заголовка ответа Search и ISearch с SearchSummary.
+// Комиксы идут потоком, как и раньше, а сведения о выдаче в целом
+// передаются заголовком, чтобы формат сообщений потока не менялся.
+const HeaderSummary = "search-summary-bin"
@@ -23,9 +23,21 @@ const (
 )
 
 type SearchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Phrase        string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`
-	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Phrase   string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`
+	Limit    int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	ClientId string                 `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Offset   int64                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Explain  bool                   `protobuf:"varint,5,opt,name=explain,proto3" json:"explain,omitempty"`
+	// фильтры, 0 и false ничего не ограничивают
+	YearFrom int32 `protobuf:"varint,6,opt,name=year_from,json=yearFrom,proto3" json:"year_from,omitempty"`
+	YearTo   int32 `protobuf:"varint,7,opt,name=year_to,json=yearTo,proto3" json:"year_to,omitempty"`
+	IdFrom   int64 `protobuf:"varint,8,opt,name=id_from,json=idFrom,proto3" json:"id_from,omitempty"`
+	IdTo     int64 `protobuf:"varint,9,opt,name=id_to,json=idTo,proto3" json:"id_to,omitempty"`
+	Link     bool  `protobuf:"varint,10,opt,name=link,proto3" json:"link,omitempty"`
+	News     bool  `protobuf:"varint,11,opt,name=news,proto3" json:"news,omitempty"`
+	// relevance (по умолчанию), id_desc, id_asc или date
+	Sort          string `protobuf:"bytes,12,opt,name=sort,proto3" json:"sort,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,28 +86,99 @@ func (x *SearchRequest) GetLimit() int64 {
 	return 0
 }
 
-type SearchReply struct {
+func (x *SearchRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetExplain() bool {
+	if x != nil {
+		return x.Explain
+	}
+	return false
+}
+
+func (x *SearchRequest) GetYearFrom() int32 {
+	if x != nil {
+		return x.YearFrom
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetYearTo() int32 {
+	if x != nil {
+		return x.YearTo
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetIdFrom() int64 {
+	if x != nil {
+		return x.IdFrom
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetIdTo() int64 {
+	if x != nil {
+		return x.IdTo
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetLink() bool {
+	if x != nil {
+		return x.Link
+	}
+	return false
+}
+
+func (x *SearchRequest) GetNews() bool {
+	if x != nil {
+		return x.News
+	}
+	return false
+}
+
+func (x *SearchRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type FieldMatch struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Terms         []string               `protobuf:"bytes,2,rep,name=terms,proto3" json:"terms,omitempty"`
+	Snippet       string                 `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchReply) Reset() {
-	*x = SearchReply{}
+func (x *FieldMatch) Reset() {
+	*x = FieldMatch{}
 	mi := &file_proto_search_search_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchReply) String() string {
+func (x *FieldMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchReply) ProtoMessage() {}
+func (*FieldMatch) ProtoMessage() {}
 
-func (x *SearchReply) ProtoReflect() protoreflect.Message {
+func (x *FieldMatch) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_search_search_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -107,40 +190,1390 @@ func (x *SearchReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchReply.ProtoReflect.Descriptor instead.
-func (*SearchReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use FieldMatch.ProtoReflect.Descriptor instead.
+func (*FieldMatch) Descriptor() ([]byte, []int) {
 	return file_proto_search_search_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *SearchReply) GetId() int64 {
+func (x *FieldMatch) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldMatch) GetTerms() []string {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+func (x *FieldMatch) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type TermScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Term          string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	Tf            int64                  `protobuf:"varint,2,opt,name=tf,proto3" json:"tf,omitempty"`
+	Df            int64                  `protobuf:"varint,3,opt,name=df,proto3" json:"df,omitempty"`
+	Idf           float64                `protobuf:"fixed64,4,opt,name=idf,proto3" json:"idf,omitempty"`
+	FieldWeight   float64                `protobuf:"fixed64,5,opt,name=field_weight,json=fieldWeight,proto3" json:"field_weight,omitempty"`
+	Score         float64                `protobuf:"fixed64,6,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TermScore) Reset() {
+	*x = TermScore{}
+	mi := &file_proto_search_search_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TermScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TermScore) ProtoMessage() {}
+
+func (x *TermScore) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TermScore.ProtoReflect.Descriptor instead.
+func (*TermScore) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TermScore) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *TermScore) GetTf() int64 {
+	if x != nil {
+		return x.Tf
+	}
+	return 0
+}
+
+func (x *TermScore) GetDf() int64 {
+	if x != nil {
+		return x.Df
+	}
+	return 0
+}
+
+func (x *TermScore) GetIdf() float64 {
+	if x != nil {
+		return x.Idf
+	}
+	return 0
+}
+
+func (x *TermScore) GetFieldWeight() float64 {
+	if x != nil {
+		return x.FieldWeight
+	}
+	return 0
+}
+
+func (x *TermScore) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type Explanation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         float64                `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	Terms         []*TermScore           `protobuf:"bytes,2,rep,name=terms,proto3" json:"terms,omitempty"`
+	Boost         float64                `protobuf:"fixed64,3,opt,name=boost,proto3" json:"boost,omitempty"`
+	Bonus         float64                `protobuf:"fixed64,4,opt,name=bonus,proto3" json:"bonus,omitempty"`
+	Phonetic      bool                   `protobuf:"varint,5,opt,name=phonetic,proto3" json:"phonetic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Explanation) Reset() {
+	*x = Explanation{}
+	mi := &file_proto_search_search_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Explanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Explanation) ProtoMessage() {}
+
+func (x *Explanation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Explanation.ProtoReflect.Descriptor instead.
+func (*Explanation) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Explanation) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Explanation) GetTerms() []*TermScore {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+func (x *Explanation) GetBoost() float64 {
+	if x != nil {
+		return x.Boost
+	}
+	return 0
+}
+
+func (x *Explanation) GetBonus() float64 {
+	if x != nil {
+		return x.Bonus
+	}
+	return 0
+}
+
+func (x *Explanation) GetPhonetic() bool {
+	if x != nil {
+		return x.Phonetic
+	}
+	return false
+}
+
+type Comic struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url          string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Title        string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Matches      []*FieldMatch          `protobuf:"bytes,4,rep,name=matches,proto3" json:"matches,omitempty"`
+	Score        float64                `protobuf:"fixed64,5,opt,name=score,proto3" json:"score,omitempty"`
+	MatchedTerms []string               `protobuf:"bytes,6,rep,name=matched_terms,json=matchedTerms,proto3" json:"matched_terms,omitempty"`
+	Explanation  *Explanation           `protobuf:"bytes,7,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	// только в GetComic, GetComics и RandomComic
+	Alt        string `protobuf:"bytes,8,opt,name=alt,proto3" json:"alt,omitempty"`
+	Transcript string `protobuf:"bytes,9,opt,name=transcript,proto3" json:"transcript,omitempty"`
+	// дата публикации в формате 2006-01-02, пустая, если неизвестна
+	Published     string `protobuf:"bytes,10,opt,name=published,proto3" json:"published,omitempty"`
+	Link          string `protobuf:"bytes,11,opt,name=link,proto3" json:"link,omitempty"`
+	News          string `protobuf:"bytes,12,opt,name=news,proto3" json:"news,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comic) Reset() {
+	*x = Comic{}
+	mi := &file_proto_search_search_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comic) ProtoMessage() {}
+
+func (x *Comic) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comic.ProtoReflect.Descriptor instead.
+func (*Comic) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Comic) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *SearchReply) GetUrl() string {
+func (x *Comic) GetUrl() string {
 	if x != nil {
 		return x.Url
 	}
 	return ""
 }
 
-var File_proto_search_search_proto protoreflect.FileDescriptor
+func (x *Comic) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
 
-const file_proto_search_search_proto_rawDesc = "" +
-	"\n" +
-	"\x19proto/search/search.proto\x12\x06search\x1a\x1bgoogle/protobuf/empty.proto\"=\n" +
-	"\rSearchRequest\x12\x16\n" +
-	"\x06phrase\x18\x01 \x01(\tR\x06phrase\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x03R\x05limit\"/\n" +
-	"\vSearchReply\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
-	"\x03url\x18\x02 \x01(\tR\x03url2\xb7\x01\n" +
-	"\x06Search\x128\n" +
-	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x128\n" +
-	"\x06Search\x12\x15.search.SearchRequest\x1a\x13.search.SearchReply\"\x000\x01\x129\n" +
-	"\aISearch\x12\x15.search.SearchRequest\x1a\x13.search.SearchReply\"\x000\x01B\x1fZ\x1dyadro.com/course/proto/searchb\x06proto3"
+func (x *Comic) GetMatches() []*FieldMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *Comic) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Comic) GetMatchedTerms() []string {
+	if x != nil {
+		return x.MatchedTerms
+	}
+	return nil
+}
+
+func (x *Comic) GetExplanation() *Explanation {
+	if x != nil {
+		return x.Explanation
+	}
+	return nil
+}
+
+func (x *Comic) GetAlt() string {
+	if x != nil {
+		return x.Alt
+	}
+	return ""
+}
+
+func (x *Comic) GetTranscript() string {
+	if x != nil {
+		return x.Transcript
+	}
+	return ""
+}
+
+func (x *Comic) GetPublished() string {
+	if x != nil {
+		return x.Published
+	}
+	return ""
+}
+
+func (x *Comic) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *Comic) GetNews() string {
+	if x != nil {
+		return x.News
+	}
+	return ""
+}
+
+type YearFacet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Year          int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *YearFacet) Reset() {
+	*x = YearFacet{}
+	mi := &file_proto_search_search_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *YearFacet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*YearFacet) ProtoMessage() {}
+
+func (x *YearFacet) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use YearFacet.ProtoReflect.Descriptor instead.
+func (*YearFacet) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *YearFacet) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *YearFacet) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// SearchReply - сообщение потока Search и ISearch в прежних версиях. Теперь
+// в потоке Comic: его поля id и url совпадают по номерам, и прежние клиенты
+// читают поток как раньше.
+type SearchReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchReply) Reset() {
+	*x = SearchReply{}
+	mi := &file_proto_search_search_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchReply) ProtoMessage() {}
+
+func (x *SearchReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchReply.ProtoReflect.Descriptor instead.
+func (*SearchReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchReply) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SearchReply) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// SimilarReply - комиксы, похожие на заданный, со сведениями о выдаче.
+type SimilarReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Comics          []*Comic               `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	Ranker          string                 `protobuf:"bytes,2,opt,name=ranker,proto3" json:"ranker,omitempty"`
+	TotalHits       int64                  `protobuf:"varint,3,opt,name=total_hits,json=totalHits,proto3" json:"total_hits,omitempty"`
+	CorrectedQuery  string                 `protobuf:"bytes,4,opt,name=corrected_query,json=correctedQuery,proto3" json:"corrected_query,omitempty"`
+	DidYouMean      string                 `protobuf:"bytes,5,opt,name=did_you_mean,json=didYouMean,proto3" json:"did_you_mean,omitempty"`
+	IndexGeneration uint64                 `protobuf:"varint,6,opt,name=index_generation,json=indexGeneration,proto3" json:"index_generation,omitempty"`
+	YearFacets      []*YearFacet           `protobuf:"bytes,7,rep,name=year_facets,json=yearFacets,proto3" json:"year_facets,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SimilarReply) Reset() {
+	*x = SimilarReply{}
+	mi := &file_proto_search_search_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarReply) ProtoMessage() {}
+
+func (x *SimilarReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarReply.ProtoReflect.Descriptor instead.
+func (*SimilarReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SimilarReply) GetComics() []*Comic {
+	if x != nil {
+		return x.Comics
+	}
+	return nil
+}
+
+func (x *SimilarReply) GetRanker() string {
+	if x != nil {
+		return x.Ranker
+	}
+	return ""
+}
+
+func (x *SimilarReply) GetTotalHits() int64 {
+	if x != nil {
+		return x.TotalHits
+	}
+	return 0
+}
+
+func (x *SimilarReply) GetCorrectedQuery() string {
+	if x != nil {
+		return x.CorrectedQuery
+	}
+	return ""
+}
+
+func (x *SimilarReply) GetDidYouMean() string {
+	if x != nil {
+		return x.DidYouMean
+	}
+	return ""
+}
+
+func (x *SimilarReply) GetIndexGeneration() uint64 {
+	if x != nil {
+		return x.IndexGeneration
+	}
+	return 0
+}
+
+func (x *SimilarReply) GetYearFacets() []*YearFacet {
+	if x != nil {
+		return x.YearFacets
+	}
+	return nil
+}
+
+// SearchSummary - сведения о выдаче Search и ISearch без самих комиксов.
+type SearchSummary struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Ranker          string                 `protobuf:"bytes,1,opt,name=ranker,proto3" json:"ranker,omitempty"`
+	TotalHits       int64                  `protobuf:"varint,2,opt,name=total_hits,json=totalHits,proto3" json:"total_hits,omitempty"`
+	CorrectedQuery  string                 `protobuf:"bytes,3,opt,name=corrected_query,json=correctedQuery,proto3" json:"corrected_query,omitempty"`
+	DidYouMean      string                 `protobuf:"bytes,4,opt,name=did_you_mean,json=didYouMean,proto3" json:"did_you_mean,omitempty"`
+	IndexGeneration uint64                 `protobuf:"varint,5,opt,name=index_generation,json=indexGeneration,proto3" json:"index_generation,omitempty"`
+	YearFacets      []*YearFacet           `protobuf:"bytes,6,rep,name=year_facets,json=yearFacets,proto3" json:"year_facets,omitempty"`
+	// теги, которыми в фрагментах Comic.matches отмечены совпадения
+	HighlightPreTag  string `protobuf:"bytes,7,opt,name=highlight_pre_tag,json=highlightPreTag,proto3" json:"highlight_pre_tag,omitempty"`
+	HighlightPostTag string `protobuf:"bytes,8,opt,name=highlight_post_tag,json=highlightPostTag,proto3" json:"highlight_post_tag,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SearchSummary) Reset() {
+	*x = SearchSummary{}
+	mi := &file_proto_search_search_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSummary) ProtoMessage() {}
+
+func (x *SearchSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSummary.ProtoReflect.Descriptor instead.
+func (*SearchSummary) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchSummary) GetRanker() string {
+	if x != nil {
+		return x.Ranker
+	}
+	return ""
+}
+
+func (x *SearchSummary) GetTotalHits() int64 {
+	if x != nil {
+		return x.TotalHits
+	}
+	return 0
+}
+
+func (x *SearchSummary) GetCorrectedQuery() string {
+	if x != nil {
+		return x.CorrectedQuery
+	}
+	return ""
+}
+
+func (x *SearchSummary) GetDidYouMean() string {
+	if x != nil {
+		return x.DidYouMean
+	}
+	return ""
+}
+
+func (x *SearchSummary) GetIndexGeneration() uint64 {
+	if x != nil {
+		return x.IndexGeneration
+	}
+	return 0
+}
+
+func (x *SearchSummary) GetYearFacets() []*YearFacet {
+	if x != nil {
+		return x.YearFacets
+	}
+	return nil
+}
+
+func (x *SearchSummary) GetHighlightPreTag() string {
+	if x != nil {
+		return x.HighlightPreTag
+	}
+	return ""
+}
+
+func (x *SearchSummary) GetHighlightPostTag() string {
+	if x != nil {
+		return x.HighlightPostTag
+	}
+	return ""
+}
+
+type SimilarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarRequest) Reset() {
+	*x = SimilarRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarRequest) ProtoMessage() {}
+
+func (x *SimilarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarRequest.ProtoReflect.Descriptor instead.
+func (*SimilarRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SimilarRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SimilarRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ComicRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComicRequest) Reset() {
+	*x = ComicRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComicRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComicRequest) ProtoMessage() {}
+
+func (x *ComicRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComicRequest.ProtoReflect.Descriptor instead.
+func (*ComicRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ComicRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// ComicReply - комикс с номерами соседних комиксов; 0 - соседа нет.
+type ComicReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comic         *Comic                 `protobuf:"bytes,1,opt,name=comic,proto3" json:"comic,omitempty"`
+	PrevId        int64                  `protobuf:"varint,2,opt,name=prev_id,json=prevId,proto3" json:"prev_id,omitempty"`
+	NextId        int64                  `protobuf:"varint,3,opt,name=next_id,json=nextId,proto3" json:"next_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComicReply) Reset() {
+	*x = ComicReply{}
+	mi := &file_proto_search_search_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComicReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComicReply) ProtoMessage() {}
+
+func (x *ComicReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComicReply.ProtoReflect.Descriptor instead.
+func (*ComicReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ComicReply) GetComic() *Comic {
+	if x != nil {
+		return x.Comic
+	}
+	return nil
+}
+
+func (x *ComicReply) GetPrevId() int64 {
+	if x != nil {
+		return x.PrevId
+	}
+	return 0
+}
+
+func (x *ComicReply) GetNextId() int64 {
+	if x != nil {
+		return x.NextId
+	}
+	return 0
+}
+
+type ComicsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComicsRequest) Reset() {
+	*x = ComicsRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComicsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComicsRequest) ProtoMessage() {}
+
+func (x *ComicsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComicsRequest.ProtoReflect.Descriptor instead.
+func (*ComicsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ComicsRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type ComicsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comics        []*Comic               `protobuf:"bytes,1,rep,name=comics,proto3" json:"comics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComicsReply) Reset() {
+	*x = ComicsReply{}
+	mi := &file_proto_search_search_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComicsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComicsReply) ProtoMessage() {}
+
+func (x *ComicsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComicsReply.ProtoReflect.Descriptor instead.
+func (*ComicsReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ComicsReply) GetComics() []*Comic {
+	if x != nil {
+		return x.Comics
+	}
+	return nil
+}
+
+type SuggestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestRequest) Reset() {
+	*x = SuggestRequest{}
+	mi := &file_proto_search_search_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestRequest) ProtoMessage() {}
+
+func (x *SuggestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestRequest.ProtoReflect.Descriptor instead.
+func (*SuggestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SuggestRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Suggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Suggestion) Reset() {
+	*x = Suggestion{}
+	mi := &file_proto_search_search_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestion) ProtoMessage() {}
+
+func (x *Suggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestion.ProtoReflect.Descriptor instead.
+func (*Suggestion) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Suggestion) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Suggestion) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type SuggestReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*Suggestion          `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestReply) Reset() {
+	*x = SuggestReply{}
+	mi := &file_proto_search_search_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestReply) ProtoMessage() {}
+
+func (x *SuggestReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestReply.ProtoReflect.Descriptor instead.
+func (*SuggestReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SuggestReply) GetSuggestions() []*Suggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+type StatusReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IndexGeneration uint64                 `protobuf:"varint,1,opt,name=index_generation,json=indexGeneration,proto3" json:"index_generation,omitempty"`
+	// snapshot, database, events или empty
+	IndexSource string `protobuf:"bytes,2,opt,name=index_source,json=indexSource,proto3" json:"index_source,omitempty"`
+	// unix-время, на которое индекс отражает базу, и возраст индекса в секундах
+	IndexUpdatedAt  int64 `protobuf:"varint,3,opt,name=index_updated_at,json=indexUpdatedAt,proto3" json:"index_updated_at,omitempty"`
+	IndexAgeSeconds int64 `protobuf:"varint,4,opt,name=index_age_seconds,json=indexAgeSeconds,proto3" json:"index_age_seconds,omitempty"`
+	IndexComics     int64 `protobuf:"varint,5,opt,name=index_comics,json=indexComics,proto3" json:"index_comics,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StatusReply) Reset() {
+	*x = StatusReply{}
+	mi := &file_proto_search_search_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusReply) ProtoMessage() {}
+
+func (x *StatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusReply.ProtoReflect.Descriptor instead.
+func (*StatusReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StatusReply) GetIndexGeneration() uint64 {
+	if x != nil {
+		return x.IndexGeneration
+	}
+	return 0
+}
+
+func (x *StatusReply) GetIndexSource() string {
+	if x != nil {
+		return x.IndexSource
+	}
+	return ""
+}
+
+func (x *StatusReply) GetIndexUpdatedAt() int64 {
+	if x != nil {
+		return x.IndexUpdatedAt
+	}
+	return 0
+}
+
+func (x *StatusReply) GetIndexAgeSeconds() int64 {
+	if x != nil {
+		return x.IndexAgeSeconds
+	}
+	return 0
+}
+
+func (x *StatusReply) GetIndexComics() int64 {
+	if x != nil {
+		return x.IndexComics
+	}
+	return 0
+}
+
+type IndexStatsReply struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Terms    int64                  `protobuf:"varint,1,opt,name=terms,proto3" json:"terms,omitempty"`
+	Postings int64                  `protobuf:"varint,2,opt,name=postings,proto3" json:"postings,omitempty"`
+	// объем сжатых списков комиксов вместе с терминами
+	Bytes           int64 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	BuildDurationMs int64 `protobuf:"varint,4,opt,name=build_duration_ms,json=buildDurationMs,proto3" json:"build_duration_ms,omitempty"`
+	// unix-время последней сборки
+	BuiltAt       int64 `protobuf:"varint,5,opt,name=built_at,json=builtAt,proto3" json:"built_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndexStatsReply) Reset() {
+	*x = IndexStatsReply{}
+	mi := &file_proto_search_search_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndexStatsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexStatsReply) ProtoMessage() {}
+
+func (x *IndexStatsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexStatsReply.ProtoReflect.Descriptor instead.
+func (*IndexStatsReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *IndexStatsReply) GetTerms() int64 {
+	if x != nil {
+		return x.Terms
+	}
+	return 0
+}
+
+func (x *IndexStatsReply) GetPostings() int64 {
+	if x != nil {
+		return x.Postings
+	}
+	return 0
+}
+
+func (x *IndexStatsReply) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *IndexStatsReply) GetBuildDurationMs() int64 {
+	if x != nil {
+		return x.BuildDurationMs
+	}
+	return 0
+}
+
+func (x *IndexStatsReply) GetBuiltAt() int64 {
+	if x != nil {
+		return x.BuiltAt
+	}
+	return 0
+}
+
+type CacheStatsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          int64                  `protobuf:"varint,1,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        int64                  `protobuf:"varint,2,opt,name=misses,proto3" json:"misses,omitempty"`
+	Evictions     int64                  `protobuf:"varint,3,opt,name=evictions,proto3" json:"evictions,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	HitRatio      float64                `protobuf:"fixed64,5,opt,name=hit_ratio,json=hitRatio,proto3" json:"hit_ratio,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheStatsReply) Reset() {
+	*x = CacheStatsReply{}
+	mi := &file_proto_search_search_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStatsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStatsReply) ProtoMessage() {}
+
+func (x *CacheStatsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_search_search_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStatsReply.ProtoReflect.Descriptor instead.
+func (*CacheStatsReply) Descriptor() ([]byte, []int) {
+	return file_proto_search_search_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CacheStatsReply) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheStatsReply) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *CacheStatsReply) GetEvictions() int64 {
+	if x != nil {
+		return x.Evictions
+	}
+	return 0
+}
+
+func (x *CacheStatsReply) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *CacheStatsReply) GetHitRatio() float64 {
+	if x != nil {
+		return x.HitRatio
+	}
+	return 0
+}
+
+var File_proto_search_search_proto protoreflect.FileDescriptor
+
+const file_proto_search_search_proto_rawDesc = "" +
+	"\n" +
+	"\x19proto/search/search.proto\x12\x06search\x1a\x1bgoogle/protobuf/empty.proto\"\xac\x02\n" +
+	"\rSearchRequest\x12\x16\n" +
+	"\x06phrase\x18\x01 \x01(\tR\x06phrase\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x03R\x06offset\x12\x18\n" +
+	"\aexplain\x18\x05 \x01(\bR\aexplain\x12\x1b\n" +
+	"\tyear_from\x18\x06 \x01(\x05R\byearFrom\x12\x17\n" +
+	"\ayear_to\x18\a \x01(\x05R\x06yearTo\x12\x17\n" +
+	"\aid_from\x18\b \x01(\x03R\x06idFrom\x12\x13\n" +
+	"\x05id_to\x18\t \x01(\x03R\x04idTo\x12\x12\n" +
+	"\x04link\x18\n" +
+	" \x01(\bR\x04link\x12\x12\n" +
+	"\x04news\x18\v \x01(\bR\x04news\x12\x12\n" +
+	"\x04sort\x18\f \x01(\tR\x04sort\"R\n" +
+	"\n" +
+	"FieldMatch\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x14\n" +
+	"\x05terms\x18\x02 \x03(\tR\x05terms\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\"\x8a\x01\n" +
+	"\tTermScore\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x12\x0e\n" +
+	"\x02tf\x18\x02 \x01(\x03R\x02tf\x12\x0e\n" +
+	"\x02df\x18\x03 \x01(\x03R\x02df\x12\x10\n" +
+	"\x03idf\x18\x04 \x01(\x01R\x03idf\x12!\n" +
+	"\ffield_weight\x18\x05 \x01(\x01R\vfieldWeight\x12\x14\n" +
+	"\x05score\x18\x06 \x01(\x01R\x05score\"\x94\x01\n" +
+	"\vExplanation\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x01R\x05score\x12'\n" +
+	"\x05terms\x18\x02 \x03(\v2\x11.search.TermScoreR\x05terms\x12\x14\n" +
+	"\x05boost\x18\x03 \x01(\x01R\x05boost\x12\x14\n" +
+	"\x05bonus\x18\x04 \x01(\x01R\x05bonus\x12\x1a\n" +
+	"\bphonetic\x18\x05 \x01(\bR\bphonetic\"\xd7\x02\n" +
+	"\x05Comic\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12,\n" +
+	"\amatches\x18\x04 \x03(\v2\x12.search.FieldMatchR\amatches\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x01R\x05score\x12#\n" +
+	"\rmatched_terms\x18\x06 \x03(\tR\fmatchedTerms\x125\n" +
+	"\vexplanation\x18\a \x01(\v2\x13.search.ExplanationR\vexplanation\x12\x10\n" +
+	"\x03alt\x18\b \x01(\tR\x03alt\x12\x1e\n" +
+	"\n" +
+	"transcript\x18\t \x01(\tR\n" +
+	"transcript\x12\x1c\n" +
+	"\tpublished\x18\n" +
+	" \x01(\tR\tpublished\x12\x12\n" +
+	"\x04link\x18\v \x01(\tR\x04link\x12\x12\n" +
+	"\x04news\x18\f \x01(\tR\x04news\"5\n" +
+	"\tYearFacet\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"/\n" +
+	"\vSearchReply\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"\x96\x02\n" +
+	"\fSimilarReply\x12%\n" +
+	"\x06comics\x18\x01 \x03(\v2\r.search.ComicR\x06comics\x12\x16\n" +
+	"\x06ranker\x18\x02 \x01(\tR\x06ranker\x12\x1d\n" +
+	"\n" +
+	"total_hits\x18\x03 \x01(\x03R\ttotalHits\x12'\n" +
+	"\x0fcorrected_query\x18\x04 \x01(\tR\x0ecorrectedQuery\x12 \n" +
+	"\fdid_you_mean\x18\x05 \x01(\tR\n" +
+	"didYouMean\x12)\n" +
+	"\x10index_generation\x18\x06 \x01(\x04R\x0findexGeneration\x122\n" +
+	"\vyear_facets\x18\a \x03(\v2\x11.search.YearFacetR\n" +
+	"yearFacets\"\xca\x02\n" +
+	"\rSearchSummary\x12\x16\n" +
+	"\x06ranker\x18\x01 \x01(\tR\x06ranker\x12\x1d\n" +
+	"\n" +
+	"total_hits\x18\x02 \x01(\x03R\ttotalHits\x12'\n" +
+	"\x0fcorrected_query\x18\x03 \x01(\tR\x0ecorrectedQuery\x12 \n" +
+	"\fdid_you_mean\x18\x04 \x01(\tR\n" +
+	"didYouMean\x12)\n" +
+	"\x10index_generation\x18\x05 \x01(\x04R\x0findexGeneration\x122\n" +
+	"\vyear_facets\x18\x06 \x03(\v2\x11.search.YearFacetR\n" +
+	"yearFacets\x12*\n" +
+	"\x11highlight_pre_tag\x18\a \x01(\tR\x0fhighlightPreTag\x12,\n" +
+	"\x12highlight_post_tag\x18\b \x01(\tR\x10highlightPostTag\"6\n" +
+	"\x0eSimilarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\"\x1e\n" +
+	"\fComicRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"c\n" +
+	"\n" +
+	"ComicReply\x12#\n" +
+	"\x05comic\x18\x01 \x01(\v2\r.search.ComicR\x05comic\x12\x17\n" +
+	"\aprev_id\x18\x02 \x01(\x03R\x06prevId\x12\x17\n" +
+	"\anext_id\x18\x03 \x01(\x03R\x06nextId\"!\n" +
+	"\rComicsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\"4\n" +
+	"\vComicsReply\x12%\n" +
+	"\x06comics\x18\x01 \x03(\v2\r.search.ComicR\x06comics\">\n" +
+	"\x0eSuggestRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\"6\n" +
+	"\n" +
+	"Suggestion\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"D\n" +
+	"\fSuggestReply\x124\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x12.search.SuggestionR\vsuggestions\"\xd4\x01\n" +
+	"\vStatusReply\x12)\n" +
+	"\x10index_generation\x18\x01 \x01(\x04R\x0findexGeneration\x12!\n" +
+	"\findex_source\x18\x02 \x01(\tR\vindexSource\x12(\n" +
+	"\x10index_updated_at\x18\x03 \x01(\x03R\x0eindexUpdatedAt\x12*\n" +
+	"\x11index_age_seconds\x18\x04 \x01(\x03R\x0findexAgeSeconds\x12!\n" +
+	"\findex_comics\x18\x05 \x01(\x03R\vindexComics\"\xa0\x01\n" +
+	"\x0fIndexStatsReply\x12\x14\n" +
+	"\x05terms\x18\x01 \x01(\x03R\x05terms\x12\x1a\n" +
+	"\bpostings\x18\x02 \x01(\x03R\bpostings\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x03R\x05bytes\x12*\n" +
+	"\x11build_duration_ms\x18\x04 \x01(\x03R\x0fbuildDurationMs\x12\x19\n" +
+	"\bbuilt_at\x18\x05 \x01(\x03R\abuiltAt\"\x8c\x01\n" +
+	"\x0fCacheStatsReply\x12\x12\n" +
+	"\x04hits\x18\x01 \x01(\x03R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x02 \x01(\x03R\x06misses\x12\x1c\n" +
+	"\tevictions\x18\x03 \x01(\x03R\tevictions\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x1b\n" +
+	"\thit_ratio\x18\x05 \x01(\x01R\bhitRatio2\x8c\x05\n" +
+	"\x06Search\x128\n" +
+	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x122\n" +
+	"\x06Search\x12\x15.search.SearchRequest\x1a\r.search.Comic\"\x000\x01\x123\n" +
+	"\aISearch\x12\x15.search.SearchRequest\x1a\r.search.Comic\"\x000\x01\x129\n" +
+	"\aSuggest\x12\x16.search.SuggestRequest\x1a\x14.search.SuggestReply\"\x00\x129\n" +
+	"\aSimilar\x12\x16.search.SimilarRequest\x1a\x14.search.SimilarReply\"\x00\x126\n" +
+	"\bGetComic\x12\x14.search.ComicRequest\x1a\x12.search.ComicReply\"\x00\x129\n" +
+	"\tGetComics\x12\x15.search.ComicsRequest\x1a\x13.search.ComicsReply\"\x00\x12;\n" +
+	"\vRandomComic\x12\x16.google.protobuf.Empty\x1a\x12.search.ComicReply\"\x00\x127\n" +
+	"\x06Status\x12\x16.google.protobuf.Empty\x1a\x13.search.StatusReply\"\x00\x12?\n" +
+	"\n" +
+	"IndexStats\x12\x16.google.protobuf.Empty\x1a\x17.search.IndexStatsReply\"\x00\x12?\n" +
+	"\n" +
+	"CacheStats\x12\x16.google.protobuf.Empty\x1a\x17.search.CacheStatsReply\"\x00B\x1fZ\x1dyadro.com/course/proto/searchb\x06proto3"
 
 var (
 	file_proto_search_search_proto_rawDescOnce sync.Once
@@ -154,24 +1587,67 @@ func file_proto_search_search_proto_rawDescGZIP() []byte {
 	return file_proto_search_search_proto_rawDescData
 }
 
-var file_proto_search_search_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_search_search_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_search_search_proto_goTypes = []any{
-	(*SearchRequest)(nil), // 0: search.SearchRequest
-	(*SearchReply)(nil),   // 1: search.SearchReply
-	(*emptypb.Empty)(nil), // 2: google.protobuf.Empty
+	(*SearchRequest)(nil),   // 0: search.SearchRequest
+	(*FieldMatch)(nil),      // 1: search.FieldMatch
+	(*TermScore)(nil),       // 2: search.TermScore
+	(*Explanation)(nil),     // 3: search.Explanation
+	(*Comic)(nil),           // 4: search.Comic
+	(*YearFacet)(nil),       // 5: search.YearFacet
+	(*SearchReply)(nil),     // 6: search.SearchReply
+	(*SimilarReply)(nil),    // 7: search.SimilarReply
+	(*SearchSummary)(nil),   // 8: search.SearchSummary
+	(*SimilarRequest)(nil),  // 9: search.SimilarRequest
+	(*ComicRequest)(nil),    // 10: search.ComicRequest
+	(*ComicReply)(nil),      // 11: search.ComicReply
+	(*ComicsRequest)(nil),   // 12: search.ComicsRequest
+	(*ComicsReply)(nil),     // 13: search.ComicsReply
+	(*SuggestRequest)(nil),  // 14: search.SuggestRequest
+	(*Suggestion)(nil),      // 15: search.Suggestion
+	(*SuggestReply)(nil),    // 16: search.SuggestReply
+	(*StatusReply)(nil),     // 17: search.StatusReply
+	(*IndexStatsReply)(nil), // 18: search.IndexStatsReply
+	(*CacheStatsReply)(nil), // 19: search.CacheStatsReply
+	(*emptypb.Empty)(nil),   // 20: google.protobuf.Empty
 }
 var file_proto_search_search_proto_depIdxs = []int32{
-	2, // 0: search.Search.Ping:input_type -> google.protobuf.Empty
-	0, // 1: search.Search.Search:input_type -> search.SearchRequest
-	0, // 2: search.Search.ISearch:input_type -> search.SearchRequest
-	2, // 3: search.Search.Ping:output_type -> google.protobuf.Empty
-	1, // 4: search.Search.Search:output_type -> search.SearchReply
-	1, // 5: search.Search.ISearch:output_type -> search.SearchReply
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2,  // 0: search.Explanation.terms:type_name -> search.TermScore
+	1,  // 1: search.Comic.matches:type_name -> search.FieldMatch
+	3,  // 2: search.Comic.explanation:type_name -> search.Explanation
+	4,  // 3: search.SimilarReply.comics:type_name -> search.Comic
+	5,  // 4: search.SimilarReply.year_facets:type_name -> search.YearFacet
+	5,  // 5: search.SearchSummary.year_facets:type_name -> search.YearFacet
+	4,  // 6: search.ComicReply.comic:type_name -> search.Comic
+	4,  // 7: search.ComicsReply.comics:type_name -> search.Comic
+	15, // 8: search.SuggestReply.suggestions:type_name -> search.Suggestion
+	20, // 9: search.Search.Ping:input_type -> google.protobuf.Empty
+	0,  // 10: search.Search.Search:input_type -> search.SearchRequest
+	0,  // 11: search.Search.ISearch:input_type -> search.SearchRequest
+	14, // 12: search.Search.Suggest:input_type -> search.SuggestRequest
+	9,  // 13: search.Search.Similar:input_type -> search.SimilarRequest
+	10, // 14: search.Search.GetComic:input_type -> search.ComicRequest
+	12, // 15: search.Search.GetComics:input_type -> search.ComicsRequest
+	20, // 16: search.Search.RandomComic:input_type -> google.protobuf.Empty
+	20, // 17: search.Search.Status:input_type -> google.protobuf.Empty
+	20, // 18: search.Search.IndexStats:input_type -> google.protobuf.Empty
+	20, // 19: search.Search.CacheStats:input_type -> google.protobuf.Empty
+	20, // 20: search.Search.Ping:output_type -> google.protobuf.Empty
+	4,  // 21: search.Search.Search:output_type -> search.Comic
+	4,  // 22: search.Search.ISearch:output_type -> search.Comic
+	16, // 23: search.Search.Suggest:output_type -> search.SuggestReply
+	7,  // 24: search.Search.Similar:output_type -> search.SimilarReply
+	11, // 25: search.Search.GetComic:output_type -> search.ComicReply
+	13, // 26: search.Search.GetComics:output_type -> search.ComicsReply
+	11, // 27: search.Search.RandomComic:output_type -> search.ComicReply
+	17, // 28: search.Search.Status:output_type -> search.StatusReply
+	18, // 29: search.Search.IndexStats:output_type -> search.IndexStatsReply
+	19, // 30: search.Search.CacheStats:output_type -> search.CacheStatsReply
+	20, // [20:31] is the sub-list for method output_type
+	9,  // [9:20] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_search_search_proto_init() }
@@ -185,7 +1661,7 @@ func file_proto_search_search_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_search_search_proto_rawDesc), len(file_proto_search_search_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
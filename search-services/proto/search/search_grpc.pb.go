@@ -20,9 +20,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Search_Ping_FullMethodName    = "/search.Search/Ping"
-	Search_Search_FullMethodName  = "/search.Search/Search"
-	Search_ISearch_FullMethodName = "/search.Search/ISearch"
+	Search_Ping_FullMethodName        = "/search.Search/Ping"
+	Search_Search_FullMethodName      = "/search.Search/Search"
+	Search_ISearch_FullMethodName     = "/search.Search/ISearch"
+	Search_Suggest_FullMethodName     = "/search.Search/Suggest"
+	Search_Similar_FullMethodName     = "/search.Search/Similar"
+	Search_GetComic_FullMethodName    = "/search.Search/GetComic"
+	Search_GetComics_FullMethodName   = "/search.Search/GetComics"
+	Search_RandomComic_FullMethodName = "/search.Search/RandomComic"
+	Search_Status_FullMethodName      = "/search.Search/Status"
+	Search_IndexStats_FullMethodName  = "/search.Search/IndexStats"
+	Search_CacheStats_FullMethodName  = "/search.Search/CacheStats"
 )
 
 // SearchClient is the client API for Search service.
@@ -30,8 +38,18 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SearchClient interface {
 	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchReply], error)
-	ISearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchReply], error)
+	// комиксы передаются потоком, по сообщению на комикс, сводка по выдаче -
+	// в заголовке ответа search-summary-bin
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Comic], error)
+	ISearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Comic], error)
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestReply, error)
+	Similar(ctx context.Context, in *SimilarRequest, opts ...grpc.CallOption) (*SimilarReply, error)
+	GetComic(ctx context.Context, in *ComicRequest, opts ...grpc.CallOption) (*ComicReply, error)
+	GetComics(ctx context.Context, in *ComicsRequest, opts ...grpc.CallOption) (*ComicsReply, error)
+	RandomComic(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ComicReply, error)
+	Status(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatusReply, error)
+	IndexStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*IndexStatsReply, error)
+	CacheStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CacheStatsReply, error)
 }
 
 type searchClient struct {
@@ -52,13 +70,13 @@ func (c *searchClient) Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc
 	return out, nil
 }
 
-func (c *searchClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchReply], error) {
+func (c *searchClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Comic], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Search_ServiceDesc.Streams[0], Search_Search_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[SearchRequest, SearchReply]{ClientStream: stream}
+	x := &grpc.GenericClientStream[SearchRequest, Comic]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -69,15 +87,15 @@ func (c *searchClient) Search(ctx context.Context, in *SearchRequest, opts ...gr
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Search_SearchClient = grpc.ServerStreamingClient[SearchReply]
+type Search_SearchClient = grpc.ServerStreamingClient[Comic]
 
-func (c *searchClient) ISearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchReply], error) {
+func (c *searchClient) ISearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Comic], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Search_ServiceDesc.Streams[1], Search_ISearch_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[SearchRequest, SearchReply]{ClientStream: stream}
+	x := &grpc.GenericClientStream[SearchRequest, Comic]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -88,15 +106,105 @@ func (c *searchClient) ISearch(ctx context.Context, in *SearchRequest, opts ...g
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Search_ISearchClient = grpc.ServerStreamingClient[SearchReply]
+type Search_ISearchClient = grpc.ServerStreamingClient[Comic]
+
+func (c *searchClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestReply)
+	err := c.cc.Invoke(ctx, Search_Suggest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) Similar(ctx context.Context, in *SimilarRequest, opts ...grpc.CallOption) (*SimilarReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimilarReply)
+	err := c.cc.Invoke(ctx, Search_Similar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) GetComic(ctx context.Context, in *ComicRequest, opts ...grpc.CallOption) (*ComicReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComicReply)
+	err := c.cc.Invoke(ctx, Search_GetComic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) GetComics(ctx context.Context, in *ComicsRequest, opts ...grpc.CallOption) (*ComicsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComicsReply)
+	err := c.cc.Invoke(ctx, Search_GetComics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) RandomComic(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ComicReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComicReply)
+	err := c.cc.Invoke(ctx, Search_RandomComic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) Status(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*StatusReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusReply)
+	err := c.cc.Invoke(ctx, Search_Status_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) IndexStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*IndexStatsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IndexStatsReply)
+	err := c.cc.Invoke(ctx, Search_IndexStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) CacheStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CacheStatsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CacheStatsReply)
+	err := c.cc.Invoke(ctx, Search_CacheStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
 // SearchServer is the server API for Search service.
 // All implementations must embed UnimplementedSearchServer
 // for forward compatibility.
 type SearchServer interface {
 	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
-	Search(*SearchRequest, grpc.ServerStreamingServer[SearchReply]) error
-	ISearch(*SearchRequest, grpc.ServerStreamingServer[SearchReply]) error
+	// комиксы передаются потоком, по сообщению на комикс, сводка по выдаче -
+	// в заголовке ответа search-summary-bin
+	Search(*SearchRequest, grpc.ServerStreamingServer[Comic]) error
+	ISearch(*SearchRequest, grpc.ServerStreamingServer[Comic]) error
+	Suggest(context.Context, *SuggestRequest) (*SuggestReply, error)
+	Similar(context.Context, *SimilarRequest) (*SimilarReply, error)
+	GetComic(context.Context, *ComicRequest) (*ComicReply, error)
+	GetComics(context.Context, *ComicsRequest) (*ComicsReply, error)
+	RandomComic(context.Context, *emptypb.Empty) (*ComicReply, error)
+	Status(context.Context, *emptypb.Empty) (*StatusReply, error)
+	IndexStats(context.Context, *emptypb.Empty) (*IndexStatsReply, error)
+	CacheStats(context.Context, *emptypb.Empty) (*CacheStatsReply, error)
 	mustEmbedUnimplementedSearchServer()
 }
 
@@ -110,12 +218,36 @@ type UnimplementedSearchServer struct{}
 func (UnimplementedSearchServer) Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
-func (UnimplementedSearchServer) Search(*SearchRequest, grpc.ServerStreamingServer[SearchReply]) error {
+func (UnimplementedSearchServer) Search(*SearchRequest, grpc.ServerStreamingServer[Comic]) error {
 	return status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
-func (UnimplementedSearchServer) ISearch(*SearchRequest, grpc.ServerStreamingServer[SearchReply]) error {
+func (UnimplementedSearchServer) ISearch(*SearchRequest, grpc.ServerStreamingServer[Comic]) error {
 	return status.Errorf(codes.Unimplemented, "method ISearch not implemented")
 }
+func (UnimplementedSearchServer) Suggest(context.Context, *SuggestRequest) (*SuggestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedSearchServer) Similar(context.Context, *SimilarRequest) (*SimilarReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Similar not implemented")
+}
+func (UnimplementedSearchServer) GetComic(context.Context, *ComicRequest) (*ComicReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComic not implemented")
+}
+func (UnimplementedSearchServer) GetComics(context.Context, *ComicsRequest) (*ComicsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComics not implemented")
+}
+func (UnimplementedSearchServer) RandomComic(context.Context, *emptypb.Empty) (*ComicReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RandomComic not implemented")
+}
+func (UnimplementedSearchServer) Status(context.Context, *emptypb.Empty) (*StatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedSearchServer) IndexStats(context.Context, *emptypb.Empty) (*IndexStatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IndexStats not implemented")
+}
+func (UnimplementedSearchServer) CacheStats(context.Context, *emptypb.Empty) (*CacheStatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CacheStats not implemented")
+}
 func (UnimplementedSearchServer) mustEmbedUnimplementedSearchServer() {}
 func (UnimplementedSearchServer) testEmbeddedByValue()                {}
 
@@ -160,22 +292,166 @@ func _Search_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(SearchServer).Search(m, &grpc.GenericServerStream[SearchRequest, SearchReply]{ServerStream: stream})
+	return srv.(SearchServer).Search(m, &grpc.GenericServerStream[SearchRequest, Comic]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Search_SearchServer = grpc.ServerStreamingServer[SearchReply]
+type Search_SearchServer = grpc.ServerStreamingServer[Comic]
 
 func _Search_ISearch_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(SearchRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(SearchServer).ISearch(m, &grpc.GenericServerStream[SearchRequest, SearchReply]{ServerStream: stream})
+	return srv.(SearchServer).ISearch(m, &grpc.GenericServerStream[SearchRequest, Comic]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Search_ISearchServer = grpc.ServerStreamingServer[SearchReply]
+type Search_ISearchServer = grpc.ServerStreamingServer[Comic]
+
+func _Search_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Suggest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_Similar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimilarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Similar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Similar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Similar(ctx, req.(*SimilarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_GetComic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).GetComic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_GetComic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).GetComic(ctx, req.(*ComicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_GetComics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComicsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).GetComics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_GetComics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).GetComics(ctx, req.(*ComicsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_RandomComic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).RandomComic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_RandomComic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).RandomComic(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_Status_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).Status(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_IndexStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).IndexStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_IndexStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).IndexStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_CacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).CacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Search_CacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).CacheStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
 // Search_ServiceDesc is the grpc.ServiceDesc for Search service.
 // It's only intended for direct use with grpc.RegisterService,
@@ -188,6 +464,38 @@ var Search_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Ping",
 			Handler:    _Search_Ping_Handler,
 		},
+		{
+			MethodName: "Suggest",
+			Handler:    _Search_Suggest_Handler,
+		},
+		{
+			MethodName: "Similar",
+			Handler:    _Search_Similar_Handler,
+		},
+		{
+			MethodName: "GetComic",
+			Handler:    _Search_GetComic_Handler,
+		},
+		{
+			MethodName: "GetComics",
+			Handler:    _Search_GetComics_Handler,
+		},
+		{
+			MethodName: "RandomComic",
+			Handler:    _Search_RandomComic_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _Search_Status_Handler,
+		},
+		{
+			MethodName: "IndexStats",
+			Handler:    _Search_IndexStats_Handler,
+		},
+		{
+			MethodName: "CacheStats",
+			Handler:    _Search_CacheStats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
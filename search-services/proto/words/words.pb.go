@@ -25,6 +25,8 @@ const (
 type WordsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Phrase        string                 `protobuf:"bytes,1,opt,name=phrase,proto3" json:"phrase,omitempty"`
+	Phonetic      bool                   `protobuf:"varint,2,opt,name=phonetic,proto3" json:"phonetic,omitempty"`
+	Terms         bool                   `protobuf:"varint,3,opt,name=terms,proto3" json:"terms,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,9 +68,25 @@ func (x *WordsRequest) GetPhrase() string {
 	return ""
 }
 
+func (x *WordsRequest) GetPhonetic() bool {
+	if x != nil {
+		return x.Phonetic
+	}
+	return false
+}
+
+func (x *WordsRequest) GetTerms() bool {
+	if x != nil {
+		return x.Terms
+	}
+	return false
+}
+
 type WordsReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Words         []string               `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+	Phonetics     []string               `protobuf:"bytes,2,rep,name=phonetics,proto3" json:"phonetics,omitempty"`
+	Terms         []string               `protobuf:"bytes,3,rep,name=terms,proto3" json:"terms,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -110,19 +128,352 @@ func (x *WordsReply) GetWords() []string {
 	return nil
 }
 
+func (x *WordsReply) GetPhonetics() []string {
+	if x != nil {
+		return x.Phonetics
+	}
+	return nil
+}
+
+func (x *WordsReply) GetTerms() []string {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+type Token struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Start         int64                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	Stem          string                 `protobuf:"bytes,4,opt,name=stem,proto3" json:"stem,omitempty"`
+	Stopword      bool                   `protobuf:"varint,5,opt,name=stopword,proto3" json:"stopword,omitempty"`
+	Position      int64                  `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	Phonetics     []string               `protobuf:"bytes,7,rep,name=phonetics,proto3" json:"phonetics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_proto_words_words_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_words_words_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_proto_words_words_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Token) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Token) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Token) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Token) GetStem() string {
+	if x != nil {
+		return x.Stem
+	}
+	return ""
+}
+
+func (x *Token) GetStopword() bool {
+	if x != nil {
+		return x.Stopword
+	}
+	return false
+}
+
+func (x *Token) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Token) GetPhonetics() []string {
+	if x != nil {
+		return x.Phonetics
+	}
+	return nil
+}
+
+type TokenizeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*Token               `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenizeReply) Reset() {
+	*x = TokenizeReply{}
+	mi := &file_proto_words_words_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenizeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenizeReply) ProtoMessage() {}
+
+func (x *TokenizeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_words_words_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenizeReply.ProtoReflect.Descriptor instead.
+func (*TokenizeReply) Descriptor() ([]byte, []int) {
+	return file_proto_words_words_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TokenizeReply) GetTokens() []*Token {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type TokenizeBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phrases       []string               `protobuf:"bytes,1,rep,name=phrases,proto3" json:"phrases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenizeBatchRequest) Reset() {
+	*x = TokenizeBatchRequest{}
+	mi := &file_proto_words_words_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenizeBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenizeBatchRequest) ProtoMessage() {}
+
+func (x *TokenizeBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_words_words_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenizeBatchRequest.ProtoReflect.Descriptor instead.
+func (*TokenizeBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_words_words_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TokenizeBatchRequest) GetPhrases() []string {
+	if x != nil {
+		return x.Phrases
+	}
+	return nil
+}
+
+type TokenizeBatchReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replies       []*TokenizeReply       `protobuf:"bytes,1,rep,name=replies,proto3" json:"replies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenizeBatchReply) Reset() {
+	*x = TokenizeBatchReply{}
+	mi := &file_proto_words_words_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenizeBatchReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenizeBatchReply) ProtoMessage() {}
+
+func (x *TokenizeBatchReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_words_words_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenizeBatchReply.ProtoReflect.Descriptor instead.
+func (*TokenizeBatchReply) Descriptor() ([]byte, []int) {
+	return file_proto_words_words_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TokenizeBatchReply) GetReplies() []*TokenizeReply {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+type CacheStatsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          int64                  `protobuf:"varint,1,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        int64                  `protobuf:"varint,2,opt,name=misses,proto3" json:"misses,omitempty"`
+	Evictions     int64                  `protobuf:"varint,3,opt,name=evictions,proto3" json:"evictions,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheStatsReply) Reset() {
+	*x = CacheStatsReply{}
+	mi := &file_proto_words_words_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStatsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStatsReply) ProtoMessage() {}
+
+func (x *CacheStatsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_words_words_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStatsReply.ProtoReflect.Descriptor instead.
+func (*CacheStatsReply) Descriptor() ([]byte, []int) {
+	return file_proto_words_words_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CacheStatsReply) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheStatsReply) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *CacheStatsReply) GetEvictions() int64 {
+	if x != nil {
+		return x.Evictions
+	}
+	return 0
+}
+
+func (x *CacheStatsReply) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
 var File_proto_words_words_proto protoreflect.FileDescriptor
 
 const file_proto_words_words_proto_rawDesc = "" +
 	"\n" +
-	"\x17proto/words/words.proto\x12\x05words\x1a\x1bgoogle/protobuf/empty.proto\"&\n" +
+	"\x17proto/words/words.proto\x12\x05words\x1a\x1bgoogle/protobuf/empty.proto\"X\n" +
 	"\fWordsRequest\x12\x16\n" +
-	"\x06phrase\x18\x01 \x01(\tR\x06phrase\"\"\n" +
+	"\x06phrase\x18\x01 \x01(\tR\x06phrase\x12\x1a\n" +
+	"\bphonetic\x18\x02 \x01(\bR\bphonetic\x12\x14\n" +
+	"\x05terms\x18\x03 \x01(\bR\x05terms\"V\n" +
 	"\n" +
 	"WordsReply\x12\x14\n" +
-	"\x05words\x18\x01 \x03(\tR\x05words2s\n" +
+	"\x05words\x18\x01 \x03(\tR\x05words\x12\x1c\n" +
+	"\tphonetics\x18\x02 \x03(\tR\tphonetics\x12\x14\n" +
+	"\x05terms\x18\x03 \x03(\tR\x05terms\"\xad\x01\n" +
+	"\x05Token\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x03R\x03end\x12\x12\n" +
+	"\x04stem\x18\x04 \x01(\tR\x04stem\x12\x1a\n" +
+	"\bstopword\x18\x05 \x01(\bR\bstopword\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\x03R\bposition\x12\x1c\n" +
+	"\tphonetics\x18\a \x03(\tR\tphonetics\"5\n" +
+	"\rTokenizeReply\x12$\n" +
+	"\x06tokens\x18\x01 \x03(\v2\f.words.TokenR\x06tokens\"0\n" +
+	"\x14TokenizeBatchRequest\x12\x18\n" +
+	"\aphrases\x18\x01 \x03(\tR\aphrases\"D\n" +
+	"\x12TokenizeBatchReply\x12.\n" +
+	"\areplies\x18\x01 \x03(\v2\x14.words.TokenizeReplyR\areplies\"o\n" +
+	"\x0fCacheStatsReply\x12\x12\n" +
+	"\x04hits\x18\x01 \x01(\x03R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x02 \x01(\x03R\x06misses\x12\x1c\n" +
+	"\tevictions\x18\x03 \x01(\x03R\tevictions\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size2\xb7\x02\n" +
 	"\x05Words\x128\n" +
 	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\"\x00\x120\n" +
-	"\x04Norm\x12\x13.words.WordsRequest\x1a\x11.words.WordsReply\"\x00B\x1eZ\x1cyadro.com/course/proto/wordsb\x06proto3"
+	"\x04Norm\x12\x13.words.WordsRequest\x1a\x11.words.WordsReply\"\x00\x127\n" +
+	"\bTokenize\x12\x13.words.WordsRequest\x1a\x14.words.TokenizeReply\"\x00\x12I\n" +
+	"\rTokenizeBatch\x12\x1b.words.TokenizeBatchRequest\x1a\x19.words.TokenizeBatchReply\"\x00\x12>\n" +
+	"\n" +
+	"CacheStats\x12\x16.google.protobuf.Empty\x1a\x16.words.CacheStatsReply\"\x00B\x1eZ\x1cyadro.com/course/proto/wordsb\x06proto3"
 
 var (
 	file_proto_words_words_proto_rawDescOnce sync.Once
@@ -136,22 +487,35 @@ func file_proto_words_words_proto_rawDescGZIP() []byte {
 	return file_proto_words_words_proto_rawDescData
 }
 
-var file_proto_words_words_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_words_words_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_words_words_proto_goTypes = []any{
-	(*WordsRequest)(nil),  // 0: words.WordsRequest
-	(*WordsReply)(nil),    // 1: words.WordsReply
-	(*emptypb.Empty)(nil), // 2: google.protobuf.Empty
+	(*WordsRequest)(nil),         // 0: words.WordsRequest
+	(*WordsReply)(nil),           // 1: words.WordsReply
+	(*Token)(nil),                // 2: words.Token
+	(*TokenizeReply)(nil),        // 3: words.TokenizeReply
+	(*TokenizeBatchRequest)(nil), // 4: words.TokenizeBatchRequest
+	(*TokenizeBatchReply)(nil),   // 5: words.TokenizeBatchReply
+	(*CacheStatsReply)(nil),      // 6: words.CacheStatsReply
+	(*emptypb.Empty)(nil),        // 7: google.protobuf.Empty
 }
 var file_proto_words_words_proto_depIdxs = []int32{
-	2, // 0: words.Words.Ping:input_type -> google.protobuf.Empty
-	0, // 1: words.Words.Norm:input_type -> words.WordsRequest
-	2, // 2: words.Words.Ping:output_type -> google.protobuf.Empty
-	1, // 3: words.Words.Norm:output_type -> words.WordsReply
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: words.TokenizeReply.tokens:type_name -> words.Token
+	3, // 1: words.TokenizeBatchReply.replies:type_name -> words.TokenizeReply
+	7, // 2: words.Words.Ping:input_type -> google.protobuf.Empty
+	0, // 3: words.Words.Norm:input_type -> words.WordsRequest
+	0, // 4: words.Words.Tokenize:input_type -> words.WordsRequest
+	4, // 5: words.Words.TokenizeBatch:input_type -> words.TokenizeBatchRequest
+	7, // 6: words.Words.CacheStats:input_type -> google.protobuf.Empty
+	7, // 7: words.Words.Ping:output_type -> google.protobuf.Empty
+	1, // 8: words.Words.Norm:output_type -> words.WordsReply
+	3, // 9: words.Words.Tokenize:output_type -> words.TokenizeReply
+	5, // 10: words.Words.TokenizeBatch:output_type -> words.TokenizeBatchReply
+	6, // 11: words.Words.CacheStats:output_type -> words.CacheStatsReply
+	7, // [7:12] is the sub-list for method output_type
+	2, // [2:7] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_proto_words_words_proto_init() }
@@ -165,7 +529,7 @@ func file_proto_words_words_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_words_words_proto_rawDesc), len(file_proto_words_words_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,8 +20,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Words_Ping_FullMethodName = "/words.Words/Ping"
-	Words_Norm_FullMethodName = "/words.Words/Norm"
+	Words_Ping_FullMethodName          = "/words.Words/Ping"
+	Words_Norm_FullMethodName          = "/words.Words/Norm"
+	Words_Tokenize_FullMethodName      = "/words.Words/Tokenize"
+	Words_TokenizeBatch_FullMethodName = "/words.Words/TokenizeBatch"
+	Words_CacheStats_FullMethodName    = "/words.Words/CacheStats"
 )
 
 // WordsClient is the client API for Words service.
@@ -30,6 +33,10 @@ const (
 type WordsClient interface {
 	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Norm(ctx context.Context, in *WordsRequest, opts ...grpc.CallOption) (*WordsReply, error)
+	Tokenize(ctx context.Context, in *WordsRequest, opts ...grpc.CallOption) (*TokenizeReply, error)
+	// TokenizeBatch разбивает на слова несколько фраз, ответы в порядке фраз
+	TokenizeBatch(ctx context.Context, in *TokenizeBatchRequest, opts ...grpc.CallOption) (*TokenizeBatchReply, error)
+	CacheStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CacheStatsReply, error)
 }
 
 type wordsClient struct {
@@ -60,12 +67,46 @@ func (c *wordsClient) Norm(ctx context.Context, in *WordsRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *wordsClient) Tokenize(ctx context.Context, in *WordsRequest, opts ...grpc.CallOption) (*TokenizeReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TokenizeReply)
+	err := c.cc.Invoke(ctx, Words_Tokenize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wordsClient) TokenizeBatch(ctx context.Context, in *TokenizeBatchRequest, opts ...grpc.CallOption) (*TokenizeBatchReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TokenizeBatchReply)
+	err := c.cc.Invoke(ctx, Words_TokenizeBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wordsClient) CacheStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CacheStatsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CacheStatsReply)
+	err := c.cc.Invoke(ctx, Words_CacheStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WordsServer is the server API for Words service.
 // All implementations must embed UnimplementedWordsServer
 // for forward compatibility.
 type WordsServer interface {
 	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	Norm(context.Context, *WordsRequest) (*WordsReply, error)
+	Tokenize(context.Context, *WordsRequest) (*TokenizeReply, error)
+	// TokenizeBatch разбивает на слова несколько фраз, ответы в порядке фраз
+	TokenizeBatch(context.Context, *TokenizeBatchRequest) (*TokenizeBatchReply, error)
+	CacheStats(context.Context, *emptypb.Empty) (*CacheStatsReply, error)
 	mustEmbedUnimplementedWordsServer()
 }
 
@@ -82,6 +123,15 @@ func (UnimplementedWordsServer) Ping(context.Context, *emptypb.Empty) (*emptypb.
 func (UnimplementedWordsServer) Norm(context.Context, *WordsRequest) (*WordsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Norm not implemented")
 }
+func (UnimplementedWordsServer) Tokenize(context.Context, *WordsRequest) (*TokenizeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Tokenize not implemented")
+}
+func (UnimplementedWordsServer) TokenizeBatch(context.Context, *TokenizeBatchRequest) (*TokenizeBatchReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TokenizeBatch not implemented")
+}
+func (UnimplementedWordsServer) CacheStats(context.Context, *emptypb.Empty) (*CacheStatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CacheStats not implemented")
+}
 func (UnimplementedWordsServer) mustEmbedUnimplementedWordsServer() {}
 func (UnimplementedWordsServer) testEmbeddedByValue()               {}
 
@@ -139,6 +189,60 @@ func _Words_Norm_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Words_Tokenize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordsServer).Tokenize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Words_Tokenize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordsServer).Tokenize(ctx, req.(*WordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Words_TokenizeBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenizeBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordsServer).TokenizeBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Words_TokenizeBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordsServer).TokenizeBatch(ctx, req.(*TokenizeBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Words_CacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WordsServer).CacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Words_CacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WordsServer).CacheStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Words_ServiceDesc is the grpc.ServiceDesc for Words service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -154,6 +258,18 @@ var Words_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Norm",
 			Handler:    _Words_Norm_Handler,
 		},
+		{
+			MethodName: "Tokenize",
+			Handler:    _Words_Tokenize_Handler,
+		},
+		{
+			MethodName: "TokenizeBatch",
+			Handler:    _Words_TokenizeBatch_Handler,
+		},
+		{
+			MethodName: "CacheStats",
+			Handler:    _Words_CacheStats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/words/words.proto",
@@ -12,8 +12,33 @@ import (
 )
 
 const (
-	getComicsByIds   = `SELECT id, url FROM comics WHERE id = ANY($1)`
-	getAllComicsInfo = `SELECT id, url, words FROM comics`
+	getAllComicsInfo = `
+		SELECT id, url, title, alt, transcript, published, link, news,
+			words, phonetics, terms, title_terms, alt_terms, transcript_terms
+		FROM comics
+	`
+	getComicsInfoByIds = `
+		SELECT id, url, title, alt, transcript, published, link, news,
+			words, phonetics, terms, title_terms, alt_terms, transcript_terms
+		FROM comics
+		WHERE id = ANY($1)
+	`
+	// && использует GIN-индекс на words
+	findComicsInfo = `
+		SELECT id, url, title, alt, transcript, published, link, news,
+			words, phonetics, terms, title_terms, alt_terms, transcript_terms
+		FROM comics
+		WHERE words && $1
+	`
+	getCorpusStats = `
+		SELECT comics_fetched AS docs, terms_total,
+			(SELECT COALESCE(MAX(id), 0) FROM comics) AS max_id
+		FROM comics_stats
+	`
+	getDocumentFrequencies = `
+		SELECT word, (SELECT COUNT(*) FROM comics WHERE words @> ARRAY[word]) AS df
+		FROM unnest($1::text[]) AS word
+	`
 )
 
 type DB struct {
@@ -39,28 +64,86 @@ func (db *DB) Close() {
 	}
 }
 
-func (db *DB) GetComicsByIds(ctx context.Context, ids []int64) ([]core.Comic, error) {
-	var comics []core.Comic
-	if err := db.conn.Select(&comics, getComicsByIds, pq.Array(ids)); err != nil {
-		return nil, fmt.Errorf("failed to select comics by ids from comics table: %w", err)
+func (db *DB) GetAllComicsInfo(ctx context.Context) ([]core.ComicInfo, error) {
+	comics, err := db.selectComicsInfo(ctx, getAllComicsInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select all comic info from comics table: %w", err)
 	}
 	return comics, nil
 }
 
-func (db *DB) GetAllComicsInfo(ctx context.Context) ([]core.ComicInfo, error) {
+func (db *DB) GetComicsInfoByIds(ctx context.Context, ids []int64) ([]core.ComicInfo, error) {
+	comics, err := db.selectComicsInfo(ctx, getComicsInfoByIds, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to select comic info by ids from comics table: %w", err)
+	}
+	return comics, nil
+}
+
+func (db *DB) FindComicsInfo(ctx context.Context, words []string) ([]core.ComicInfo, error) {
+	comics, err := db.selectComicsInfo(ctx, findComicsInfo, pq.Array(words))
+	if err != nil {
+		return nil, fmt.Errorf("failed to select comic info by words from comics table: %w", err)
+	}
+	return comics, nil
+}
+
+func (db *DB) CorpusStats(ctx context.Context, words []string) (core.CorpusStats, error) {
+	var stats struct {
+		Docs       int   `db:"docs"`
+		TermsTotal int   `db:"terms_total"`
+		MaxID      int64 `db:"max_id"`
+	}
+	if err := db.conn.GetContext(ctx, &stats, getCorpusStats); err != nil {
+		return core.CorpusStats{}, fmt.Errorf("failed to select stats from comics_stats table: %w", err)
+	}
+
+	var frequencies []struct {
+		Word string `db:"word"`
+		DF   int    `db:"df"`
+	}
+	if err := db.conn.SelectContext(ctx, &frequencies, getDocumentFrequencies, pq.Array(words)); err != nil {
+		return core.CorpusStats{}, fmt.Errorf("failed to select document frequencies from comics table: %w", err)
+	}
+
+	corpus := core.CorpusStats{
+		Docs:     stats.Docs,
+		TotalLen: stats.TermsTotal,
+		MaxID:    stats.MaxID,
+		DF:       make(map[string]int, len(frequencies)),
+	}
+	for _, f := range frequencies {
+		corpus.DF[f.Word] = f.DF
+	}
+	return corpus, nil
+}
+
+func (db *DB) selectComicsInfo(ctx context.Context, query string, args ...any) ([]core.ComicInfo, error) {
 	var comicsPg []struct {
 		core.Comic
-		Words pq.StringArray `db:"words"`
+		Words     pq.StringArray `db:"words"`
+		Phonetics pq.StringArray `db:"phonetics"`
+		Terms     pq.StringArray `db:"terms"`
+
+		TitleTerms      pq.StringArray `db:"title_terms"`
+		AltTerms        pq.StringArray `db:"alt_terms"`
+		TranscriptTerms pq.StringArray `db:"transcript_terms"`
 	}
-	if err := db.conn.Select(&comicsPg, getAllComicsInfo); err != nil {
-		return nil, fmt.Errorf("failed to select all comic info from comics table: %w", err)
+	if err := db.conn.SelectContext(ctx, &comicsPg, query, args...); err != nil {
+		return nil, err
 	}
 
 	comics := make([]core.ComicInfo, len(comicsPg))
 	for i, info := range comicsPg {
 		comics[i] = core.ComicInfo{
-			Comic: info.Comic,
-			Words: info.Words,
+			Comic:     info.Comic,
+			Words:     info.Words,
+			Phonetics: info.Phonetics,
+			Terms:     info.Terms,
+
+			TitleTerms:      info.TitleTerms,
+			AltTerms:        info.AltTerms,
+			TranscriptTerms: info.TranscriptTerms,
 		}
 	}
 	return comics, nil
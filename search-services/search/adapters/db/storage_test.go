@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"search-service/search/adapters/db"
 	"search-service/search/core"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/mock/gomock"
 )
 
 var (
@@ -89,95 +91,6 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
-func TestGetComicsByIds(t *testing.T) {
-	testCases := []struct {
-		desc           string
-		requestedIds   []int64
-		prepare        func(t *testing.T)
-		cleanup        func(t *testing.T)
-		expectedComics []core.Comic
-		wantErr        bool
-	}{
-		{
-			desc:         "success - returns multiple comics",
-			requestedIds: []int64{1, 2, 3},
-			prepare: func(t *testing.T) {
-				_, err := conn.Exec(`
-					INSERT INTO comics (id, url, words) VALUES 
-					(1, 'http://example.com/1', ARRAY['test', 'comic']),
-					(2, 'http://example.com/2', ARRAY['another', 'test']),
-					(3, 'http://example.com/3', ARRAY['third', 'comic'])
-				`)
-				require.NoError(t, err)
-			},
-			cleanup: func(t *testing.T) { teardown(t, "comics") },
-			expectedComics: []core.Comic{
-				{ID: 1, URL: "http://example.com/1"},
-				{ID: 2, URL: "http://example.com/2"},
-				{ID: 3, URL: "http://example.com/3"},
-			},
-			wantErr: false,
-		},
-		{
-			desc:         "success - returns single comic",
-			requestedIds: []int64{1},
-			prepare: func(t *testing.T) {
-				_, err := conn.Exec(`
-					INSERT INTO comics (id, url, words) VALUES 
-					(1, 'http://example.com/1', ARRAY['test'])
-				`)
-				require.NoError(t, err)
-			},
-			cleanup:        func(t *testing.T) { teardown(t, "comics") },
-			expectedComics: []core.Comic{{ID: 1, URL: "http://example.com/1"}},
-			wantErr:        false,
-		},
-		{
-			desc: "success - empty ids returns empty result",
-			prepare: func(t *testing.T) {
-				_, err := conn.Exec(`
-					INSERT INTO comics (id, url, words) VALUES 
-					(1, 'http://example.com/1', ARRAY['test'])
-				`)
-				require.NoError(t, err)
-			},
-			cleanup:        func(t *testing.T) { teardown(t, "comics") },
-			expectedComics: []core.Comic{},
-			wantErr:        false,
-		},
-		{
-			desc:         "success - non-existent ids returns empty",
-			requestedIds: []int64{3},
-			prepare: func(t *testing.T) {
-				_, err := conn.Exec(`
-					INSERT INTO comics (id, url, words) VALUES 
-					(1, 'http://example.com/1', ARRAY['test'])
-				`)
-				require.NoError(t, err)
-			},
-			cleanup:        func(t *testing.T) { teardown(t, "comics") },
-			expectedComics: []core.Comic{},
-			wantErr:        false,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.desc, func(t *testing.T) {
-			tc.prepare(t)
-			defer tc.cleanup(t)
-
-			comics, err := testDB.GetComicsByIds(context.TODO(), tc.requestedIds)
-
-			if tc.wantErr {
-				require.Error(t, err)
-			} else {
-				require.NoError(t, err)
-				require.ElementsMatch(t, tc.expectedComics, comics)
-			}
-		})
-	}
-}
-
 func TestGetAllComicsInfo(t *testing.T) {
 	testCases := []struct {
 		desc               string
@@ -229,7 +142,165 @@ func TestGetAllComicsInfo(t *testing.T) {
 	}
 }
 
-func teardown(t *testing.T, table string) {
-	_, err := conn.Exec(fmt.Sprintf("TRUNCATE %s", table))
+func TestGetComicsInfoByIds(t *testing.T) {
+	_, err := conn.Exec(`
+		INSERT INTO comics (id, url, words, phonetics) VALUES 
+		(1, 'http://example.com/1', ARRAY['test'], ARRAY['TST']),
+		(2, 'http://example.com/2', ARRAY['another'], ARRAY['ANR'])
+	`)
+	require.NoError(t, err)
+	defer teardown(t, "comics")
+
+	comicsInfo, err := testDB.GetComicsInfoByIds(context.TODO(), []int64{2, 3})
+	require.NoError(t, err)
+	require.Equal(t, []core.ComicInfo{{
+		Comic:     core.Comic{ID: 2, URL: "http://example.com/2"},
+		Words:     []string{"another"},
+		Phonetics: []string{"ANR"},
+	}}, comicsInfo)
+}
+
+func TestFindComicsInfo(t *testing.T) {
+	_, err := conn.Exec(`
+		INSERT INTO comics (id, url, words) VALUES 
+		(1, 'http://example.com/1', ARRAY['test', 'comic']),
+		(2, 'http://example.com/2', ARRAY['another']),
+		(3, 'http://example.com/3', ARRAY['third', 'comic'])
+	`)
+	require.NoError(t, err)
+	defer teardown(t, "comics")
+
+	testCases := []struct {
+		desc        string
+		words       []string
+		expectedIDs []int64
+	}{
+		{desc: "single word", words: []string{"comic"}, expectedIDs: []int64{1, 3}},
+		{desc: "any of words", words: []string{"test", "another"}, expectedIDs: []int64{1, 2}},
+		{desc: "unknown word", words: []string{"unknown"}, expectedIDs: []int64{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			comicsInfo, err := testDB.FindComicsInfo(context.TODO(), tc.words)
+			require.NoError(t, err)
+			ids := make([]int64, len(comicsInfo))
+			for i, info := range comicsInfo {
+				ids[i] = info.ID
+			}
+			require.ElementsMatch(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestCorpusStats(t *testing.T) {
+	_, err := conn.Exec(`
+		INSERT INTO comics (id, url, words, terms) VALUES 
+		(1, 'http://example.com/1', ARRAY['test', 'comic'], ARRAY['test', 'comic', 'test']),
+		(5, 'http://example.com/5', ARRAY['comic'], NULL)
+	`)
+	require.NoError(t, err)
+	_, err = conn.Exec("UPDATE comics_stats SET comics_fetched = 2, terms_total = 4")
+	require.NoError(t, err)
+	defer teardown(t, "comics")
+	defer teardown(t, "comics_stats")
+
+	stats, err := testDB.CorpusStats(context.TODO(), []string{"comic", "test", "unknown"})
 	require.NoError(t, err)
+	require.Equal(t, core.CorpusStats{
+		Docs:     2,
+		TotalLen: 4,
+		MaxID:    5,
+		DF:       map[string]int{"comic": 2, "test": 1, "unknown": 0},
+	}, stats)
+}
+
+// BenchmarkSearchCandidates сравнивает полный просмотр таблицы с выборкой
+// кандидатов по GIN-индексу на words. Поиск с опечаткой ходит в базу только за
+// кандидатами: словарь для исправления берется из версии индекса.
+func BenchmarkSearchCandidates(b *testing.B) {
+	const comics, vocabulary, wordsPerComic = 3000, 5000, 40
+	_, err := conn.Exec(`
+		INSERT INTO comics (id, url, title, words, terms)
+		SELECT id, 'http://example.com/' || id, array_to_string(words, ' '), words, words
+		FROM (
+			SELECT id, ARRAY(
+				SELECT 'word' || (random() * $2)::int FROM generate_series(1, $3) WHERE id > 0
+			) AS words
+			FROM generate_series(1, $1) AS id
+		) t
+	`, comics, vocabulary, wordsPerComic)
+	require.NoError(b, err)
+	_, err = conn.Exec("ANALYZE comics")
+	require.NoError(b, err)
+	defer func() {
+		_, err := conn.Exec("TRUNCATE comics")
+		require.NoError(b, err)
+	}()
+
+	words := []string{"word1", "word42"}
+	b.Run("full scan", func(b *testing.B) {
+		for range b.N {
+			_, err := testDB.GetAllComicsInfo(context.TODO())
+			require.NoError(b, err)
+		}
+	})
+	b.Run("gin index", func(b *testing.B) {
+		for range b.N {
+			_, err := testDB.FindComicsInfo(context.TODO(), words)
+			require.NoError(b, err)
+			_, err = testDB.CorpusStats(context.TODO(), words)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("typo correction", func(b *testing.B) {
+		service, err := core.NewService(slog.Default(), testDB, spaceWords(gomock.NewController(b)), nil,
+			core.Options{FuzzyMinHits: 1})
+		require.NoError(b, err)
+		require.NoError(b, service.UpdateIndex(context.TODO()))
+
+		// без исправления ничего не находится; кэш результатов выключен
+		req := core.SearchRequest{Phrase: "wordd42", Limit: 10}
+		result, err := service.Search(context.TODO(), req)
+		require.NoError(b, err)
+		require.NotEmpty(b, result.CorrectedQuery)
+
+		b.ResetTimer()
+		for range b.N {
+			_, err := service.Search(context.TODO(), req)
+			require.NoError(b, err)
+		}
+	})
+}
+
+// spaceWords делит тексты на слова по пробелам, основа - само слово.
+func spaceWords(ctrl *gomock.Controller) *core.MockWords {
+	mockWords := core.NewMockWords(ctrl)
+	norm := func(_ context.Context, phrase string) ([]string, error) {
+		return strings.Fields(phrase), nil
+	}
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(norm).AnyTimes()
+	mockWords.EXPECT().Terms(gomock.Any(), gomock.Any()).DoAndReturn(norm).AnyTimes()
+	mockWords.EXPECT().TokenizeAll(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, texts []string) ([][]core.Token, error) {
+			tokens := make([][]core.Token, len(texts))
+			for i, text := range texts {
+				for _, word := range strings.Fields(text) {
+					tokens[i] = append(tokens[i], core.Token{Text: word, Stem: word})
+				}
+			}
+			return tokens, nil
+		}).AnyTimes()
+	return mockWords
+}
+
+func teardown(t *testing.T, table string) {
+	switch table {
+	case "comics_stats":
+		_, err := conn.Exec("UPDATE comics_stats SET comics_fetched = 0, words_total = 0, words_unique = 0, terms_total = 0")
+		require.NoError(t, err)
+	default:
+		_, err := conn.Exec(fmt.Sprintf("TRUNCATE %s", table))
+		require.NoError(t, err)
+	}
 }
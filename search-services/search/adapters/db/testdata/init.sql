@@ -1,5 +1,28 @@
 CREATE TABLE IF NOT EXISTS comics (
     id BIGINT PRIMARY KEY,
     url TEXT NOT NULL,
-    words TEXT[]
+    words TEXT[],
+    phonetics TEXT[],
+    terms TEXT[],
+    title_terms TEXT[],
+    alt_terms TEXT[],
+    transcript_terms TEXT[],
+    title TEXT NOT NULL DEFAULT '',
+    alt TEXT NOT NULL DEFAULT '',
+    transcript TEXT NOT NULL DEFAULT '',
+    published DATE,
+    link TEXT NOT NULL DEFAULT '',
+    news TEXT NOT NULL DEFAULT ''
 );
+
+CREATE INDEX IF NOT EXISTS comics_words_idx ON comics USING GIN (words);
+
+CREATE TABLE IF NOT EXISTS comics_stats (
+    comics_fetched BIGINT,
+    words_total BIGINT,
+    words_unique BIGINT,
+    terms_total BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO comics_stats (comics_fetched, words_total, words_unique, terms_total)
+VALUES (0, 0, 0, 0);
@@ -5,9 +5,15 @@ import (
 	"errors"
 	searchpb "search-service/proto/search"
 	"search-service/search/core"
+	"strconv"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -25,33 +31,263 @@ func (s *Server) Ping(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, erro
 }
 
 func (s *Server) Search(in *searchpb.SearchRequest, stream searchpb.Search_SearchServer) error {
-	reply, err := s.service.Search(stream.Context(), in.GetPhrase(), in.GetLimit())
+	result, err := s.service.Search(stream.Context(), makeRequest(in))
+	if err != nil {
+		return makeError(err)
+	}
+	return sendResult(stream, result)
+}
+
+func (s *Server) ISearch(in *searchpb.SearchRequest, stream searchpb.Search_ISearchServer) error {
+	result, err := s.service.ISearch(stream.Context(), makeRequest(in))
+	if err != nil {
+		return makeError(err)
+	}
+	return sendResult(stream, result)
+}
+
+// sendResult передает сводку по выдаче в заголовке, а комиксы - потоком.
+func sendResult(stream grpc.ServerStreamingServer[searchpb.Comic], result core.SearchResult) error {
+	summary, err := proto.Marshal(makeSummary(result))
 	if err != nil {
-		if errors.Is(err, core.ErrBadArguments) {
-			return status.Error(codes.InvalidArgument, err.Error())
-		}
 		return status.Error(codes.Internal, err.Error())
 	}
-	for _, comic := range reply {
-		if err := stream.Send(&searchpb.SearchReply{Id: comic.ID, Url: comic.URL}); err != nil {
+	if err := stream.SendHeader(metadata.Pairs(searchpb.HeaderSummary, string(summary))); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	for _, comic := range result.Comics {
+		if err := stream.Send(makeComic(comic)); err != nil {
 			return status.Error(codes.Internal, err.Error())
 		}
 	}
 	return nil
 }
 
-func (s *Server) ISearch(in *searchpb.SearchRequest, stream searchpb.Search_SearchServer) error {
-	reply, err := s.service.ISearch(stream.Context(), in.GetPhrase(), in.GetLimit())
+func makeSummary(result core.SearchResult) *searchpb.SearchSummary {
+	summary := &searchpb.SearchSummary{
+		Ranker:           result.Ranker,
+		TotalHits:        result.TotalHits,
+		CorrectedQuery:   result.CorrectedQuery,
+		DidYouMean:       result.DidYouMean,
+		IndexGeneration:  result.IndexGeneration,
+		YearFacets:       make([]*searchpb.YearFacet, len(result.Years)),
+		HighlightPreTag:  result.HighlightPreTag,
+		HighlightPostTag: result.HighlightPostTag,
+	}
+	for i, facet := range result.Years {
+		summary.YearFacets[i] = &searchpb.YearFacet{Year: int32(facet.Year), Count: facet.Count}
+	}
+	return summary
+}
+
+func (s *Server) Similar(ctx context.Context, in *searchpb.SimilarRequest) (*searchpb.SimilarReply, error) {
+	result, err := s.service.Similar(ctx, in.GetId(), in.GetLimit())
+	if err != nil {
+		return nil, makeError(err)
+	}
+	return makeSimilarReply(result), nil
+}
+
+func (s *Server) GetComic(ctx context.Context, in *searchpb.ComicRequest) (*searchpb.ComicReply, error) {
+	detail, err := s.service.GetComic(ctx, in.GetId())
+	if err != nil {
+		return nil, makeError(err)
+	}
+	return makeComicReply(detail), nil
+}
+
+func (s *Server) GetComics(ctx context.Context, in *searchpb.ComicsRequest) (*searchpb.ComicsReply, error) {
+	comics, err := s.service.GetComics(ctx, in.GetIds())
+	if err != nil {
+		return nil, makeError(err)
+	}
+	reply := &searchpb.ComicsReply{Comics: make([]*searchpb.Comic, len(comics))}
+	for i, comic := range comics {
+		reply.Comics[i] = makeStoredComic(comic)
+	}
+	return reply, nil
+}
+
+func (s *Server) RandomComic(ctx context.Context, _ *emptypb.Empty) (*searchpb.ComicReply, error) {
+	detail, err := s.service.RandomComic(ctx)
+	if err != nil {
+		return nil, makeError(err)
+	}
+	return makeComicReply(detail), nil
+}
+
+func (s *Server) Suggest(ctx context.Context, in *searchpb.SuggestRequest) (*searchpb.SuggestReply, error) {
+	suggestions, err := s.service.Suggest(ctx, in.GetPrefix(), in.GetLimit())
 	if err != nil {
-		if errors.Is(err, core.ErrBadArguments) {
-			return status.Error(codes.InvalidArgument, err.Error())
+		return nil, makeError(err)
+	}
+	reply := &searchpb.SuggestReply{
+		Suggestions: make([]*searchpb.Suggestion, len(suggestions)),
+	}
+	for i, suggestion := range suggestions {
+		reply.Suggestions[i] = &searchpb.Suggestion{Text: suggestion.Text, Count: suggestion.Count}
+	}
+	return reply, nil
+}
+
+func (s *Server) Status(ctx context.Context, _ *emptypb.Empty) (*searchpb.StatusReply, error) {
+	indexStatus := s.service.IndexStatus(ctx)
+	reply := &searchpb.StatusReply{
+		IndexGeneration: indexStatus.Generation,
+		IndexSource:     string(indexStatus.Source),
+		IndexComics:     int64(indexStatus.Comics),
+	}
+	if !indexStatus.UpdatedAt.IsZero() {
+		reply.IndexUpdatedAt = indexStatus.UpdatedAt.Unix()
+		reply.IndexAgeSeconds = int64(time.Since(indexStatus.UpdatedAt).Seconds())
+	}
+	return reply, nil
+}
+
+func (s *Server) IndexStats(ctx context.Context, _ *emptypb.Empty) (*searchpb.IndexStatsReply, error) {
+	stats := s.service.IndexStats(ctx)
+	return &searchpb.IndexStatsReply{
+		Terms:           int64(stats.Terms),
+		Postings:        int64(stats.Postings),
+		Bytes:           int64(stats.Bytes),
+		BuildDurationMs: stats.BuildDuration.Milliseconds(),
+		BuiltAt:         stats.BuiltAt.Unix(),
+	}, nil
+}
+
+func (s *Server) CacheStats(ctx context.Context, _ *emptypb.Empty) (*searchpb.CacheStatsReply, error) {
+	stats := s.service.CacheStats(ctx)
+	return &searchpb.CacheStatsReply{
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		Size:      stats.Size,
+		HitRatio:  stats.HitRatio(),
+	}, nil
+}
+
+func makeRequest(in *searchpb.SearchRequest) core.SearchRequest {
+	return core.SearchRequest{
+		Phrase:   in.GetPhrase(),
+		Limit:    in.GetLimit(),
+		Offset:   in.GetOffset(),
+		ClientID: in.GetClientId(),
+		Explain:  in.GetExplain(),
+		Filters: core.Filters{
+			YearFrom: int(in.GetYearFrom()),
+			YearTo:   int(in.GetYearTo()),
+			IDFrom:   in.GetIdFrom(),
+			IDTo:     in.GetIdTo(),
+			Link:     in.GetLink(),
+			News:     in.GetNews(),
+		},
+		Sort: core.SortOrder(in.GetSort()),
+	}
+}
+
+func makeSimilarReply(result core.SearchResult) *searchpb.SimilarReply {
+	reply := &searchpb.SimilarReply{
+		Comics:          make([]*searchpb.Comic, len(result.Comics)),
+		Ranker:          result.Ranker,
+		TotalHits:       result.TotalHits,
+		CorrectedQuery:  result.CorrectedQuery,
+		DidYouMean:      result.DidYouMean,
+		IndexGeneration: result.IndexGeneration,
+		YearFacets:      make([]*searchpb.YearFacet, len(result.Years)),
+	}
+	for i, comic := range result.Comics {
+		reply.Comics[i] = makeComic(comic)
+	}
+	for i, facet := range result.Years {
+		reply.YearFacets[i] = &searchpb.YearFacet{Year: int32(facet.Year), Count: facet.Count}
+	}
+	return reply
+}
+
+func makeComic(comic core.Comic) *searchpb.Comic {
+	pb := &searchpb.Comic{
+		Id:           comic.ID,
+		Url:          comic.URL,
+		Title:        comic.Title,
+		Matches:      make([]*searchpb.FieldMatch, len(comic.Matches)),
+		Score:        comic.Score,
+		MatchedTerms: comic.MatchedTerms,
+		Explanation:  makeExplanation(comic.Explanation),
+		Link:         comic.Link,
+		News:         comic.News,
+	}
+	if comic.Published != nil {
+		pb.Published = comic.Published.Format(time.DateOnly)
+	}
+	for i, match := range comic.Matches {
+		pb.Matches[i] = &searchpb.FieldMatch{Field: match.Field, Terms: match.Terms, Snippet: match.Snippet}
+	}
+	return pb
+}
+
+func makeComicReply(detail core.ComicDetail) *searchpb.ComicReply {
+	return &searchpb.ComicReply{
+		Comic:  makeStoredComic(detail.Comic),
+		PrevId: detail.PrevID,
+		NextId: detail.NextID,
+	}
+}
+
+// makeStoredComic передает все сохраненные поля комикса. В результатах поиска
+// alt и transcript не передаются: вместо них есть фрагменты с подсветкой.
+func makeStoredComic(comic core.Comic) *searchpb.Comic {
+	pb := makeComic(comic)
+	pb.Alt = comic.Alt
+	pb.Transcript = comic.Transcript
+	return pb
+}
+
+func makeExplanation(explanation *core.Explanation) *searchpb.Explanation {
+	if explanation == nil {
+		return nil
+	}
+	pb := &searchpb.Explanation{
+		Score:    explanation.Score,
+		Terms:    make([]*searchpb.TermScore, len(explanation.Terms)),
+		Boost:    explanation.Boost,
+		Bonus:    explanation.Bonus,
+		Phonetic: explanation.Phonetic,
+	}
+	for i, term := range explanation.Terms {
+		pb.Terms[i] = &searchpb.TermScore{
+			Term:        term.Term,
+			Tf:          int64(term.TF),
+			Df:          int64(term.DF),
+			Idf:         term.IDF,
+			FieldWeight: term.FieldWeight,
+			Score:       term.Score,
 		}
-		return status.Error(codes.Internal, err.Error())
 	}
-	for _, comic := range reply {
-		if err := stream.Send(&searchpb.SearchReply{Id: comic.ID, Url: comic.URL}); err != nil {
-			return status.Error(codes.Internal, err.Error())
+	return pb
+}
+
+// makeError передает позицию синтаксической ошибки запроса в ErrorInfo,
+// чтобы клиент мог показать ее пользователю.
+func makeError(err error) error {
+	var queryErr *core.QueryError
+	if errors.As(err, &queryErr) {
+		st, detailsErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&errdetails.ErrorInfo{
+			Reason: searchpb.ReasonQuerySyntax,
+			Domain: searchpb.ErrorDomain,
+			Metadata: map[string]string{
+				searchpb.MetadataPosition: strconv.Itoa(queryErr.Pos),
+				searchpb.MetadataMessage:  queryErr.Msg,
+			},
+		})
+		if detailsErr == nil {
+			return st.Err()
 		}
 	}
-	return nil
+	if errors.Is(err, core.ErrBadArguments) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, core.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
 }
@@ -7,26 +7,80 @@ import (
 	"search-service/search/adapters/grpc"
 	"search-service/search/core"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	googlegrpc "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-type mockSearchStream struct {
-	searchpb.Search_SearchServer
-	sent []*searchpb.SearchReply
+// searchStream запоминает заголовок и комиксы потокового ответа.
+type searchStream struct {
+	googlegrpc.ServerStream
+	header metadata.MD
+	comics []*searchpb.Comic
 }
 
-func (m *mockSearchStream) Send(reply *searchpb.SearchReply) error {
-	m.sent = append(m.sent, reply)
+func (s *searchStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *searchStream) SendHeader(md metadata.MD) error {
+	s.header = md
 	return nil
 }
 
-func (m *mockSearchStream) Context() context.Context {
-	return context.Background()
+func (s *searchStream) Send(comic *searchpb.Comic) error {
+	s.comics = append(s.comics, comic)
+	return nil
+}
+
+type searchMethod func(*searchpb.SearchRequest, googlegrpc.ServerStreamingServer[searchpb.Comic]) error
+
+// searchReply - ответ Search или ISearch: сводка из заголовка и комиксы из потока.
+type searchReply struct {
+	*searchpb.SearchSummary
+	comics []*searchpb.Comic
+}
+
+func (r *searchReply) GetComics() []*searchpb.Comic {
+	return r.comics
+}
+
+// collect вызывает Search или ISearch и собирает ответ так же, как клиент:
+// сводку из заголовка, комиксы из потока.
+func collect(method searchMethod, in *searchpb.SearchRequest) (*searchReply, error) {
+	stream := &searchStream{}
+	if err := method(in, stream); err != nil {
+		return nil, err
+	}
+	values := stream.header.Get(searchpb.HeaderSummary)
+	if len(values) != 1 {
+		return nil, errors.New("summary header is missing")
+	}
+	var summary searchpb.SearchSummary
+	if err := proto.Unmarshal([]byte(values[0]), &summary); err != nil {
+		return nil, err
+	}
+	return &searchReply{SearchSummary: &summary, comics: stream.comics}, nil
+}
+
+// TestSearchLegacyClient проверяет, что клиент со старым SearchReply
+// читает комиксы потока.
+func TestSearchLegacyClient(t *testing.T) {
+	comic, err := proto.Marshal(&searchpb.Comic{Id: 42, Url: "http://example.com/42", Title: "Answer", Score: 1.5})
+	require.NoError(t, err)
+
+	var legacy searchpb.SearchReply
+	require.NoError(t, proto.Unmarshal(comic, &legacy))
+	require.Equal(t, int64(42), legacy.GetId())
+	require.Equal(t, "http://example.com/42", legacy.GetUrl())
 }
 
 func TestPing(t *testing.T) {
@@ -56,7 +110,9 @@ func TestSearch(t *testing.T) {
 			phrase: "test",
 			limit:  10,
 			serviceResult: []core.Comic{
-				{ID: 1, URL: "http://example.com/1"},
+				{ID: 1, URL: "http://example.com/1", Title: "Test", Matches: []core.FieldMatch{
+					{Field: core.FieldTitle, Terms: []string{"test"}, Snippet: "<mark>Test</mark>"},
+				}},
 				{ID: 2, URL: "http://example.com/2"},
 			},
 			expectedSent: 2,
@@ -94,22 +150,32 @@ func TestSearch(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockSearcher := core.NewMockSearcher(ctrl)
-			mockSearcher.EXPECT().Search(gomock.Any(), tc.phrase, tc.limit).Return(tc.serviceResult, tc.serviceError)
+			mockSearcher.EXPECT().Search(gomock.Any(), core.SearchRequest{Phrase: tc.phrase, Limit: tc.limit, Offset: 5, ClientID: "client"}).
+				Return(core.SearchResult{Comics: tc.serviceResult, TotalHits: 42, Ranker: core.RankerBM25, DidYouMean: "linux"}, tc.serviceError)
 
 			server := grpc.NewServer(mockSearcher)
-			stream := &mockSearchStream{}
 
-			err := server.Search(&searchpb.SearchRequest{Phrase: tc.phrase, Limit: tc.limit}, stream)
+			reply, err := collect(server.Search, &searchpb.SearchRequest{Phrase: tc.phrase, Limit: tc.limit, Offset: 5, ClientId: "client"})
 
 			if tc.wantErr {
 				require.Error(t, err)
 				require.Equal(t, tc.expectedCode, status.Code(err))
 			} else {
 				require.NoError(t, err)
-				require.Len(t, stream.sent, tc.expectedSent)
+				require.Equal(t, core.RankerBM25, reply.GetRanker())
+				require.Equal(t, int64(42), reply.GetTotalHits())
+				require.Equal(t, "linux", reply.GetDidYouMean())
+				require.Len(t, reply.GetComics(), tc.expectedSent)
 				for i, comic := range tc.serviceResult {
-					require.Equal(t, comic.ID, stream.sent[i].Id)
-					require.Equal(t, comic.URL, stream.sent[i].Url)
+					require.Equal(t, comic.ID, reply.GetComics()[i].GetId())
+					require.Equal(t, comic.URL, reply.GetComics()[i].GetUrl())
+					require.Equal(t, comic.Title, reply.GetComics()[i].GetTitle())
+					require.Len(t, reply.GetComics()[i].GetMatches(), len(comic.Matches))
+					for j, match := range comic.Matches {
+						require.Equal(t, match.Field, reply.GetComics()[i].GetMatches()[j].GetField())
+						require.Equal(t, match.Terms, reply.GetComics()[i].GetMatches()[j].GetTerms())
+						require.Equal(t, match.Snippet, reply.GetComics()[i].GetMatches()[j].GetSnippet())
+					}
 				}
 			}
 		})
@@ -170,24 +236,364 @@ func TestISearch(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockSearcher := core.NewMockSearcher(ctrl)
-			mockSearcher.EXPECT().ISearch(gomock.Any(), tc.phrase, tc.limit).Return(tc.serviceResult, tc.serviceError)
+			mockSearcher.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: tc.phrase, Limit: tc.limit, Offset: 5, ClientID: "client"}).
+				Return(core.SearchResult{Comics: tc.serviceResult, TotalHits: 42, Ranker: core.RankerBM25, IndexGeneration: 7}, tc.serviceError)
 
 			server := grpc.NewServer(mockSearcher)
-			stream := &mockSearchStream{}
 
-			err := server.ISearch(&searchpb.SearchRequest{Phrase: tc.phrase, Limit: tc.limit}, stream)
+			reply, err := collect(server.ISearch, &searchpb.SearchRequest{Phrase: tc.phrase, Limit: tc.limit, Offset: 5, ClientId: "client"})
 
 			if tc.wantErr {
 				require.Error(t, err)
 				require.Equal(t, tc.expectedCode, status.Code(err))
 			} else {
 				require.NoError(t, err)
-				require.Len(t, stream.sent, tc.expectedSent)
+				require.Equal(t, core.RankerBM25, reply.GetRanker())
+				require.Equal(t, int64(42), reply.GetTotalHits())
+				require.Equal(t, uint64(7), reply.GetIndexGeneration())
+				require.Len(t, reply.GetComics(), tc.expectedSent)
 				for i, comic := range tc.serviceResult {
-					require.Equal(t, comic.ID, stream.sent[i].Id)
-					require.Equal(t, comic.URL, stream.sent[i].Url)
+					require.Equal(t, comic.ID, reply.GetComics()[i].GetId())
+					require.Equal(t, comic.URL, reply.GetComics()[i].GetUrl())
 				}
 			}
 		})
 	}
 }
+
+func TestSearchQueryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().ISearch(gomock.Any(), gomock.Any()).
+		Return(core.SearchResult{}, &core.QueryError{Pos: 6, Msg: "missing operand after AND"})
+
+	server := grpc.NewServer(mockSearcher)
+	_, err := collect(server.ISearch, &searchpb.SearchRequest{Phrase: "linux AND", Limit: 10})
+
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, searchpb.ReasonQuerySyntax, info.GetReason())
+	require.Equal(t, "6", info.GetMetadata()[searchpb.MetadataPosition])
+	require.Equal(t, "missing operand after AND", info.GetMetadata()[searchpb.MetadataMessage])
+}
+
+func TestSearchExplain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().ISearch(gomock.Any(), core.SearchRequest{Phrase: "robot", Limit: 10, Explain: true}).
+		Return(core.SearchResult{Comics: []core.Comic{{
+			ID:           1,
+			Score:        2.5,
+			MatchedTerms: []string{"robot"},
+			Explanation: &core.Explanation{
+				Score: 2.5,
+				Terms: []core.TermScore{{Term: "robot", TF: 2, DF: 1, IDF: 1.5, FieldWeight: 1, Score: 2}},
+				Boost: 1,
+				Bonus: 0.5,
+			},
+		}}}, nil)
+
+	server := grpc.NewServer(mockSearcher)
+	reply, err := collect(server.ISearch, &searchpb.SearchRequest{Phrase: "robot", Limit: 10, Explain: true})
+	require.NoError(t, err)
+
+	comic := reply.GetComics()[0]
+	require.Equal(t, 2.5, comic.GetScore())
+	require.Equal(t, []string{"robot"}, comic.GetMatchedTerms())
+	require.Equal(t, 0.5, comic.GetExplanation().GetBonus())
+	require.Len(t, comic.GetExplanation().GetTerms(), 1)
+	term := comic.GetExplanation().GetTerms()[0]
+	require.Equal(t, "robot", term.GetTerm())
+	require.Equal(t, int64(2), term.GetTf())
+	require.Equal(t, int64(1), term.GetDf())
+	require.Equal(t, 1.5, term.GetIdf())
+	require.Equal(t, 2.0, term.GetScore())
+}
+
+func TestSearchFiltersAndSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	published := time.Date(2010, time.May, 3, 0, 0, 0, 0, time.UTC)
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().ISearch(gomock.Any(), core.SearchRequest{
+		Phrase:  "linux",
+		Limit:   10,
+		Filters: core.Filters{YearFrom: 2008, YearTo: 2012, IDFrom: 100, IDTo: 900, Link: true},
+		Sort:    core.SortDate,
+	}).Return(core.SearchResult{
+		Comics: []core.Comic{
+			{ID: 353, Published: &published, Link: "http://example.com/large"},
+			{ID: 354},
+		},
+		Years: []core.YearFacet{{Year: 2007, Count: 3}, {Year: 2010, Count: 1}},
+	}, nil)
+
+	server := grpc.NewServer(mockSearcher)
+	reply, err := collect(server.ISearch, &searchpb.SearchRequest{
+		Phrase: "linux", Limit: 10, YearFrom: 2008, YearTo: 2012, IdFrom: 100, IdTo: 900, Link: true,
+		Sort: "date",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2010-05-03", reply.GetComics()[0].GetPublished())
+	require.Equal(t, "http://example.com/large", reply.GetComics()[0].GetLink())
+	require.Empty(t, reply.GetComics()[1].GetPublished())
+	require.Len(t, reply.GetYearFacets(), 2)
+	require.Equal(t, int32(2007), reply.GetYearFacets()[0].GetYear())
+	require.Equal(t, int64(3), reply.GetYearFacets()[0].GetCount())
+	require.Equal(t, int32(2010), reply.GetYearFacets()[1].GetYear())
+}
+
+func TestSimilar(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		result       core.SearchResult
+		serviceError error
+		expectedCode codes.Code
+	}{
+		{
+			desc: "success - returns similar comics",
+			result: core.SearchResult{
+				Comics:    []core.Comic{{ID: 2, URL: "http://example.com/2", Score: 0.8, MatchedTerms: []string{"linux"}}},
+				TotalHits: 5,
+			},
+		},
+		{
+			desc:         "error - comic not found",
+			serviceError: core.ErrNotFound,
+			expectedCode: codes.NotFound,
+		},
+		{
+			desc:         "error - bad arguments",
+			serviceError: core.ErrBadArguments,
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			mockSearcher.EXPECT().Similar(gomock.Any(), int64(1), int64(3)).Return(tc.result, tc.serviceError)
+
+			server := grpc.NewServer(mockSearcher)
+			reply, err := server.Similar(context.Background(), &searchpb.SimilarRequest{Id: 1, Limit: 3})
+
+			if tc.serviceError != nil {
+				require.Equal(t, tc.expectedCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.result.TotalHits, reply.GetTotalHits())
+			require.Len(t, reply.GetComics(), len(tc.result.Comics))
+			for i, comic := range tc.result.Comics {
+				require.Equal(t, comic.ID, reply.GetComics()[i].GetId())
+				require.Equal(t, comic.Score, reply.GetComics()[i].GetScore())
+				require.Equal(t, comic.MatchedTerms, reply.GetComics()[i].GetMatchedTerms())
+			}
+		})
+	}
+}
+
+func TestGetComic(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		detail       core.ComicDetail
+		serviceError error
+		expectedCode codes.Code
+	}{
+		{
+			desc: "success - returns comic with neighbours",
+			detail: core.ComicDetail{
+				Comic:  core.Comic{ID: 2, URL: "http://example.com/2", Title: "Cat", Alt: "meow", Transcript: "[[a cat]]"},
+				PrevID: 1,
+				NextID: 3,
+			},
+		},
+		{
+			desc:         "error - comic not found",
+			serviceError: core.ErrNotFound,
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			mockSearcher.EXPECT().GetComic(gomock.Any(), int64(2)).Return(tc.detail, tc.serviceError)
+
+			server := grpc.NewServer(mockSearcher)
+			reply, err := server.GetComic(context.Background(), &searchpb.ComicRequest{Id: 2})
+
+			if tc.serviceError != nil {
+				require.Equal(t, tc.expectedCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.detail.ID, reply.GetComic().GetId())
+			require.Equal(t, tc.detail.URL, reply.GetComic().GetUrl())
+			require.Equal(t, tc.detail.Title, reply.GetComic().GetTitle())
+			require.Equal(t, tc.detail.Alt, reply.GetComic().GetAlt())
+			require.Equal(t, tc.detail.Transcript, reply.GetComic().GetTranscript())
+			require.Equal(t, tc.detail.PrevID, reply.GetPrevId())
+			require.Equal(t, tc.detail.NextID, reply.GetNextId())
+		})
+	}
+}
+
+func TestGetComics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	comics := []core.Comic{{ID: 3, URL: "http://example.com/3", Alt: "alt"}, {ID: 1, URL: "http://example.com/1"}}
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().GetComics(gomock.Any(), []int64{3, 1}).Return(comics, nil)
+
+	server := grpc.NewServer(mockSearcher)
+	reply, err := server.GetComics(context.Background(), &searchpb.ComicsRequest{Ids: []int64{3, 1}})
+	require.NoError(t, err)
+	require.Len(t, reply.GetComics(), 2)
+	for i, comic := range comics {
+		require.Equal(t, comic.ID, reply.GetComics()[i].GetId())
+		require.Equal(t, comic.Alt, reply.GetComics()[i].GetAlt())
+	}
+}
+
+func TestRandomComic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := core.NewMockSearcher(ctrl)
+	gomock.InOrder(
+		mockSearcher.EXPECT().RandomComic(gomock.Any()).Return(core.ComicDetail{Comic: core.Comic{ID: 7}, PrevID: 6}, nil),
+		mockSearcher.EXPECT().RandomComic(gomock.Any()).Return(core.ComicDetail{}, core.ErrNotFound),
+	)
+
+	server := grpc.NewServer(mockSearcher)
+	reply, err := server.RandomComic(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, int64(7), reply.GetComic().GetId())
+	require.Equal(t, int64(6), reply.GetPrevId())
+
+	_, err = server.RandomComic(context.Background(), &emptypb.Empty{})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSuggest(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		suggestions  []core.Suggestion
+		serviceError error
+		expectedCode codes.Code
+	}{
+		{
+			desc:        "success",
+			suggestions: []core.Suggestion{{Text: "linux", Count: 3}, {Text: "line", Count: 2}},
+		},
+		{
+			desc:         "error - bad arguments",
+			serviceError: core.ErrBadArguments,
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := core.NewMockSearcher(ctrl)
+			mockSearcher.EXPECT().Suggest(gomock.Any(), "li", int64(5)).Return(tc.suggestions, tc.serviceError)
+
+			server := grpc.NewServer(mockSearcher)
+			reply, err := server.Suggest(context.Background(), &searchpb.SuggestRequest{Prefix: "li", Limit: 5})
+
+			if tc.serviceError != nil {
+				require.Equal(t, tc.expectedCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, reply.GetSuggestions(), len(tc.suggestions))
+			for i, suggestion := range tc.suggestions {
+				require.Equal(t, suggestion.Text, reply.GetSuggestions()[i].GetText())
+				require.Equal(t, suggestion.Count, reply.GetSuggestions()[i].GetCount())
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updatedAt := time.Now().Add(-time.Hour)
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().IndexStatus(gomock.Any()).Return(core.IndexStatus{
+		Generation: 3,
+		Source:     core.IndexSourceSnapshot,
+		UpdatedAt:  updatedAt,
+		Comics:     42,
+	})
+
+	server := grpc.NewServer(mockSearcher)
+	reply, err := server.Status(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), reply.GetIndexGeneration())
+	require.Equal(t, string(core.IndexSourceSnapshot), reply.GetIndexSource())
+	require.Equal(t, updatedAt.Unix(), reply.GetIndexUpdatedAt())
+	require.InDelta(t, 3600, reply.GetIndexAgeSeconds(), 5)
+	require.Equal(t, int64(42), reply.GetIndexComics())
+}
+
+func TestIndexStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	builtAt := time.Now()
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().IndexStats(gomock.Any()).Return(core.IndexStats{
+		Terms:         10,
+		Postings:      25,
+		Bytes:         120,
+		BuildDuration: 1500 * time.Millisecond,
+		BuiltAt:       builtAt,
+	})
+
+	server := grpc.NewServer(mockSearcher)
+	reply, err := server.IndexStats(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), reply.GetTerms())
+	require.Equal(t, int64(25), reply.GetPostings())
+	require.Equal(t, int64(120), reply.GetBytes())
+	require.Equal(t, int64(1500), reply.GetBuildDurationMs())
+	require.Equal(t, builtAt.Unix(), reply.GetBuiltAt())
+}
+
+func TestCacheStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := core.NewMockSearcher(ctrl)
+	mockSearcher.EXPECT().CacheStats(gomock.Any()).Return(core.CacheStats{Hits: 3, Misses: 1, Evictions: 2, Size: 5})
+
+	server := grpc.NewServer(mockSearcher)
+	reply, err := server.CacheStats(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), reply.GetHits())
+	require.Equal(t, int64(1), reply.GetMisses())
+	require.Equal(t, int64(2), reply.GetEvictions())
+	require.Equal(t, int64(5), reply.GetSize())
+	require.InDelta(t, 0.75, reply.GetHitRatio(), 1e-9)
+}
@@ -26,6 +26,56 @@ func (s *SearcherScheduler) Start(ctx context.Context) error {
 	if err := s.searcher.UpdateIndex(ctx); err != nil {
 		return err
 	}
+	go s.loop(ctx)
+	return nil
+}
+
+// StartWarm запускает обновления, когда индекс уже загружен из снимка:
+// поиск готов сразу, а первая сборка догоняет базу в фоне.
+func (s *SearcherScheduler) StartWarm(ctx context.Context) {
+	s.log.Info("start searcher scheduler from snapshot")
+	go func() {
+		if err := s.searcher.UpdateIndex(ctx); err != nil {
+			s.log.Error("failed to update index", "error", err)
+		}
+		s.loop(ctx)
+	}()
+}
+
+func (s *SearcherScheduler) loop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.searcher.UpdateIndex(ctx); err != nil {
+				s.log.Error("failed to update index", "error", err)
+			}
+		case <-ctx.Done():
+			s.log.Info("index updater stopped")
+			return
+		}
+	}
+}
+
+// SnapshotScheduler периодически сохраняет снимок индекса.
+type SnapshotScheduler struct {
+	log         *slog.Logger
+	snapshotter core.Snapshotter
+	interval    time.Duration
+}
+
+func NewSnapshotScheduler(log *slog.Logger, snapshotter core.Snapshotter, interval time.Duration) *SnapshotScheduler {
+	return &SnapshotScheduler{
+		log:         log,
+		snapshotter: snapshotter,
+		interval:    interval,
+	}
+}
+
+func (s *SnapshotScheduler) Start(ctx context.Context) {
+	s.log.Info("start snapshot scheduler")
 	go func() {
 		ticker := time.NewTicker(s.interval)
 		defer ticker.Stop()
@@ -33,14 +83,13 @@ func (s *SearcherScheduler) Start(ctx context.Context) error {
 		for {
 			select {
 			case <-ticker.C:
-				if err := s.searcher.UpdateIndex(ctx); err != nil {
-					s.log.Error("failed to update index", "error", err)
+				if err := s.snapshotter.SaveSnapshot(ctx); err != nil {
+					s.log.Error("failed to save index snapshot", "error", err)
 				}
 			case <-ctx.Done():
-				s.log.Info("index updater stopped")
+				s.log.Info("snapshot scheduler stopped")
 				return
 			}
 		}
 	}()
-	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"search-service/search/adapters/scheduler"
 	"search-service/search/core"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -45,7 +46,11 @@ func TestStartInitialUpdate(t *testing.T) {
 
 			s := scheduler.NewSearcherScheduler(slog.Default(), mockSearcher, time.Second)
 
-			err := s.Start(context.Background())
+			// отмена останавливает периодическое обновление после теста
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := s.Start(ctx)
 
 			if tc.wantErr {
 				require.Error(t, err)
@@ -63,10 +68,10 @@ func TestStartPeriodicUpdate(t *testing.T) {
 	mockSearcher := core.NewMockSearcher(ctrl)
 
 	expectedCalls := 3
-	callCount := 0
+	var callCount atomic.Int32
 
 	mockSearcher.EXPECT().UpdateIndex(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
-		callCount++
+		callCount.Add(1)
 		return nil
 	}).MinTimes(expectedCalls)
 
@@ -83,7 +88,7 @@ func TestStartPeriodicUpdate(t *testing.T) {
 
 	time.Sleep(100 * time.Millisecond)
 
-	require.GreaterOrEqual(t, callCount, expectedCalls)
+	require.GreaterOrEqual(t, int(callCount.Load()), expectedCalls)
 }
 
 func TestStartContextCancellation(t *testing.T) {
@@ -93,10 +98,10 @@ func TestStartContextCancellation(t *testing.T) {
 	mockSearcher := core.NewMockSearcher(ctrl)
 
 	expectedCalls := 1
-	callCount := 0
+	var callCount atomic.Int32
 
 	mockSearcher.EXPECT().UpdateIndex(gomock.Any()).Do(func(ctx context.Context) {
-		callCount++
+		callCount.Add(1)
 	}).Return(nil)
 
 	s := scheduler.NewSearcherScheduler(slog.Default(), mockSearcher, time.Second)
@@ -109,7 +114,7 @@ func TestStartContextCancellation(t *testing.T) {
 	cancel()
 	time.Sleep(100 * time.Millisecond)
 
-	require.Equal(t, expectedCalls, callCount)
+	require.Equal(t, expectedCalls, int(callCount.Load()))
 }
 
 func TestStartUpdateError(t *testing.T) {
@@ -134,3 +139,56 @@ func TestStartUpdateError(t *testing.T) {
 	time.Sleep(75 * time.Millisecond)
 	cancel()
 }
+
+func TestStartWarm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearcher := core.NewMockSearcher(ctrl)
+
+	// ошибка первой сборки не мешает запуску: поиск идет по снимку
+	updated := make(chan struct{})
+	mockSearcher.EXPECT().UpdateIndex(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		close(updated)
+		return errors.New("update failed")
+	})
+
+	s := scheduler.NewSearcherScheduler(slog.Default(), mockSearcher, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.StartWarm(ctx)
+
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fatal("initial update was not started")
+	}
+}
+
+func TestSnapshotScheduler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSnapshotter := core.NewMockSnapshotter(ctrl)
+
+	expectedCalls := 2
+	var callCount atomic.Int32
+
+	mockSnapshotter.EXPECT().SaveSnapshot(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		callCount.Add(1)
+		return nil
+	}).MinTimes(expectedCalls)
+
+	s := scheduler.NewSnapshotScheduler(slog.Default(), mockSnapshotter, 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	time.Sleep(150 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	require.GreaterOrEqual(t, int(callCount.Load()), expectedCalls)
+}
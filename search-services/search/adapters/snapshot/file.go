@@ -0,0 +1,114 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"search-service/search/core"
+)
+
+// Формат файла: magic, версия формата, CRC32 и длина данных, затем данные в gob.
+// Снимок другой версии формата считается поврежденным: индекс пересобирается по базе.
+const (
+	magic      = "XKCDIDX\n"
+	version    = 1
+	headerSize = len(magic) + 4 + 4 + 8
+)
+
+type File struct {
+	log  *slog.Logger
+	path string
+}
+
+func NewFile(log *slog.Logger, path string) *File {
+	return &File{log: log, path: path}
+}
+
+func (f *File) Save(_ context.Context, snapshot core.IndexSnapshot) error {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	binary.BigEndian.PutUint32(header[len(magic):], version)
+	binary.BigEndian.PutUint32(header[len(magic)+4:], crc32.ChecksumIEEE(payload.Bytes()))
+	binary.BigEndian.PutUint64(header[len(magic)+8:], uint64(payload.Len()))
+
+	// снимок пишется во временный файл и подменяется целиком,
+	// чтобы падение во время записи не испортило прежний
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(f.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(tmp.Name()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			f.log.Warn("failed to remove temporary snapshot file", "error", err)
+		}
+	}()
+
+	if _, err := tmp.Write(header); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if _, err := payload.WriteTo(tmp); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return nil
+}
+
+func (f *File) Load(_ context.Context) (core.IndexSnapshot, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return core.IndexSnapshot{}, core.ErrSnapshotNotFound
+	}
+	if err != nil {
+		return core.IndexSnapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	if len(data) < headerSize || string(data[:len(magic)]) != magic {
+		return core.IndexSnapshot{}, fmt.Errorf("%w: unknown file format", core.ErrSnapshotCorrupt)
+	}
+	if v := binary.BigEndian.Uint32(data[len(magic):]); v != version {
+		return core.IndexSnapshot{}, fmt.Errorf("%w: unsupported version %d", core.ErrSnapshotCorrupt, v)
+	}
+	checksum := binary.BigEndian.Uint32(data[len(magic)+4:])
+	length := binary.BigEndian.Uint64(data[len(magic)+8:])
+	payload := data[headerSize:]
+	if uint64(len(payload)) != length {
+		return core.IndexSnapshot{}, fmt.Errorf("%w: truncated", core.ErrSnapshotCorrupt)
+	}
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return core.IndexSnapshot{}, fmt.Errorf("%w: checksum mismatch", core.ErrSnapshotCorrupt)
+	}
+
+	var snapshot core.IndexSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&snapshot); err != nil {
+		return core.IndexSnapshot{}, fmt.Errorf("%w: %w", core.ErrSnapshotCorrupt, err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,96 @@
+package snapshot_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"search-service/search/adapters/snapshot"
+	"search-service/search/core"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index", "snapshot")
+	file := snapshot.NewFile(slog.Default(), path)
+
+	saved := core.IndexSnapshot{
+		Generation: 7,
+		UpdatedAt:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Comics: []core.ComicInfo{{
+			Comic:      core.Comic{ID: 1, URL: "http://example.com/1", Title: "Linux"},
+			Words:      []string{"linux"},
+			Phonetics:  []string{"LNKS"},
+			Terms:      []string{"linux"},
+			TitleTerms: []string{"linux"},
+		}},
+	}
+	require.NoError(t, file.Save(context.TODO(), saved))
+
+	loaded, err := file.Load(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, saved, loaded)
+
+	// повторное сохранение подменяет снимок и не оставляет временных файлов
+	saved.Generation = 8
+	require.NoError(t, file.Save(context.TODO(), saved))
+	loaded, err = file.Load(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), loaded.Generation)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		corrupt func(data []byte) []byte
+		wantErr error
+	}{
+		{
+			desc:    "not found",
+			wantErr: core.ErrSnapshotNotFound,
+		},
+		{
+			desc:    "checksum mismatch",
+			corrupt: func(data []byte) []byte { data[len(data)-1] ^= 0xff; return data },
+			wantErr: core.ErrSnapshotCorrupt,
+		},
+		{
+			desc:    "truncated",
+			corrupt: func(data []byte) []byte { return data[:len(data)-3] },
+			wantErr: core.ErrSnapshotCorrupt,
+		},
+		{
+			desc:    "unsupported version",
+			corrupt: func(data []byte) []byte { data[11] = 99; return data },
+			wantErr: core.ErrSnapshotCorrupt,
+		},
+		{
+			desc:    "not a snapshot",
+			corrupt: func([]byte) []byte { return []byte("hello") },
+			wantErr: core.ErrSnapshotCorrupt,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "snapshot")
+			file := snapshot.NewFile(slog.Default(), path)
+
+			if tc.corrupt != nil {
+				require.NoError(t, file.Save(context.TODO(), core.IndexSnapshot{Generation: 1}))
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, tc.corrupt(data), 0o644))
+			}
+
+			_, err := file.Load(context.TODO())
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package subscriber
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"search-service/search/core"
@@ -10,6 +12,22 @@ import (
 	"github.com/nats-io/nats.go"
 )
 
+// message - событие в брокере. Прежний формат - только тип события.
+type message struct {
+	Type    core.EventType `json:"type"`
+	Added   []int64        `json:"added,omitempty"`
+	Updated []int64        `json:"updated,omitempty"`
+	Deleted []int64        `json:"deleted,omitempty"`
+}
+
+func decodeEvent(data []byte) core.Event {
+	var msg message
+	if !bytes.HasPrefix(data, []byte("{")) || json.Unmarshal(data, &msg) != nil {
+		return core.Event{Type: core.EventType(data)}
+	}
+	return core.Event{Type: msg.Type, Added: msg.Added, Updated: msg.Updated, Deleted: msg.Deleted}
+}
+
 type NatsSubscriber struct {
 	conn *nats.Conn
 	sub  *nats.Subscription
@@ -39,7 +57,7 @@ func NewNatsSubscriber(address, subj string, handler core.EventHandler, log *slo
 	}
 
 	sub, err := nc.Subscribe(subj, func(msg *nats.Msg) {
-		if err := handler.HandleEvent(context.TODO(), core.EventType(msg.Data)); err != nil {
+		if err := handler.HandleEvent(context.TODO(), decodeEvent(msg.Data)); err != nil {
 			log.Error("failed to handle event", "error", err)
 		} else {
 			log.Debug("received message", "subject", subj)
@@ -2,6 +2,7 @@ package words
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	wordspb "search-service/proto/words"
 	"search-service/search/core"
@@ -15,6 +16,8 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+const maxBatchSize = 256 << 10
+
 type Client struct {
 	log    *slog.Logger
 	conn   *grpc.ClientConn
@@ -69,6 +72,83 @@ func (c *Client) Norm(ctx context.Context, phrase string) ([]string, error) {
 	return reply.GetWords(), nil
 }
 
+func (c *Client) Phonetics(ctx context.Context, phrase string) ([]string, error) {
+	reply, err := c.client.Norm(ctx, &wordspb.WordsRequest{Phrase: phrase, Phonetic: true})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable:
+			return nil, core.ErrServiceUnavailable
+		case codes.ResourceExhausted:
+			return nil, core.ErrBadArguments
+		default:
+			return nil, err
+		}
+	}
+	return reply.GetPhonetics(), nil
+}
+
+func (c *Client) Terms(ctx context.Context, phrase string) ([]string, error) {
+	reply, err := c.client.Norm(ctx, &wordspb.WordsRequest{Phrase: phrase, Terms: true})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable:
+			return nil, core.ErrServiceUnavailable
+		case codes.ResourceExhausted:
+			return nil, core.ErrBadArguments
+		default:
+			return nil, err
+		}
+	}
+	return reply.GetTerms(), nil
+}
+
+// TokenizeAll отправляет тексты пакетами не больше maxBatchSize байт:
+// ответ с токенами в несколько раз больше текста и не должен упереться
+// в ограничение размера сообщения gRPC.
+func (c *Client) TokenizeAll(ctx context.Context, texts []string) ([][]core.Token, error) {
+	tokens := make([][]core.Token, 0, len(texts))
+	for start := 0; start < len(texts); {
+		end, size := start, 0
+		for end < len(texts) && (end == start || size+len(texts[end]) <= maxBatchSize) {
+			size += len(texts[end])
+			end++
+		}
+		reply, err := c.client.TokenizeBatch(ctx, &wordspb.TokenizeBatchRequest{Phrases: texts[start:end]})
+		if err != nil {
+			switch status.Code(err) {
+			case codes.Unavailable:
+				return nil, core.ErrServiceUnavailable
+			case codes.ResourceExhausted:
+				return nil, core.ErrBadArguments
+			default:
+				return nil, err
+			}
+		}
+		if len(reply.GetReplies()) != end-start {
+			return nil, fmt.Errorf("words tokenized %d texts of %d", len(reply.GetReplies()), end-start)
+		}
+		for _, tokenized := range reply.GetReplies() {
+			tokens = append(tokens, makeTokens(tokenized))
+		}
+		start = end
+	}
+	return tokens, nil
+}
+
+func makeTokens(reply *wordspb.TokenizeReply) []core.Token {
+	tokens := make([]core.Token, len(reply.GetTokens()))
+	for i, token := range reply.GetTokens() {
+		tokens[i] = core.Token{
+			Text:     token.GetText(),
+			Start:    int(token.GetStart()),
+			End:      int(token.GetEnd()),
+			Stem:     token.GetStem(),
+			StopWord: token.GetStopword(),
+		}
+	}
+	return tokens
+}
+
 func (c *Client) Close() {
 	if err := c.conn.Close(); err != nil {
 		c.log.Warn("failed to close gRPC connection", "error", err)
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+	"search-service/pkg/cache"
+	"strings"
+)
+
+// resultCache - кэш результатов ISearch. Нулевой размер отключает кэширование.
+//
+// Ключ строится по фразе без нормализации в words, чтобы повторный запрос
+// не ходил в words, и включает версию индекса: результат, найденный по
+// прежней версии, уже не выдается, даже если попал в кэш после сброса.
+type resultCache struct {
+	lru *cache.LRU[SearchResult]
+}
+
+func newResultCache(size int) *resultCache {
+	if size <= 0 {
+		return &resultCache{}
+	}
+	// записи живут до смены версии индекса, а не по времени
+	return &resultCache{lru: cache.New[SearchResult](size, 0)}
+}
+
+func cacheKey(method string, req SearchRequest, ranker string, generation uint64) string {
+	phrase := strings.Join(strings.Fields(req.Phrase), " ")
+	f := req.Filters
+	return fmt.Sprintf("%s|%d|%s|%d|%d|%t|%d-%d|%d-%d|%t|%t|%s|%s",
+		method, generation, ranker, req.Limit, req.Offset, req.Explain,
+		f.YearFrom, f.YearTo, f.IDFrom, f.IDTo, f.Link, f.News, req.Sort, phrase)
+}
+
+func (c *resultCache) get(key string) (SearchResult, bool) {
+	if c.lru == nil {
+		return SearchResult{}, false
+	}
+	return c.lru.Get(key)
+}
+
+func (c *resultCache) add(key string, result SearchResult) {
+	if c.lru != nil {
+		c.lru.Add(key, result)
+	}
+}
+
+// purge удаляет все записи после смены версии индекса.
+func (c *resultCache) purge() {
+	if c.lru != nil {
+		c.lru.Purge()
+	}
+}
+
+func (c *resultCache) stats() CacheStats {
+	if c.lru == nil {
+		return CacheStats{}
+	}
+	stats := c.lru.Stats()
+	return CacheStats{Hits: stats.Hits, Misses: stats.Misses, Evictions: stats.Evictions, Size: stats.Size}
+}
@@ -0,0 +1,137 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"search-service/search/core"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	indexed := []core.ComicInfo{
+		fieldComic(1, []string{"linux", "kernel"}, nil, nil),
+		fieldComic(2, []string{"cat"}, nil, nil),
+	}
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil).Times(2)
+
+	norms := 0
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, phrase string) ([]string, error) {
+			norms++
+			return fakeNorm(ctx, phrase)
+		}).AnyTimes()
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{CacheSize: 2})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	first, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux kernel", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, norms)
+
+	// повторный запрос, отличающийся только пробелами, не ходит в words
+	cached, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: " linux  kernel ", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, first, cached)
+	require.Equal(t, 1, norms)
+
+	// другая страница - другой ключ
+	_, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux kernel", Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 2, norms)
+	require.Equal(t, core.CacheStats{Hits: 1, Misses: 2, Size: 2}, service.CacheStats(context.TODO()))
+
+	// третья запись вытесняет самую старую
+	_, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: "cat", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), service.CacheStats(context.TODO()).Evictions)
+
+	// новая версия индекса сбрасывает кэш
+	require.NoError(t, service.HandleEvent(context.TODO(), core.Event{Type: core.EventUpdate}))
+	require.Equal(t, int64(0), service.CacheStats(context.TODO()).Size)
+	result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "cat", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 4, norms)
+	require.Equal(t, uint64(2), result.IndexGeneration)
+
+	service.ResetIndex()
+	require.Equal(t, int64(0), service.CacheStats(context.TODO()).Size)
+
+	stats := service.CacheStats(context.TODO())
+	require.InDelta(t, 1.0/5, stats.HitRatio(), 1e-9)
+}
+
+func TestSearchCacheSkipsDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+	mockDB.EXPECT().CorpusStats(gomock.Any(), gomock.Any()).Return(core.CorpusStats{Docs: 1}, nil).AnyTimes()
+	gomock.InOrder(
+		mockDB.EXPECT().FindComicsInfo(gomock.Any(), []string{"linux"}).Return(nil, nil),
+		// комикс записан в базу до новой версии индекса
+		mockDB.EXPECT().FindComicsInfo(gomock.Any(), []string{"linux"}).Return([]core.ComicInfo{
+			fieldComic(1, []string{"linux"}, nil, nil),
+		}, nil),
+	)
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{CacheSize: 10})
+	require.NoError(t, err)
+
+	result, err := service.Search(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, result.Comics)
+
+	result, err = service.Search(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Comics, 1)
+	require.Equal(t, core.CacheStats{}, service.CacheStats(context.TODO()))
+}
+
+func TestSearchCacheSkipsErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWords := core.NewMockWords(ctrl)
+	gomock.InOrder(
+		mockWords.EXPECT().Norm(gomock.Any(), "linux").Return(nil, errors.New("words unavailable")),
+		mockWords.EXPECT().Norm(gomock.Any(), "linux").Return([]string{"linux"}, nil),
+	)
+
+	service, err := core.NewService(slog.Default(), core.NewMockDB(ctrl), mockWords, nil, core.Options{CacheSize: 10})
+	require.NoError(t, err)
+
+	_, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.Error(t, err)
+	_, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), service.CacheStats(context.TODO()).Size)
+}
+
+func TestSearchCacheDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWords := core.NewMockWords(ctrl)
+	mockWords.EXPECT().Norm(gomock.Any(), "linux").Return([]string{"linux"}, nil).Times(2)
+
+	service, err := core.NewService(slog.Default(), core.NewMockDB(ctrl), mockWords, nil, core.Options{})
+	require.NoError(t, err)
+
+	for range 2 {
+		_, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+		require.NoError(t, err)
+	}
+	require.Equal(t, core.CacheStats{}, service.CacheStats(context.TODO()))
+}
@@ -0,0 +1,195 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"search-service/search/core"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func numberedComics() []core.ComicInfo {
+	comics := []core.ComicInfo{
+		fieldComic(1, []string{"linux", "2"}, nil, nil),
+		fieldComic(2, []string{"cat"}, nil, nil),
+		fieldComic(5, []string{"linux"}, nil, nil),
+	}
+	for i := range comics {
+		comics[i].URL = "http://example.com/" + comics[i].Words[0]
+		comics[i].Alt = "alt text"
+	}
+	return comics
+}
+
+func TestGetComic(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		id       int64
+		expected core.ComicDetail
+		wantErr  error
+	}{
+		{
+			desc:     "success - with both neighbours",
+			id:       2,
+			expected: core.ComicDetail{Comic: numberedComics()[1].Comic, PrevID: 1, NextID: 5},
+		},
+		{
+			desc:     "success - first comic",
+			id:       1,
+			expected: core.ComicDetail{Comic: numberedComics()[0].Comic, NextID: 2},
+		},
+		{
+			desc:     "success - last comic",
+			id:       5,
+			expected: core.ComicDetail{Comic: numberedComics()[2].Comic, PrevID: 2},
+		},
+		{
+			desc:    "error - not in index",
+			id:      3,
+			wantErr: core.ErrNotFound,
+		},
+		{
+			desc:    "error - bad id",
+			id:      0,
+			wantErr: core.ErrBadArguments,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(numberedComics(), nil)
+
+			service, err := core.NewService(slog.Default(), mockDB, newIndexingWords(ctrl), nil, core.Options{})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			detail, err := service.GetComic(context.TODO(), tc.id)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, detail)
+		})
+	}
+}
+
+func TestGetComicsAndRandom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(numberedComics(), nil)
+
+	service, err := core.NewService(slog.Default(), mockDB, newIndexingWords(ctrl), nil, core.Options{})
+	require.NoError(t, err)
+
+	// пока индекс пуст, случайного комикса нет
+	_, err = service.RandomComic(context.TODO())
+	require.ErrorIs(t, err, core.ErrNotFound)
+
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	comics, err := service.GetComics(context.TODO(), []int64{5, 3, 1})
+	require.NoError(t, err)
+	require.Equal(t, []core.Comic{numberedComics()[2].Comic, numberedComics()[0].Comic}, comics)
+
+	detail, err := service.RandomComic(context.TODO())
+	require.NoError(t, err)
+	require.Contains(t, []int64{1, 2, 5}, detail.ID)
+	expected, err := service.GetComic(context.TODO(), detail.ID)
+	require.NoError(t, err)
+	require.Equal(t, expected, detail)
+}
+
+func TestSearchComicNumber(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		phrase    string
+		offset    int64
+		ids       []int64
+		totalHits int64
+	}{
+		{
+			desc:      "number goes first, then text matches",
+			phrase:    "2",
+			ids:       []int64{2, 1},
+			totalHits: 2,
+		},
+		{
+			desc:      "number with hash",
+			phrase:    "#5",
+			ids:       []int64{5},
+			totalHits: 1,
+		},
+		{
+			desc:      "number on the first page only",
+			phrase:    "2",
+			offset:    1,
+			ids:       []int64{1},
+			totalHits: 2,
+		},
+		{
+			desc:      "unknown number is an ordinary query",
+			phrase:    "42",
+			ids:       []int64{},
+			totalHits: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, method := range []string{"Search", "ISearch"} {
+			t.Run(method+" - "+tc.desc, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				indexed := numberedComics()
+				mockDB := core.NewMockDB(ctrl)
+				mockWords := newIndexingWords(ctrl)
+				mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+				service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+				require.NoError(t, err)
+
+				search := service.ISearch
+				if method == "Search" {
+					expectFindComics(mockDB, indexed)
+					// Search берет комикс по номеру из базы
+					mockDB.EXPECT().GetComicsInfoByIds(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, ids []int64) ([]core.ComicInfo, error) {
+							var found []core.ComicInfo
+							for _, info := range indexed {
+								if info.ID == ids[0] {
+									found = append(found, info)
+								}
+							}
+							return found, nil
+						})
+					search = service.Search
+				} else {
+					mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+					require.NoError(t, service.UpdateIndex(context.TODO()))
+				}
+
+				result, err := search(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10, Offset: tc.offset})
+				require.NoError(t, err)
+				ids := make([]int64, len(result.Comics))
+				for i, comic := range result.Comics {
+					ids[i] = comic.ID
+				}
+				require.Equal(t, tc.ids, ids)
+				require.Equal(t, tc.totalHits, result.TotalHits)
+				// у комикса по номеру те же поля, что и у найденных
+				for _, comic := range result.Comics {
+					require.NotEmpty(t, comic.URL)
+				}
+			})
+		}
+	}
+}
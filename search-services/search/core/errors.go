@@ -1,8 +1,28 @@
 package core
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrBadArguments       = errors.New("arguments are not acceptable")
 	ErrServiceUnavailable = errors.New("service is currently unavailable")
+	ErrNotFound           = errors.New("comic not found")
+	ErrSnapshotNotFound   = errors.New("index snapshot not found")
+	ErrSnapshotCorrupt    = errors.New("index snapshot is corrupt")
 )
+
+// QueryError - синтаксическая ошибка в запросе. Pos - позиция в символах от начала фразы.
+type QueryError struct {
+	Pos int
+	Msg string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrBadArguments
+}
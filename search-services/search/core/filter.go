@@ -0,0 +1,69 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+)
+
+func (f Filters) valid() bool {
+	if f.YearFrom < 0 || f.YearTo < 0 || f.IDFrom < 0 || f.IDTo < 0 {
+		return false
+	}
+	if f.YearTo > 0 && f.YearFrom > f.YearTo {
+		return false
+	}
+	return f.IDTo == 0 || f.IDFrom <= f.IDTo
+}
+
+// matchYear проверяет год публикации. Комикс с неизвестной датой
+// проходит, только если год не ограничен.
+func (f Filters) matchYear(comic Comic) bool {
+	if f.YearFrom == 0 && f.YearTo == 0 {
+		return true
+	}
+	if comic.Published == nil {
+		return false
+	}
+	year := comic.Published.Year()
+	return year >= f.YearFrom && (f.YearTo == 0 || year <= f.YearTo)
+}
+
+// matchOther проверяет все ограничения, кроме года.
+func (f Filters) matchOther(comic Comic) bool {
+	switch {
+	case comic.ID < f.IDFrom, f.IDTo > 0 && comic.ID > f.IDTo:
+		return false
+	case f.Link && comic.Link == "", f.News && comic.News == "":
+		return false
+	}
+	return true
+}
+
+// filter оставляет комиксы, подходящие под фильтры, в прежнем порядке и
+// считает их по годам публикации. Годы считаются до фильтра по году, иначе
+// в распределении остались бы только выбранные годы.
+func (f Filters) filter(ids []int64, comic func(int64) (Comic, bool)) ([]int64, []YearFacet) {
+	counts := map[int]int64{}
+	filtered := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		c, ok := comic(id)
+		if !ok || !f.matchOther(c) {
+			continue
+		}
+		if c.Published != nil {
+			counts[c.Published.Year()]++
+		}
+		if f.matchYear(c) {
+			filtered = append(filtered, id)
+		}
+	}
+
+	var years []YearFacet
+	for year, count := range counts {
+		years = append(years, YearFacet{Year: year, Count: count})
+	}
+	slices.SortFunc(years, func(a, b YearFacet) int {
+		return cmp.Compare(a.Year, b.Year)
+	})
+	return filtered, years
+}
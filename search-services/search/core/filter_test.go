@@ -0,0 +1,175 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"search-service/search/core"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func publishedComics() []core.ComicInfo {
+	date := func(year int) *time.Time {
+		published := time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC)
+		return &published
+	}
+	comics := []core.ComicInfo{
+		fieldComic(1, []string{"linux"}, nil, nil),
+		fieldComic(2, []string{"linux"}, nil, nil),
+		fieldComic(3, []string{"linux"}, nil, nil),
+		fieldComic(4, []string{"linux"}, nil, nil),
+		fieldComic(5, []string{"linux"}, nil, nil),
+	}
+	comics[0].Published = date(2006)
+	comics[1].Published = date(2006)
+	comics[2].Published = date(2010)
+	comics[2].Link = "http://example.com/large"
+	comics[3].Published = date(2012)
+	comics[3].News = "announcement"
+	// у комикса 5 дата неизвестна
+	return comics
+}
+
+func TestSearchFilters(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		phrase  string
+		filters core.Filters
+		ids     []int64
+		years   []core.YearFacet
+		wantErr error
+	}{
+		{
+			desc:   "no filters - facets over all hits",
+			phrase: "linux",
+			ids:    []int64{5, 4, 3, 2, 1},
+			years:  []core.YearFacet{{Year: 2006, Count: 2}, {Year: 2010, Count: 1}, {Year: 2012, Count: 1}},
+		},
+		{
+			desc:    "year range keeps facets of other years",
+			phrase:  "linux",
+			filters: core.Filters{YearFrom: 2007, YearTo: 2012},
+			ids:     []int64{4, 3},
+			years:   []core.YearFacet{{Year: 2006, Count: 2}, {Year: 2010, Count: 1}, {Year: 2012, Count: 1}},
+		},
+		{
+			desc:    "single year",
+			phrase:  "linux",
+			filters: core.Filters{YearFrom: 2006, YearTo: 2006},
+			ids:     []int64{2, 1},
+			years:   []core.YearFacet{{Year: 2006, Count: 2}, {Year: 2010, Count: 1}, {Year: 2012, Count: 1}},
+		},
+		{
+			desc:    "id range narrows facets",
+			phrase:  "linux",
+			filters: core.Filters{IDFrom: 2, IDTo: 3},
+			ids:     []int64{3, 2},
+			years:   []core.YearFacet{{Year: 2006, Count: 1}, {Year: 2010, Count: 1}},
+		},
+		{
+			desc:    "link flag",
+			phrase:  "linux",
+			filters: core.Filters{Link: true},
+			ids:     []int64{3},
+			years:   []core.YearFacet{{Year: 2010, Count: 1}},
+		},
+		{
+			desc:    "news flag with year",
+			phrase:  "linux",
+			filters: core.Filters{News: true, YearFrom: 2013},
+			ids:     []int64{},
+			years:   []core.YearFacet{{Year: 2012, Count: 1}},
+		},
+		{
+			desc:    "comic number stays pinned despite filters",
+			phrase:  "5",
+			filters: core.Filters{YearFrom: 2006},
+			ids:     []int64{5},
+		},
+		{
+			desc:    "error - inverted years",
+			phrase:  "linux",
+			filters: core.Filters{YearFrom: 2012, YearTo: 2006},
+			wantErr: core.ErrBadArguments,
+		},
+		{
+			desc:    "error - inverted ids",
+			phrase:  "linux",
+			filters: core.Filters{IDFrom: 5, IDTo: 1},
+			wantErr: core.ErrBadArguments,
+		},
+		{
+			desc:    "error - negative id",
+			phrase:  "linux",
+			filters: core.Filters{IDFrom: -1},
+			wantErr: core.ErrBadArguments,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, method := range []string{"Search", "ISearch"} {
+			t.Run(method+" - "+tc.desc, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				indexed := publishedComics()
+				mockDB := core.NewMockDB(ctrl)
+				mockWords := core.NewMockWords(ctrl)
+				mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+				service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+				require.NoError(t, err)
+
+				search := service.ISearch
+				if method == "Search" {
+					expectFindComics(mockDB, indexed)
+					mockDB.EXPECT().GetComicsInfoByIds(gomock.Any(), gomock.Any()).Return(indexed[4:], nil).AnyTimes()
+					search = service.Search
+				} else {
+					mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+					require.NoError(t, service.UpdateIndex(context.TODO()))
+				}
+
+				result, err := search(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10, Filters: tc.filters})
+				if tc.wantErr != nil {
+					require.ErrorIs(t, err, tc.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				ids := make([]int64, len(result.Comics))
+				for i, comic := range result.Comics {
+					ids[i] = comic.ID
+				}
+				require.ElementsMatch(t, tc.ids, ids)
+				require.Equal(t, int64(len(tc.ids)), result.TotalHits)
+				require.Equal(t, tc.years, result.Years)
+			})
+		}
+	}
+}
+
+func TestSearchCacheKeepsFiltersApart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(publishedComics(), nil)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).Times(2)
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{CacheSize: 10})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	all, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	filtered, err := service.ISearch(context.TODO(), core.SearchRequest{
+		Phrase: "linux", Limit: 10, Filters: core.Filters{Link: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), all.TotalHits)
+	require.Equal(t, int64(1), filtered.TotalHits)
+}
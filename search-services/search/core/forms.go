@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"strings"
+)
+
+// addForms заполняет слова комиксов в исходном написании: по ним исправляются
+// опечатки и строятся подсказки. Тексты уходят в words пакетами, а не по
+// запросу на комикс. Без words комиксы индексируются без слов в написании,
+// и до следующей сборки опечатки в них не исправляются.
+func (s *Service) addForms(ctx context.Context, comicsInfo []ComicInfo) {
+	var texts []string
+	var targets []int
+	for i, info := range comicsInfo {
+		// у комиксов из снимка слова уже есть
+		if info.Forms != nil {
+			continue
+		}
+		text := strings.TrimSpace(strings.Join([]string{info.Title, info.Alt, info.Transcript}, "\n"))
+		if text == "" {
+			continue
+		}
+		texts = append(texts, text)
+		targets = append(targets, i)
+	}
+	if len(texts) == 0 {
+		return
+	}
+
+	tokens, err := s.words.TokenizeAll(ctx, texts)
+	if err != nil {
+		s.log.Warn("failed to tokenize comics, indexing without word forms", "comics", len(texts), "error", err)
+		return
+	}
+	for i, target := range targets {
+		comicsInfo[target].Forms = makeForms(tokens[i])
+	}
+}
+
+func makeForms(tokens []Token) []WordForm {
+	seen := map[string]bool{}
+	forms := []WordForm{}
+	for _, token := range tokens {
+		word := strings.ToLower(token.Text)
+		if token.StopWord || token.Stem == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		forms = append(forms, WordForm{Word: word, Stem: token.Stem})
+	}
+	return forms
+}
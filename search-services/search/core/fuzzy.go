@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// bkTree - словарь слов комиксов для поиска на заданном расстоянии Левенштейна.
+// Расстояния до дочерних узлов хранятся в ребрах, и по неравенству треугольника
+// при поиске просматриваются только ребра в пределах [d-max, d+max].
+type bkTree struct {
+	root *bkNode
+	df   map[string]int
+}
+
+type bkNode struct {
+	term     string
+	children map[int]*bkNode
+}
+
+func newBKTree(df map[string]int) *bkTree {
+	tree := &bkTree{df: df}
+	// порядок вставки влияет только на форму дерева, но не на результат
+	terms := make([]string, 0, len(df))
+	for term := range df {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	for _, term := range terms {
+		tree.add(term)
+	}
+	return tree
+}
+
+func (t *bkTree) add(term string) {
+	if t.root == nil {
+		t.root = &bkNode{term: term, children: map[int]*bkNode{}}
+		return
+	}
+	node := t.root
+	for {
+		d := levenshtein(term, node.term)
+		if d == 0 {
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			node.children[d] = &bkNode{term: term, children: map[int]*bkNode{}}
+			return
+		}
+		node = child
+	}
+}
+
+// closest возвращает ближайшее к term слово словаря на расстоянии не больше
+// допустимого для его длины; при равенстве выигрывает слово из большего числа
+// комиксов, затем - по алфавиту.
+func (t *bkTree) closest(term string) (string, bool) {
+	maxDist := maxTypos(term)
+	if t.root == nil || maxDist == 0 {
+		return "", false
+	}
+	best, bestDist := "", maxDist+1
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := levenshtein(term, node.term)
+		if d > 0 && (d < bestDist || d == bestDist && t.better(node.term, best)) {
+			best, bestDist = node.term, d
+		}
+		for edge, child := range node.children {
+			if edge >= d-maxDist && edge <= d+maxDist {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return best, best != ""
+}
+
+func (t *bkTree) better(term, than string) bool {
+	if t.df[term] != t.df[than] {
+		return t.df[term] > t.df[than]
+	}
+	return term < than
+}
+
+// maxTypos - допустимое число опечаток: в коротких словах исправление
+// слишком часто превращает одно настоящее слово в другое.
+func maxTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// correctPhrase заменяет во фразе слова, основ которых нет в индексе, на
+// ближайшие слова комиксов. Расстояние считается между словами в написании,
+// а не между основами: стеммер по-разному обрезает слово с опечаткой и без.
+// Операторы, поля и исключения остаются как есть.
+// Возвращает false, если исправлять нечего.
+func (s *Service) correctPhrase(ctx context.Context, phrase string, current *snapshot) (string, bool, error) {
+	tokens, err := lexQuery(phrase)
+	if err != nil {
+		return "", false, err
+	}
+
+	type replacement struct {
+		pos, end int
+		text     string
+	}
+	var candidates []replacement
+	add := func(word string, pos int) {
+		// знаки препинания вокруг слова не считаются опечаткой
+		runes := []rune(word)
+		from, to := 0, len(runes)
+		for from < to && !isWordRune(runes[from]) {
+			from++
+		}
+		for to > from && !isWordRune(runes[to-1]) {
+			to--
+		}
+		if from < to {
+			candidates = append(candidates, replacement{pos: pos + from, end: pos + to, text: string(runes[from:to])})
+		}
+	}
+
+	for i, tok := range tokens {
+		if i > 0 && tokens[i-1].kind == tokenNot {
+			continue
+		}
+		switch tok.kind {
+		case tokenWord:
+			add(tok.text, tok.pos)
+		case tokenPhrase:
+			pos := tok.pos + 1
+			for _, word := range strings.Split(tok.text, " ") {
+				if word != "" {
+					add(word, pos)
+				}
+				pos += len([]rune(word)) + 1
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false, nil
+	}
+
+	// все слова запроса нормализуются одним запросом к words
+	texts := make([]string, len(candidates))
+	for i, c := range candidates {
+		texts[i] = c.text
+	}
+	wordTokens, err := s.words.TokenizeAll(ctx, texts)
+	if err != nil {
+		return "", false, err
+	}
+
+	var replacements []replacement
+	for i, c := range candidates {
+		var stems []string
+		for _, token := range wordTokens[i] {
+			if !token.StopWord && token.Stem != "" {
+				stems = append(stems, token.Stem)
+			}
+		}
+		// стоп-слова и слова из нескольких основ не исправляются
+		if len(stems) != 1 || current.index.postings.lookup(stems[0]).len() > 0 {
+			continue
+		}
+		if fixed, ok := current.vocabulary.get().closest(strings.ToLower(c.text)); ok {
+			c.text = fixed
+			replacements = append(replacements, c)
+		}
+	}
+	if len(replacements) == 0 {
+		return "", false, nil
+	}
+
+	runes := []rune(phrase)
+	var b strings.Builder
+	last := 0
+	for _, r := range replacements {
+		b.WriteString(string(runes[last:r.pos]))
+		b.WriteString(r.text)
+		last = r.end
+	}
+	b.WriteString(string(runes[last:]))
+	return b.String(), true, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,239 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"search-service/search/core"
+	"search-service/words/words"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchTypos(t *testing.T) {
+	indexed := []core.ComicInfo{
+		textComic(1, []string{"relativity"}, []string{"einstein", "train"}, nil),
+		textComic(2, []string{"special", "relativity"}, []string{"light"}, nil),
+		textComic(3, []string{"linux", "kernel"}, []string{"panic"}, nil),
+		textComic(4, []string{"linux"}, []string{"keyboard"}, nil),
+		textComic(5, []string{"kernel"}, []string{"cat"}, nil),
+		textComic(6, []string{"car"}, []string{"cat"}, nil),
+	}
+	testCases := []struct {
+		desc       string
+		phrase     string
+		expected   []int64
+		corrected  string
+		didYouMean string
+	}{
+		{
+			desc:      "no hits - results for corrected query",
+			phrase:    "relativty",
+			expected:  []int64{1, 2},
+			corrected: "relativity",
+		},
+		{
+			desc:       "few hits - only suggestion",
+			phrase:     "kernal special",
+			expected:   []int64{2},
+			didYouMean: "kernel special",
+		},
+		{
+			desc:     "enough hits - no correction",
+			phrase:   "linux kernel cat",
+			expected: []int64{3, 4, 5, 6},
+		},
+		{
+			desc:      "field and phrase kept",
+			phrase:    `title:"specal relativity" OR transcript:einstien`,
+			expected:  []int64{1, 2},
+			corrected: `title:"special relativity" OR transcript:einstein`,
+		},
+		{
+			desc:     "exclusion not corrected",
+			phrase:   "relativity -spesial",
+			expected: []int64{1, 2},
+		},
+		{
+			desc:     "short words not corrected",
+			phrase:   "cst",
+			expected: []int64{},
+		},
+		{
+			desc:     "nothing close",
+			phrase:   "quantum",
+			expected: []int64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockWords := newIndexingWords(ctrl)
+
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+			expectFindComics(mockDB, indexed)
+			mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+			mockWords.EXPECT().Terms(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{FuzzyMinHits: 3})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			req := core.SearchRequest{Phrase: tc.phrase, Limit: 10}
+			for _, search := range []func(context.Context, core.SearchRequest) (core.SearchResult, error){
+				service.ISearch, service.Search,
+			} {
+				result, err := search(context.TODO(), req)
+				require.NoError(t, err)
+				ids := make([]int64, len(result.Comics))
+				for i, comic := range result.Comics {
+					ids[i] = comic.ID
+				}
+				require.ElementsMatch(t, tc.expected, ids)
+				require.Equal(t, tc.corrected, result.CorrectedQuery)
+				require.Equal(t, tc.didYouMean, result.DidYouMean)
+			}
+		})
+	}
+}
+
+func TestSearchTyposOneRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	indexed := []core.ComicInfo{
+		textComic(1, []string{"special", "relativity"}, []string{"einstein"}, nil),
+		textComic(2, []string{"linux", "kernel"}, nil, nil),
+	}
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+	mockWords.EXPECT().Terms(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+	// индексация
+	mockWords.EXPECT().TokenizeAll(gomock.Any(), gomock.Any()).DoAndReturn(fakeTokenizeAll)
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{FuzzyMinHits: 1})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	// все слова запроса, кроме исключенных, - в одном запросе
+	mockWords.EXPECT().TokenizeAll(gomock.Any(), []string{"kernal", "specal", "relativty", "einstien"}).
+		DoAndReturn(fakeTokenizeAll)
+
+	result, err := service.ISearch(context.TODO(), core.SearchRequest{
+		Phrase: `kernal title:"specal relativty" einstien -linx`,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, `kernel title:"special relativity" einstein -linx`, result.CorrectedQuery)
+}
+
+// realWords отвечает как сервис words с настоящим нормализатором.
+func realWords(ctrl *gomock.Controller) *core.MockWords {
+	mockWords := core.NewMockWords(ctrl)
+	norm := func(_ context.Context, phrase string) ([]string, error) {
+		return words.Norm(phrase), nil
+	}
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(norm).AnyTimes()
+	mockWords.EXPECT().Terms(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, phrase string) ([]string, error) {
+			return words.Terms(words.Tokenize(phrase)), nil
+		}).AnyTimes()
+	mockWords.EXPECT().TokenizeAll(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, texts []string) ([][]core.Token, error) {
+			tokens := make([][]core.Token, len(texts))
+			for i, text := range texts {
+				for _, token := range words.Tokenize(text) {
+					tokens[i] = append(tokens[i], core.Token{
+						Text: token.Text, Start: token.Start, End: token.End, Stem: token.Stem, StopWord: token.StopWord,
+					})
+				}
+			}
+			return tokens, nil
+		}).AnyTimes()
+	return mockWords
+}
+
+// realComic индексирует тексты полей настоящим нормализатором, как update.
+func realComic(id int64, title, transcript string) core.ComicInfo {
+	info := core.ComicInfo{Comic: core.Comic{ID: id, Title: title, Transcript: transcript}}
+	info.TitleTerms = words.Terms(words.Tokenize(title))
+	info.TranscriptTerms = words.Terms(words.Tokenize(transcript))
+	info.Terms = slices.Concat(info.TitleTerms, info.TranscriptTerms)
+	info.Words = words.Keywords(words.Tokenize(title + " " + transcript))
+	return info
+}
+
+func TestSearchTyposStemmed(t *testing.T) {
+	indexed := []core.ComicInfo{
+		realComic(1, "Special Relativity", "Einstein rides a train"),
+		realComic(2, "General Relativity", "Light bends around the Sun"),
+		realComic(3, "Linux Kernel", "The kernel panics"),
+	}
+	testCases := []struct {
+		desc      string
+		phrase    string
+		expected  []int64
+		corrected string
+	}{
+		{
+			// основа опечатки relativti далеко от relat, а слово - рядом
+			desc:      "typo changes stem",
+			phrase:    "relativty",
+			expected:  []int64{1, 2},
+			corrected: "relativity",
+		},
+		{
+			desc:      "punctuation around typo kept",
+			phrase:    "relativty,",
+			expected:  []int64{1, 2},
+			corrected: "relativity,",
+		},
+		{
+			desc:      "corrected to word, not stem",
+			phrase:    "einstien",
+			expected:  []int64{1},
+			corrected: "einstein",
+		},
+		{
+			desc:     "other form of known word not corrected",
+			phrase:   "panic",
+			expected: []int64{3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+			expectFindComics(mockDB, indexed)
+
+			service, err := core.NewService(slog.Default(), mockDB, realWords(ctrl), nil, core.Options{FuzzyMinHits: 1})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			for _, search := range []func(context.Context, core.SearchRequest) (core.SearchResult, error){
+				service.ISearch, service.Search,
+			} {
+				result, err := search(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10})
+				require.NoError(t, err)
+				ids := make([]int64, len(result.Comics))
+				for i, comic := range result.Comics {
+					ids[i] = comic.ID
+				}
+				require.ElementsMatch(t, tc.expected, ids)
+				require.Equal(t, tc.corrected, result.CorrectedQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// количество слов перед первым совпадением, которые попадают во фрагмент
+const snippetContext = 3
+
+const ellipsis = "…"
+
+// highlight находит в полях комиксов страницы основы запроса и строит фрагменты
+// с отмеченными совпадениями. Ошибка words не прерывает поиск: комиксы
+// остаются без подсветки.
+func (s *Service) highlight(ctx context.Context, comics []Comic, idx *invertedIndex, query queryNode) {
+	if s.opts.SnippetSize <= 0 {
+		return
+	}
+	terms := map[string]bool{}
+	for _, term := range keywords(query) {
+		terms[term] = true
+	}
+	if len(terms) == 0 {
+		return
+	}
+
+	// поля всей страницы разбиваются на слова одним запросом к words
+	type fieldText struct {
+		comic int
+		name  string
+		text  string
+	}
+	var fields []fieldText
+	var texts []string
+	for i, comic := range comics {
+		for _, field := range []struct {
+			name string
+			text string
+		}{
+			{name: FieldTitle, text: comic.Title},
+			{name: FieldAlt, text: comic.Alt},
+			{name: FieldTranscript, text: comic.Transcript},
+		} {
+			if strings.TrimSpace(field.text) == "" || !idx.mayContain(comic.ID, field.name, terms) {
+				continue
+			}
+			fields = append(fields, fieldText{comic: i, name: field.name, text: field.text})
+			texts = append(texts, field.text)
+		}
+	}
+	if len(texts) == 0 {
+		return
+	}
+	tokens, err := s.words.TokenizeAll(ctx, texts)
+	if err != nil {
+		s.log.Warn("failed to tokenize comic fields", "fields", len(texts), "error", err)
+		return
+	}
+	for i, field := range fields {
+		if match, ok := s.matchField(field.name, field.text, tokens[i], terms); ok {
+			comics[field.comic].Matches = append(comics[field.comic].Matches, match)
+		}
+	}
+}
+
+// mayContain сообщает, что в поле комикса может быть одна из основ. Для комиксов
+// без основ по полям ответ всегда положительный: проверит токенизация текста.
+func (idx *invertedIndex) mayContain(id int64, field string, terms map[string]bool) bool {
+	doc, ok := idx.docs.get(id)
+	if !ok {
+		return true
+	}
+	if len(doc.fields[field]) == 0 {
+		for name := range queryFields {
+			if len(doc.fields[name]) > 0 {
+				return false
+			}
+		}
+		return true
+	}
+	for _, term := range doc.fields[field] {
+		if terms[term] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) matchField(field, text string, tokens []Token, terms map[string]bool) (FieldMatch, bool) {
+	matched := make([]bool, len(tokens))
+	match := FieldMatch{Field: field}
+	for i, token := range tokens {
+		if token.StopWord || !terms[token.Stem] {
+			continue
+		}
+		matched[i] = true
+		if !slices.Contains(match.Terms, token.Stem) {
+			match.Terms = append(match.Terms, token.Stem)
+		}
+	}
+	if len(match.Terms) == 0 {
+		return FieldMatch{}, false
+	}
+	match.Snippet = s.snippet(text, tokens, matched)
+	return match, true
+}
+
+// snippet выбирает окно текста не длиннее SnippetSize с наибольшим количеством
+// совпадений и отмечает в нем совпавшие слова. Окно начинается за несколько слов
+// до первого совпадения и режется по границам слов.
+func (s *Service) snippet(text string, tokens []Token, matched []bool) string {
+	bestFrom, bestTo, bestCount := 0, -1, 0
+	for i := range tokens {
+		if !matched[i] {
+			continue
+		}
+		from := max(0, i-snippetContext)
+		for from < i && tokens[i].End-tokens[from].Start > s.opts.SnippetSize {
+			from++
+		}
+		to, count := from, 0
+		for j := from; j < len(tokens) && tokens[j].End-tokens[from].Start <= s.opts.SnippetSize; j++ {
+			to = j
+			if matched[j] {
+				count++
+			}
+		}
+		// слово длиннее фрагмента все равно показывается целиком
+		if to < i {
+			to, count = i, 1
+		}
+		if count > bestCount {
+			bestFrom, bestTo, bestCount = from, to, count
+		}
+	}
+
+	// многоточие ставится только там, где отрезаны слова
+	var b strings.Builder
+	last := tokens[bestFrom].Start
+	if bestFrom == 0 {
+		last = len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+	} else {
+		b.WriteString(ellipsis)
+	}
+	for i := bestFrom; i <= bestTo; i++ {
+		b.WriteString(collapseSpaces(text[last:tokens[i].Start]))
+		if matched[i] {
+			b.WriteString(s.opts.HighlightPreTag)
+			b.WriteString(text[tokens[i].Start:tokens[i].End])
+			b.WriteString(s.opts.HighlightPostTag)
+		} else {
+			b.WriteString(text[tokens[i].Start:tokens[i].End])
+		}
+		last = tokens[i].End
+	}
+	if bestTo == len(tokens)-1 {
+		b.WriteString(collapseSpaces(strings.TrimRightFunc(text[last:], unicode.IsSpace)))
+	} else {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+// collapseSpaces заменяет пробелы и переводы строк между словами одним пробелом.
+func collapseSpaces(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsSpace) {
+		return s
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return " "
+	}
+	joined := strings.Join(fields, " ")
+	if unicode.IsSpace(rune(s[0])) {
+		joined = " " + joined
+	}
+	if unicode.IsSpace(rune(s[len(s)-1])) {
+		joined += " "
+	}
+	return joined
+}
@@ -0,0 +1,211 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"search-service/search/core"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeTokenize делит текст на слова по пробелам и знакам препинания,
+// основа - слово в нижнем регистре, как в fakeNorm.
+func fakeTokenize(_ context.Context, text string) ([]core.Token, error) {
+	var tokens []core.Token
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := text[start:end]
+		stem := strings.ToLower(word)
+		tokens = append(tokens, core.Token{
+			Text:     word,
+			Start:    start,
+			End:      end,
+			Stem:     stem,
+			StopWord: stem == "the" || stem == "a",
+		})
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return tokens, nil
+}
+
+// fakeTokenizeAll - fakeTokenize для пакета текстов.
+func fakeTokenizeAll(ctx context.Context, texts []string) ([][]core.Token, error) {
+	tokens := make([][]core.Token, len(texts))
+	for i, text := range texts {
+		tokens[i], _ = fakeTokenize(ctx, text)
+	}
+	return tokens, nil
+}
+
+// newIndexingWords возвращает words, который разбивает тексты комиксов при индексации.
+func newIndexingWords(ctrl *gomock.Controller) *core.MockWords {
+	mockWords := core.NewMockWords(ctrl)
+	mockWords.EXPECT().TokenizeAll(gomock.Any(), gomock.Any()).DoAndReturn(fakeTokenizeAll).AnyTimes()
+	return mockWords
+}
+
+func TestSearchHighlight(t *testing.T) {
+	comics := []core.Comic{
+		{ID: 1, Title: "Linux Kernel", Alt: "The kernel is fine.", Transcript: "A cat sits on a keyboard."},
+		{ID: 2, Title: "Cat", Transcript: "Long ago, in a galaxy far away,\nthere was a cat who typed sudo on a linux terminal every single day."},
+	}
+	infos := []core.ComicInfo{
+		fieldComic(1, []string{"linux", "kernel"}, []string{"cat", "sit", "keyboard"}, []string{"kernel", "fine"}),
+		fieldComic(2, []string{"cat"}, []string{"long", "ago", "galaxi", "far", "away", "cat", "type", "sudo", "linux", "termin", "everi", "singl", "day"}, nil),
+	}
+	for i := range infos {
+		infos[i].Comic = comics[i]
+	}
+
+	testCases := []struct {
+		desc     string
+		phrase   string
+		expected map[int64][]core.FieldMatch
+	}{
+		{
+			desc:   "matches in several fields",
+			phrase: "kernel",
+			expected: map[int64][]core.FieldMatch{
+				1: {
+					{Field: core.FieldTitle, Terms: []string{"kernel"}, Snippet: "Linux <b>Kernel</b>"},
+					{Field: core.FieldAlt, Terms: []string{"kernel"}, Snippet: "The <b>kernel</b> is fine."},
+				},
+			},
+		},
+		{
+			desc:   "long text is cut around matches",
+			phrase: "sudo linux",
+			expected: map[int64][]core.FieldMatch{
+				1: {
+					{Field: core.FieldTitle, Terms: []string{"linux"}, Snippet: "<b>Linux</b> Kernel"},
+				},
+				2: {
+					{Field: core.FieldTranscript, Terms: []string{"sudo", "linux"}, Snippet: "…cat who typed <b>sudo</b> on a <b>linux</b> terminal…"},
+				},
+			},
+		},
+		{
+			desc:   "only positive terms highlighted",
+			phrase: "cat -kernel",
+			expected: map[int64][]core.FieldMatch{
+				2: {
+					{Field: core.FieldTitle, Terms: []string{"cat"}, Snippet: "<b>Cat</b>"},
+					{Field: core.FieldTranscript, Terms: []string{"cat"}, Snippet: "…there was a <b>cat</b> who typed sudo on a…"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockWords := newIndexingWords(ctrl)
+
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(infos, nil)
+			mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{
+				HighlightPreTag:  "<b>",
+				HighlightPostTag: "</b>",
+				SnippetSize:      40,
+			})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10})
+			require.NoError(t, err)
+			require.Equal(t, "<b>", result.HighlightPreTag)
+			require.Equal(t, "</b>", result.HighlightPostTag)
+			require.Len(t, result.Comics, len(tc.expected))
+			for _, comic := range result.Comics {
+				require.Equal(t, tc.expected[comic.ID], comic.Matches, "comic %d", comic.ID)
+			}
+		})
+	}
+}
+
+func TestSearchHighlightWordsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+
+	info := fieldComic(1, []string{"linux"}, nil, nil)
+	info.Title = "Linux"
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{info}, nil)
+	mockWords.EXPECT().Norm(gomock.Any(), "linux").Return([]string{"linux"}, nil)
+	gomock.InOrder(
+		// индексация
+		mockWords.EXPECT().TokenizeAll(gomock.Any(), []string{"Linux"}).DoAndReturn(fakeTokenizeAll),
+		// подсветка
+		mockWords.EXPECT().TokenizeAll(gomock.Any(), []string{"Linux"}).Return(nil, errors.New("words unavailable")),
+	)
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{SnippetSize: 100})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	// без подсветки комикс все равно находится
+	result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Comics, 1)
+	require.Empty(t, result.Comics[0].Matches)
+}
+
+func TestSearchHighlightOneRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+
+	infos := []core.ComicInfo{
+		textComic(1, []string{"linux", "kernel"}, []string{"linux", "user"}, []string{"linux"}),
+		textComic(2, []string{"linux"}, []string{"cat"}, []string{"linux", "sudo"}),
+		textComic(3, []string{"linux"}, nil, nil),
+	}
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(infos, nil)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+	// индексация
+	mockWords.EXPECT().TokenizeAll(gomock.Any(), gomock.Any()).DoAndReturn(fakeTokenizeAll)
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{SnippetSize: 100})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	// все поля с совпадениями на странице - в одном запросе, поля без них не разбиваются
+	mockWords.EXPECT().TokenizeAll(gomock.Any(), []string{
+		"linux kernel", "linux", "linux user",
+		"linux", "linux sudo",
+		"linux",
+	}).DoAndReturn(fakeTokenizeAll)
+
+	result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10, Sort: core.SortIDAsc})
+	require.NoError(t, err)
+	require.Len(t, result.Comics, 3)
+	for _, comic := range result.Comics {
+		require.NotEmpty(t, comic.Matches, "comic %d", comic.ID)
+	}
+}
@@ -0,0 +1,436 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+)
+
+// document - статистика комикса для ранжирования и основы полей для фраз.
+type document struct {
+	length int            // длина комикса в терминах с повторами
+	unique int            // количество уникальных слов
+	tf     map[string]int // частоты терминов
+	// основы полей в порядке следования; "" - все описание
+	fields map[string][]string
+	info   ComicInfo // исходные данные для выдачи и снимка индекса
+}
+
+// fieldTF считает частоты слов words по полям комикса. У комиксов,
+// сохраненных до разделения по полям, частот по полям нет.
+func (doc *document) fieldTF(words map[string]int) map[string]map[string]int {
+	if len(doc.fields) == 0 || len(words) == 0 {
+		return nil
+	}
+	tf := make(map[string]map[string]int, len(words))
+	for field, terms := range doc.fields {
+		// "" - все описание, его вхождения уже учтены в полях
+		if field == "" {
+			continue
+		}
+		for _, term := range terms {
+			if _, ok := words[term]; !ok {
+				continue
+			}
+			if tf[term] == nil {
+				tf[term] = map[string]int{}
+			}
+			tf[term][field]++
+		}
+	}
+	return tf
+}
+
+// invertedIndex хранит для каждого термина комиксы, в которых он встречается,
+// отдельно по всему описанию и по полям, и статистику комиксов для ранжирования.
+// Словари версии, полученной clone, хранят только изменения поверх исходной.
+type invertedIndex struct {
+	postings *layeredMap[string, postingList]
+	fields   map[string]*layeredMap[string, postingList]
+	docs     *layeredMap[int64, *document]
+	totalLen int
+	maxID    int64
+	phonetic *layeredMap[string, postingList]
+	// статистика всей коллекции, если индекс построен только по части комиксов
+	stats *CorpusStats
+}
+
+func newInvertedIndex() *invertedIndex {
+	idx := &invertedIndex{
+		postings: newLayeredMap[string, postingList](),
+		fields:   map[string]*layeredMap[string, postingList]{},
+		docs:     newLayeredMap[int64, *document](),
+		phonetic: newLayeredMap[string, postingList](),
+	}
+	for field := range queryFields {
+		idx.fields[field] = newLayeredMap[string, postingList]()
+	}
+	return idx
+}
+
+// buildIndex строит индекс по комиксам. Комиксы добавляются по возрастанию id,
+// чтобы списки комиксов только дописывались.
+func buildIndex(comicsInfo []ComicInfo) *invertedIndex {
+	idx := newInvertedIndex()
+	sorted := slices.SortedFunc(slices.Values(comicsInfo), func(a, b ComicInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for _, info := range sorted {
+		idx.add(info)
+	}
+	return idx
+}
+
+func (idx *invertedIndex) add(info ComicInfo) {
+	frequencies, terms := termFrequencies(info)
+	doc := &document{
+		length: len(terms),
+		unique: len(info.Words),
+		tf:     frequencies,
+		fields: map[string][]string{"": terms},
+		info:   info,
+	}
+	for _, term := range info.Words {
+		if frequencies[term] > 0 {
+			addPosting(idx.postings, term, info.ID)
+		}
+	}
+	// комиксы, сохраненные до разделения по полям, находятся только без поля
+	for field, fieldTerms := range info.fieldTerms() {
+		doc.fields[field] = fieldTerms
+		seen := map[string]bool{}
+		for _, term := range fieldTerms {
+			if !seen[term] {
+				seen[term] = true
+				addPosting(idx.fields[field], term, info.ID)
+			}
+		}
+	}
+	idx.docs.set(info.ID, doc)
+	idx.totalLen += doc.length
+	idx.maxID = max(idx.maxID, info.ID)
+
+	for _, code := range info.Phonetics {
+		addPosting(idx.phonetic, code, info.ID)
+	}
+}
+
+// remove убирает комикс из индекса. Списки комиксов не меняются на месте:
+// их может читать прежняя версия индекса.
+func (idx *invertedIndex) remove(id int64) {
+	doc, ok := idx.docs.get(id)
+	if !ok {
+		return
+	}
+	for term := range doc.tf {
+		removePosting(idx.postings, term, id)
+	}
+	for field, terms := range doc.fields {
+		if postings, ok := idx.fields[field]; ok {
+			for _, term := range terms {
+				removePosting(postings, term, id)
+			}
+		}
+	}
+	for _, code := range doc.info.Phonetics {
+		removePosting(idx.phonetic, code, id)
+	}
+
+	idx.docs.delete(id)
+	idx.totalLen -= doc.length
+	if id == idx.maxID {
+		idx.maxID = 0
+		for other := range idx.docs.all() {
+			idx.maxID = max(idx.maxID, other)
+		}
+	}
+}
+
+// addPosting дописывает id в список term. Список, унаследованный от прежней
+// версии, обрезается по длине, чтобы append его скопировал.
+func addPosting(postings *layeredMap[string, postingList], term string, id int64) {
+	list := postings.lookup(term)
+	if !postings.owns(term) {
+		list.data = slices.Clip(list.data)
+	}
+	postings.set(term, list.with(id))
+}
+
+func removePosting(postings *layeredMap[string, postingList], term string, id int64) {
+	list, ok := postings.get(term)
+	if !ok {
+		return
+	}
+	rest := list.without(id)
+	if rest.len() == 0 {
+		postings.delete(term)
+		return
+	}
+	postings.set(term, rest)
+}
+
+// stored возвращает сохраненные в индексе поля комиксов в порядке ids.
+func (idx *invertedIndex) stored(ids []int64) []Comic {
+	comics := make([]Comic, 0, len(ids))
+	for _, id := range ids {
+		if doc, ok := idx.docs.get(id); ok {
+			comics = append(comics, doc.info.Comic)
+		}
+	}
+	return comics
+}
+
+// comics возвращает исходные данные комиксов индекса по возрастанию id.
+func (idx *invertedIndex) comics() []ComicInfo {
+	comics := make([]ComicInfo, 0, idx.docs.len())
+	for _, doc := range idx.docs.all() {
+		comics = append(comics, doc.info)
+	}
+	slices.SortFunc(comics, func(a, b ComicInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return comics
+}
+
+// clone возвращает версию индекса, которую можно менять, не затрагивая исходную.
+// Новая версия пишет только измененные термины и комиксы в свои слои поверх
+// словарей исходной, длинная цепочка слоев сливается в новые словари.
+func (idx *invertedIndex) clone() *invertedIndex {
+	layer := func(m *layeredMap[string, postingList]) *layeredMap[string, postingList] {
+		if m.depth >= maxLayers {
+			// списки обрезаны по длине, поэтому append их копирует
+			return m.flatten(func(list postingList) postingList {
+				list.data = slices.Clip(list.data)
+				return list
+			})
+		}
+		return m.layer()
+	}
+	clone := &invertedIndex{
+		postings: layer(idx.postings),
+		fields:   make(map[string]*layeredMap[string, postingList], len(idx.fields)),
+		totalLen: idx.totalLen,
+		maxID:    idx.maxID,
+		phonetic: layer(idx.phonetic),
+		stats:    idx.stats,
+	}
+	for field, postings := range idx.fields {
+		clone.fields[field] = layer(postings)
+	}
+	if idx.docs.depth >= maxLayers {
+		clone.docs = idx.docs.flatten(func(doc *document) *document { return doc })
+	} else {
+		clone.docs = idx.docs.layer()
+	}
+	return clone
+}
+
+// size возвращает количество терминов, записей в списках комиксов и объем
+// списков вместе с терминами в байтах.
+func (idx *invertedIndex) size() (terms, postings, bytes int) {
+	count := func(lists *layeredMap[string, postingList]) {
+		for term, list := range lists.all() {
+			postings += list.len()
+			bytes += len(term) + list.size()
+		}
+	}
+	count(idx.postings)
+	for _, lists := range idx.fields {
+		count(lists)
+	}
+	count(idx.phonetic)
+	return idx.postings.len(), postings, bytes
+}
+
+// candidates возвращает комиксы, удовлетворяющие запросу, с частотами ключевых
+// слов и статистику коллекции для ранжирования.
+func (idx *invertedIndex) candidates(query queryNode) ([]Candidate, Corpus) {
+	keywords := keywords(query)
+	corpus := idx.corpus(keywords)
+
+	matched := idx.match(query)
+	candidates := make([]Candidate, 0, len(matched))
+	for _, id := range matched {
+		doc := idx.docs.lookup(id)
+		candidate := Candidate{
+			ID:     id,
+			Unique: doc.unique,
+			Length: doc.length,
+			TF:     map[string]int{},
+		}
+		for _, keyword := range keywords {
+			if tf := doc.tf[keyword]; tf > 0 {
+				candidate.Matched++
+				candidate.TF[keyword] = tf
+				candidate.Terms = append(candidate.Terms, keyword)
+			}
+		}
+		candidate.FieldTF = doc.fieldTF(candidate.TF)
+		candidates = append(candidates, candidate)
+	}
+	return candidates, corpus
+}
+
+func (idx *invertedIndex) corpus(keywords []string) Corpus {
+	if idx.stats != nil {
+		corpus := Corpus{Docs: idx.stats.Docs, DF: idx.stats.DF, MaxID: idx.stats.MaxID}
+		if corpus.Docs > 0 {
+			corpus.AvgLen = float64(idx.stats.TotalLen) / float64(corpus.Docs)
+		}
+		return corpus
+	}
+
+	corpus := Corpus{
+		Docs:  idx.docs.len(),
+		DF:    make(map[string]int, len(keywords)),
+		MaxID: idx.maxID,
+	}
+	if corpus.Docs > 0 {
+		corpus.AvgLen = float64(idx.totalLen) / float64(corpus.Docs)
+	}
+	for _, keyword := range keywords {
+		corpus.DF[keyword] = idx.postings.lookup(keyword).len()
+	}
+	return corpus
+}
+
+// vocabulary возвращает слова комиксов в исходном написании с количеством
+// комиксов, в которых есть их основа. Слова, основ которых нет в индексе, не
+// попадают в словарь: по ним ничего не найдется.
+func (idx *invertedIndex) vocabulary() map[string]int {
+	df := map[string]int{}
+	for _, doc := range idx.docs.all() {
+		for _, form := range doc.info.Forms {
+			if n := idx.postings.lookup(form.Stem).len(); n > 0 {
+				df[form.Word] = n
+			}
+		}
+	}
+	return df
+}
+
+// match возвращает комиксы, удовлетворяющие условию, по возрастанию id.
+func (idx *invertedIndex) match(node queryNode) []int64 {
+	switch n := node.(type) {
+	case *termsNode:
+		var ids []int64
+		for _, term := range n.terms {
+			ids = union(ids, idx.lookup(n.field, term).ids())
+		}
+		return ids
+	case *phraseNode:
+		if len(n.terms) == 0 {
+			return nil
+		}
+		// фраза проверяется только в комиксах, где есть все ее слова
+		ids := idx.lookup(n.field, n.terms[0]).ids()
+		for _, term := range n.terms[1:] {
+			ids = intersect(ids, idx.lookup(n.field, term).ids())
+		}
+		return slices.DeleteFunc(ids, func(id int64) bool {
+			return !containsSequence(idx.docs.lookup(id).fields[n.field], n.terms)
+		})
+	case *boolNode:
+		return idx.matchBool(n)
+	}
+	// отрицание без положительных условий ничего не находит
+	return nil
+}
+
+// matchBool объединяет или пересекает положительные условия группы
+// и вычитает из результата исключенные. Условия только из стоп-слов пропускаются.
+func (idx *invertedIndex) matchBool(n *boolNode) []int64 {
+	var result, excluded []int64
+	matched := false
+	for _, child := range n.children {
+		if not, ok := child.(*notNode); ok {
+			if !isEmptyCondition(not.child) {
+				excluded = union(excluded, idx.match(not.child))
+			}
+			continue
+		}
+		if isEmptyCondition(child) {
+			continue
+		}
+		ids := idx.match(child)
+		switch {
+		case !matched:
+			result, matched = ids, true
+		case n.and:
+			result = intersect(result, ids)
+		default:
+			result = union(result, ids)
+		}
+	}
+	return subtract(result, excluded)
+}
+
+func (idx *invertedIndex) lookup(field, term string) postingList {
+	if field == "" {
+		return idx.postings.lookup(term)
+	}
+	if postings, ok := idx.fields[field]; ok {
+		return postings.lookup(term)
+	}
+	return postingList{}
+}
+
+// phoneticMatches возвращает количество совпавших фонетических кодов для каждого комикса.
+func (idx *invertedIndex) phoneticMatches(codes []string) map[int64]int {
+	matches := map[int64]int{}
+	for _, code := range codes {
+		for _, id := range idx.phonetic.lookup(code).ids() {
+			matches[id]++
+		}
+	}
+	return matches
+}
+
+// isEmptyCondition сообщает, что после нормализации в условии не осталось слов,
+// например оно состояло из стоп-слов.
+func isEmptyCondition(node queryNode) bool {
+	switch n := node.(type) {
+	case *termsNode:
+		return len(n.terms) == 0
+	case *phraseNode:
+		return len(n.terms) == 0
+	case *notNode:
+		return isEmptyCondition(n.child)
+	case *boolNode:
+		for _, child := range n.children {
+			if !isEmptyCondition(child) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func containsSequence(terms, sequence []string) bool {
+	for i := 0; i+len(sequence) <= len(terms); i++ {
+		match := true
+		for j, term := range sequence {
+			if terms[i+j] != term {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// termFrequencies считает частоты терминов комикса и возвращает термины по порядку.
+// Комиксы, сохраненные до появления частот, считаются по уникальным словам.
+func termFrequencies(info ComicInfo) (map[string]int, []string) {
+	terms := info.Terms
+	if len(terms) == 0 {
+		terms = info.Words
+	}
+	frequencies := make(map[string]int, len(info.Words))
+	for _, term := range terms {
+		frequencies[term]++
+	}
+	return frequencies, terms
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFieldTFFromIndex(t *testing.T) {
+	idx := buildIndex([]ComicInfo{{
+		Comic:           Comic{ID: 1},
+		Words:           []string{"linux", "kernel"},
+		Terms:           []string{"linux", "linux", "kernel"},
+		TitleTerms:      []string{"linux"},
+		TranscriptTerms: []string{"linux", "kernel"},
+	}})
+	candidates, corpus := idx.candidates(&termsNode{text: "linux", terms: []string{"linux"}})
+	require.Len(t, candidates, 1)
+	require.Equal(t, map[string]map[string]int{
+		"linux": {FieldTitle: 1, FieldTranscript: 1},
+	}, candidates[0].FieldTF)
+
+	ranker := bm25Ranker{k1: 1.2, b: 0.75, boosts: map[string]float64{FieldTitle: 2}}
+	// заголовок с весом 2 и транскрипт с весом 1
+	require.Equal(t, 3.0, ranker.weightedTF(candidates[0], "linux"))
+	explanation := ranker.Explain(candidates[0], corpus)
+	require.Equal(t, 1.5, explanation.Terms[0].FieldWeight)
+}
+
+// BenchmarkApplyDelta сравнивает изменение одного комикса, после которого
+// словари новой версии строятся при первом обращении, с прежней пересборкой
+// всех словарей на каждое событие.
+func BenchmarkApplyDelta(b *testing.B) {
+	comics := benchCorpus(3000, 20000, 150)
+	byID := make(map[int64]ComicInfo, len(comics))
+	for i := range comics {
+		for _, word := range comics[i].Words {
+			comics[i].Forms = append(comics[i].Forms, WordForm{Word: word, Stem: word})
+		}
+		byID[comics[i].ID] = comics[i]
+	}
+
+	ctrl := gomock.NewController(b)
+	mockDB := NewMockDB(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(comics, nil).AnyTimes()
+	mockDB.EXPECT().GetComicsInfoByIds(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, ids []int64) ([]ComicInfo, error) {
+			return []ComicInfo{byID[ids[0]]}, nil
+		}).AnyTimes()
+
+	for _, bc := range []struct {
+		name  string
+		eager bool
+	}{
+		{name: "lazy"},
+		{name: "eager", eager: true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			service, err := NewService(slog.New(slog.DiscardHandler), mockDB, NewMockWords(ctrl), nil, Options{})
+			require.NoError(b, err)
+			require.NoError(b, service.UpdateIndex(context.TODO()))
+
+			id := int64(0)
+			for b.Loop() {
+				id = id%int64(len(comics)) + 1
+				err := service.HandleEvent(context.TODO(), Event{Type: EventUpdate, Updated: []int64{id}})
+				require.NoError(b, err)
+				if bc.eager {
+					service.current.Load().warm()
+				}
+			}
+		})
+	}
+}
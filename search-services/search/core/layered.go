@@ -0,0 +1,125 @@
+package core
+
+import "iter"
+
+// maxLayers - длина цепочки слоев, после которой версия индекса сливает их
+// в один: поиск по ключу проходит все слои.
+const maxLayers = 32
+
+// layeredMap - словарь версии индекса поверх словарей прежних версий. Новая
+// версия пишет только измененные ключи в свой слой, нижние слои общие
+// с прежними версиями и не меняются.
+type layeredMap[K comparable, V any] struct {
+	own    map[K]V
+	gone   map[K]bool // ключи нижних слоев, удаленные в этом слое
+	parent *layeredMap[K, V]
+	depth  int
+	size   int
+}
+
+func newLayeredMap[K comparable, V any]() *layeredMap[K, V] {
+	return &layeredMap[K, V]{own: map[K]V{}, gone: map[K]bool{}, depth: 1}
+}
+
+// layer возвращает словарь следующей версии. После этого m не меняется.
+func (m *layeredMap[K, V]) layer() *layeredMap[K, V] {
+	return &layeredMap[K, V]{own: map[K]V{}, gone: map[K]bool{}, parent: m, depth: m.depth + 1, size: m.size}
+}
+
+func (m *layeredMap[K, V]) get(key K) (V, bool) {
+	for l := m; l != nil; l = l.parent {
+		if value, ok := l.own[key]; ok {
+			return value, true
+		}
+		if l.gone[key] {
+			break
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// lookup - get без признака наличия, как у обычного map.
+func (m *layeredMap[K, V]) lookup(key K) V {
+	value, _ := m.get(key)
+	return value
+}
+
+// owns сообщает, что значение key записано в этой версии, а не унаследовано.
+func (m *layeredMap[K, V]) owns(key K) bool {
+	_, ok := m.own[key]
+	return ok
+}
+
+func (m *layeredMap[K, V]) set(key K, value V) {
+	if _, ok := m.get(key); !ok {
+		m.size++
+	}
+	m.own[key] = value
+	delete(m.gone, key)
+}
+
+func (m *layeredMap[K, V]) delete(key K) {
+	if _, ok := m.get(key); !ok {
+		return
+	}
+	m.size--
+	delete(m.own, key)
+	if m.parent != nil {
+		if _, ok := m.parent.get(key); ok {
+			m.gone[key] = true
+		}
+	}
+}
+
+func (m *layeredMap[K, V]) len() int {
+	return m.size
+}
+
+// all перечисляет записи всех слоев, верхние закрывают нижние.
+func (m *layeredMap[K, V]) all() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.parent == nil {
+			for key, value := range m.own {
+				if !yield(key, value) {
+					return
+				}
+			}
+			return
+		}
+		seen := make(map[K]bool, m.size)
+		for l := m; l != nil; l = l.parent {
+			for key, value := range l.own {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				if !yield(key, value) {
+					return
+				}
+			}
+			for key := range l.gone {
+				seen[key] = true
+			}
+		}
+	}
+}
+
+func (m *layeredMap[K, V]) keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.all() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// flatten сливает слои в один новый словарь, значения проходят через private.
+func (m *layeredMap[K, V]) flatten(private func(V) V) *layeredMap[K, V] {
+	flat := &layeredMap[K, V]{own: make(map[K]V, m.size), gone: map[K]bool{}, depth: 1, size: m.size}
+	for key, value := range m.all() {
+		flat.own[key] = private(value)
+	}
+	return flat
+}
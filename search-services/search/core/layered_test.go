@@ -0,0 +1,80 @@
+package core
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayeredMap(t *testing.T) {
+	base := newLayeredMap[string, int]()
+	base.set("a", 1)
+	base.set("b", 2)
+
+	next := base.layer()
+	next.set("b", 20)
+	next.set("c", 3)
+	next.delete("a")
+	next.delete("missing")
+
+	// нижний слой не меняется
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, maps.Collect(base.all()))
+	require.Equal(t, 2, base.len())
+
+	require.Equal(t, map[string]int{"b": 20, "c": 3}, maps.Collect(next.all()))
+	require.Equal(t, 2, next.len())
+	_, ok := next.get("a")
+	require.False(t, ok)
+	require.True(t, next.owns("b"))
+
+	// удаленный ключ можно вернуть в следующей версии
+	last := next.layer()
+	last.set("a", 10)
+	require.False(t, last.owns("b"))
+	require.Equal(t, map[string]int{"a": 10, "b": 20, "c": 3}, maps.Collect(last.all()))
+	require.Equal(t, 3, last.len())
+
+	flat := last.flatten(func(v int) int { return v })
+	require.Equal(t, 1, flat.depth)
+	require.Equal(t, maps.Collect(last.all()), flat.own)
+	require.Equal(t, 3, flat.len())
+}
+
+func TestIndexCloneLayers(t *testing.T) {
+	comics := []ComicInfo{
+		{Comic: Comic{ID: 1}, Words: []string{"linux", "kernel"}, Phonetics: []string{"LNKS"}},
+		{Comic: Comic{ID: 2}, Words: []string{"linux"}, TitleTerms: []string{"linux"}},
+		{Comic: Comic{ID: 3}, Words: []string{"cat"}},
+	}
+	idx := buildIndex(comics)
+	versions := []*invertedIndex{idx}
+
+	// больше версий, чем слоев, чтобы цепочка слилась
+	for i := range 2 * maxLayers {
+		next := versions[len(versions)-1].clone()
+		info := comics[i%len(comics)]
+		next.remove(info.ID)
+		if i%2 == 0 {
+			info.Words = append([]string{"update"}, info.Words...)
+		}
+		next.add(info)
+		versions = append(versions, next)
+	}
+
+	last := versions[len(versions)-1]
+	require.Less(t, last.postings.depth, maxLayers+1)
+	// слои дают тот же индекс, что сборка с нуля
+	fresh := buildIndex(last.comics())
+	terms, postings, bytes := fresh.size()
+	gotTerms, gotPostings, gotBytes := last.size()
+	require.Equal(t, []int{terms, postings, bytes}, []int{gotTerms, gotPostings, gotBytes})
+	require.Equal(t, maps.Collect(fresh.postings.all()), maps.Collect(last.postings.all()))
+	require.Equal(t, []int64{3}, last.lookup("", "update").ids())
+	require.Equal(t, []int64{2}, last.lookup(FieldTitle, "linux").ids())
+	require.Equal(t, []int64{1}, last.phonetic.lookup("LNKS").ids())
+
+	// исходная версия не изменилась
+	require.Equal(t, comics, idx.comics())
+	require.Equal(t, 0, idx.postings.lookup("update").len())
+}
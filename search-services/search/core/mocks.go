@@ -40,6 +40,36 @@ func (m *MockDB) EXPECT() *MockDBMockRecorder {
 	return m.recorder
 }
 
+// CorpusStats mocks base method.
+func (m *MockDB) CorpusStats(ctx context.Context, words []string) (CorpusStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CorpusStats", ctx, words)
+	ret0, _ := ret[0].(CorpusStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CorpusStats indicates an expected call of CorpusStats.
+func (mr *MockDBMockRecorder) CorpusStats(ctx, words any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CorpusStats", reflect.TypeOf((*MockDB)(nil).CorpusStats), ctx, words)
+}
+
+// FindComicsInfo mocks base method.
+func (m *MockDB) FindComicsInfo(ctx context.Context, words []string) ([]ComicInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindComicsInfo", ctx, words)
+	ret0, _ := ret[0].([]ComicInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindComicsInfo indicates an expected call of FindComicsInfo.
+func (mr *MockDBMockRecorder) FindComicsInfo(ctx, words any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindComicsInfo", reflect.TypeOf((*MockDB)(nil).FindComicsInfo), ctx, words)
+}
+
 // GetAllComicsInfo mocks base method.
 func (m *MockDB) GetAllComicsInfo(ctx context.Context) ([]ComicInfo, error) {
 	m.ctrl.T.Helper()
@@ -55,19 +85,19 @@ func (mr *MockDBMockRecorder) GetAllComicsInfo(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllComicsInfo", reflect.TypeOf((*MockDB)(nil).GetAllComicsInfo), ctx)
 }
 
-// GetComicsByIds mocks base method.
-func (m *MockDB) GetComicsByIds(ctx context.Context, ids []int64) ([]Comic, error) {
+// GetComicsInfoByIds mocks base method.
+func (m *MockDB) GetComicsInfoByIds(ctx context.Context, ids []int64) ([]ComicInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetComicsByIds", ctx, ids)
-	ret0, _ := ret[0].([]Comic)
+	ret := m.ctrl.Call(m, "GetComicsInfoByIds", ctx, ids)
+	ret0, _ := ret[0].([]ComicInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetComicsByIds indicates an expected call of GetComicsByIds.
-func (mr *MockDBMockRecorder) GetComicsByIds(ctx, ids any) *gomock.Call {
+// GetComicsInfoByIds indicates an expected call of GetComicsInfoByIds.
+func (mr *MockDBMockRecorder) GetComicsInfoByIds(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComicsByIds", reflect.TypeOf((*MockDB)(nil).GetComicsByIds), ctx, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComicsInfoByIds", reflect.TypeOf((*MockDB)(nil).GetComicsInfoByIds), ctx, ids)
 }
 
 // MockWords is a mock of Words interface.
@@ -109,6 +139,51 @@ func (mr *MockWordsMockRecorder) Norm(ctx, phrase any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Norm", reflect.TypeOf((*MockWords)(nil).Norm), ctx, phrase)
 }
 
+// Phonetics mocks base method.
+func (m *MockWords) Phonetics(ctx context.Context, phrase string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Phonetics", ctx, phrase)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Phonetics indicates an expected call of Phonetics.
+func (mr *MockWordsMockRecorder) Phonetics(ctx, phrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Phonetics", reflect.TypeOf((*MockWords)(nil).Phonetics), ctx, phrase)
+}
+
+// Terms mocks base method.
+func (m *MockWords) Terms(ctx context.Context, phrase string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Terms", ctx, phrase)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Terms indicates an expected call of Terms.
+func (mr *MockWordsMockRecorder) Terms(ctx, phrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Terms", reflect.TypeOf((*MockWords)(nil).Terms), ctx, phrase)
+}
+
+// TokenizeAll mocks base method.
+func (m *MockWords) TokenizeAll(ctx context.Context, texts []string) ([][]Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenizeAll", ctx, texts)
+	ret0, _ := ret[0].([][]Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TokenizeAll indicates an expected call of TokenizeAll.
+func (mr *MockWordsMockRecorder) TokenizeAll(ctx, texts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenizeAll", reflect.TypeOf((*MockWords)(nil).TokenizeAll), ctx, texts)
+}
+
 // MockSearcher is a mock of Searcher interface.
 type MockSearcher struct {
 	ctrl     *gomock.Controller
@@ -133,19 +208,106 @@ func (m *MockSearcher) EXPECT() *MockSearcherMockRecorder {
 	return m.recorder
 }
 
-// ISearch mocks base method.
-func (m *MockSearcher) ISearch(ctx context.Context, phrase string, limit int64) ([]Comic, error) {
+// CacheStats mocks base method.
+func (m *MockSearcher) CacheStats(ctx context.Context) CacheStats {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ISearch", ctx, phrase, limit)
+	ret := m.ctrl.Call(m, "CacheStats", ctx)
+	ret0, _ := ret[0].(CacheStats)
+	return ret0
+}
+
+// CacheStats indicates an expected call of CacheStats.
+func (mr *MockSearcherMockRecorder) CacheStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheStats", reflect.TypeOf((*MockSearcher)(nil).CacheStats), ctx)
+}
+
+// GetComic mocks base method.
+func (m *MockSearcher) GetComic(ctx context.Context, id int64) (ComicDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComic", ctx, id)
+	ret0, _ := ret[0].(ComicDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComic indicates an expected call of GetComic.
+func (mr *MockSearcherMockRecorder) GetComic(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComic", reflect.TypeOf((*MockSearcher)(nil).GetComic), ctx, id)
+}
+
+// GetComics mocks base method.
+func (m *MockSearcher) GetComics(ctx context.Context, ids []int64) ([]Comic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComics", ctx, ids)
 	ret0, _ := ret[0].([]Comic)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetComics indicates an expected call of GetComics.
+func (mr *MockSearcherMockRecorder) GetComics(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComics", reflect.TypeOf((*MockSearcher)(nil).GetComics), ctx, ids)
+}
+
+// ISearch mocks base method.
+func (m *MockSearcher) ISearch(ctx context.Context, req SearchRequest) (SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ISearch", ctx, req)
+	ret0, _ := ret[0].(SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // ISearch indicates an expected call of ISearch.
-func (mr *MockSearcherMockRecorder) ISearch(ctx, phrase, limit any) *gomock.Call {
+func (mr *MockSearcherMockRecorder) ISearch(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ISearch", reflect.TypeOf((*MockSearcher)(nil).ISearch), ctx, req)
+}
+
+// IndexStats mocks base method.
+func (m *MockSearcher) IndexStats(ctx context.Context) IndexStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexStats", ctx)
+	ret0, _ := ret[0].(IndexStats)
+	return ret0
+}
+
+// IndexStats indicates an expected call of IndexStats.
+func (mr *MockSearcherMockRecorder) IndexStats(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ISearch", reflect.TypeOf((*MockSearcher)(nil).ISearch), ctx, phrase, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexStats", reflect.TypeOf((*MockSearcher)(nil).IndexStats), ctx)
+}
+
+// IndexStatus mocks base method.
+func (m *MockSearcher) IndexStatus(ctx context.Context) IndexStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexStatus", ctx)
+	ret0, _ := ret[0].(IndexStatus)
+	return ret0
+}
+
+// IndexStatus indicates an expected call of IndexStatus.
+func (mr *MockSearcherMockRecorder) IndexStatus(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexStatus", reflect.TypeOf((*MockSearcher)(nil).IndexStatus), ctx)
+}
+
+// RandomComic mocks base method.
+func (m *MockSearcher) RandomComic(ctx context.Context) (ComicDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RandomComic", ctx)
+	ret0, _ := ret[0].(ComicDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RandomComic indicates an expected call of RandomComic.
+func (mr *MockSearcherMockRecorder) RandomComic(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RandomComic", reflect.TypeOf((*MockSearcher)(nil).RandomComic), ctx)
 }
 
 // ResetIndex mocks base method.
@@ -161,18 +323,48 @@ func (mr *MockSearcherMockRecorder) ResetIndex() *gomock.Call {
 }
 
 // Search mocks base method.
-func (m *MockSearcher) Search(ctx context.Context, phrase string, limit int64) ([]Comic, error) {
+func (m *MockSearcher) Search(ctx context.Context, req SearchRequest) (SearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", ctx, phrase, limit)
-	ret0, _ := ret[0].([]Comic)
+	ret := m.ctrl.Call(m, "Search", ctx, req)
+	ret0, _ := ret[0].(SearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockSearcherMockRecorder) Search(ctx, phrase, limit any) *gomock.Call {
+func (mr *MockSearcherMockRecorder) Search(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearcher)(nil).Search), ctx, req)
+}
+
+// Similar mocks base method.
+func (m *MockSearcher) Similar(ctx context.Context, id, limit int64) (SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Similar", ctx, id, limit)
+	ret0, _ := ret[0].(SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Similar indicates an expected call of Similar.
+func (mr *MockSearcherMockRecorder) Similar(ctx, id, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearcher)(nil).Search), ctx, phrase, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Similar", reflect.TypeOf((*MockSearcher)(nil).Similar), ctx, id, limit)
+}
+
+// Suggest mocks base method.
+func (m *MockSearcher) Suggest(ctx context.Context, prefix string, limit int64) ([]Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, prefix, limit)
+	ret0, _ := ret[0].([]Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockSearcherMockRecorder) Suggest(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockSearcher)(nil).Suggest), ctx, prefix, limit)
 }
 
 // UpdateIndex mocks base method.
@@ -189,6 +381,177 @@ func (mr *MockSearcherMockRecorder) UpdateIndex(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIndex", reflect.TypeOf((*MockSearcher)(nil).UpdateIndex), ctx)
 }
 
+// MockSnapshotter is a mock of Snapshotter interface.
+type MockSnapshotter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotterMockRecorder
+	isgomock struct{}
+}
+
+// MockSnapshotterMockRecorder is the mock recorder for MockSnapshotter.
+type MockSnapshotterMockRecorder struct {
+	mock *MockSnapshotter
+}
+
+// NewMockSnapshotter creates a new mock instance.
+func NewMockSnapshotter(ctrl *gomock.Controller) *MockSnapshotter {
+	mock := &MockSnapshotter{ctrl: ctrl}
+	mock.recorder = &MockSnapshotterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotter) EXPECT() *MockSnapshotterMockRecorder {
+	return m.recorder
+}
+
+// LoadSnapshot mocks base method.
+func (m *MockSnapshotter) LoadSnapshot(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadSnapshot", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadSnapshot indicates an expected call of LoadSnapshot.
+func (mr *MockSnapshotterMockRecorder) LoadSnapshot(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSnapshot", reflect.TypeOf((*MockSnapshotter)(nil).LoadSnapshot), ctx)
+}
+
+// SaveSnapshot mocks base method.
+func (m *MockSnapshotter) SaveSnapshot(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSnapshot", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSnapshot indicates an expected call of SaveSnapshot.
+func (mr *MockSnapshotterMockRecorder) SaveSnapshot(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSnapshot", reflect.TypeOf((*MockSnapshotter)(nil).SaveSnapshot), ctx)
+}
+
+// MockSnapshotStore is a mock of SnapshotStore interface.
+type MockSnapshotStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockSnapshotStoreMockRecorder is the mock recorder for MockSnapshotStore.
+type MockSnapshotStoreMockRecorder struct {
+	mock *MockSnapshotStore
+}
+
+// NewMockSnapshotStore creates a new mock instance.
+func NewMockSnapshotStore(ctrl *gomock.Controller) *MockSnapshotStore {
+	mock := &MockSnapshotStore{ctrl: ctrl}
+	mock.recorder = &MockSnapshotStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotStore) EXPECT() *MockSnapshotStoreMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method.
+func (m *MockSnapshotStore) Load(ctx context.Context) (IndexSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx)
+	ret0, _ := ret[0].(IndexSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockSnapshotStoreMockRecorder) Load(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockSnapshotStore)(nil).Load), ctx)
+}
+
+// Save mocks base method.
+func (m *MockSnapshotStore) Save(ctx context.Context, snapshot IndexSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSnapshotStoreMockRecorder) Save(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSnapshotStore)(nil).Save), ctx, snapshot)
+}
+
+// MockRanker is a mock of Ranker interface.
+type MockRanker struct {
+	ctrl     *gomock.Controller
+	recorder *MockRankerMockRecorder
+	isgomock struct{}
+}
+
+// MockRankerMockRecorder is the mock recorder for MockRanker.
+type MockRankerMockRecorder struct {
+	mock *MockRanker
+}
+
+// NewMockRanker creates a new mock instance.
+func NewMockRanker(ctrl *gomock.Controller) *MockRanker {
+	mock := &MockRanker{ctrl: ctrl}
+	mock.recorder = &MockRankerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRanker) EXPECT() *MockRankerMockRecorder {
+	return m.recorder
+}
+
+// Explain mocks base method.
+func (m *MockRanker) Explain(candidate Candidate, corpus Corpus) Explanation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", candidate, corpus)
+	ret0, _ := ret[0].(Explanation)
+	return ret0
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockRankerMockRecorder) Explain(candidate, corpus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockRanker)(nil).Explain), candidate, corpus)
+}
+
+// Name mocks base method.
+func (m *MockRanker) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockRankerMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockRanker)(nil).Name))
+}
+
+// Score mocks base method.
+func (m *MockRanker) Score(candidate Candidate, corpus Corpus) float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Score", candidate, corpus)
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// Score indicates an expected call of Score.
+func (mr *MockRankerMockRecorder) Score(candidate, corpus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Score", reflect.TypeOf((*MockRanker)(nil).Score), candidate, corpus)
+}
+
 // MockEventHandler is a mock of EventHandler interface.
 type MockEventHandler struct {
 	ctrl     *gomock.Controller
@@ -214,15 +577,15 @@ func (m *MockEventHandler) EXPECT() *MockEventHandlerMockRecorder {
 }
 
 // HandleEvent mocks base method.
-func (m *MockEventHandler) HandleEvent(ctx context.Context, eventType EventType) error {
+func (m *MockEventHandler) HandleEvent(ctx context.Context, event Event) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HandleEvent", ctx, eventType)
+	ret := m.ctrl.Call(m, "HandleEvent", ctx, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // HandleEvent indicates an expected call of HandleEvent.
-func (mr *MockEventHandlerMockRecorder) HandleEvent(ctx, eventType any) *gomock.Call {
+func (mr *MockEventHandlerMockRecorder) HandleEvent(ctx, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEvent", reflect.TypeOf((*MockEventHandler)(nil).HandleEvent), ctx, eventType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEvent", reflect.TypeOf((*MockEventHandler)(nil).HandleEvent), ctx, event)
 }
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type EventType string
 
 const (
@@ -7,12 +9,257 @@ const (
 	EventReset  EventType = "reset"
 )
 
+// Event - изменение комиксов в базе. EventUpdate с идентификаторами обновляет
+// в индексе только эти комиксы, без них - весь индекс.
+type Event struct {
+	Type    EventType
+	Added   []int64
+	Updated []int64
+	Deleted []int64
+}
+
+func (e Event) changed() bool {
+	return len(e.Added)+len(e.Updated)+len(e.Deleted) > 0
+}
+
+// IndexSource - откуда получена текущая версия индекса ISearch.
+type IndexSource string
+
+const (
+	IndexSourceEmpty    IndexSource = "empty"
+	IndexSourceSnapshot IndexSource = "snapshot"
+	IndexSourceDatabase IndexSource = "database"
+	IndexSourceEvents   IndexSource = "events"
+)
+
+type IndexStatus struct {
+	Generation uint64
+	Source     IndexSource
+	UpdatedAt  time.Time // на какой момент индекс отражает базу
+	Comics     int
+}
+
+// IndexStats - размер индекса ISearch и его последняя сборка.
+// Bytes - объем сжатых списков комиксов вместе с терминами.
+type IndexStats struct {
+	Terms         int
+	Postings      int
+	Bytes         int
+	BuildDuration time.Duration
+	BuiltAt       time.Time
+}
+
+// CacheStats - счетчики кэша результатов поиска с момента запуска.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Size      int64
+}
+
+// HitRatio - доля запросов, найденных в кэше.
+func (s CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// IndexSnapshot - версия индекса ISearch, сохраненная между запусками.
+type IndexSnapshot struct {
+	Generation uint64
+	UpdatedAt  time.Time
+	Comics     []ComicInfo
+}
+
 type ComicInfo struct {
 	Comic
-	Words []string
+	Words     []string
+	Phonetics []string
+	Terms     []string // основы в порядке следования, с повторами
+
+	TitleTerms      []string
+	AltTerms        []string
+	TranscriptTerms []string
+
+	// слова текстов комикса в исходном написании, без повторов; заполняются
+	// при индексации, в базе их нет
+	Forms []WordForm
+}
+
+// WordForm - слово в написании из текста и его основа.
+type WordForm struct {
+	Word string
+	Stem string
+}
+
+func (info ComicInfo) fieldTerms() map[string][]string {
+	return map[string][]string{
+		FieldTitle:      info.TitleTerms,
+		FieldAlt:        info.AltTerms,
+		FieldTranscript: info.TranscriptTerms,
+	}
+}
+
+// CorpusStats - статистика всей коллекции для ранжирования.
+type CorpusStats struct {
+	Docs     int
+	TotalLen int // сумма длин комиксов в терминах
+	MaxID    int64
+	DF       map[string]int // количество комиксов со словом, только для запрошенных слов
+}
+
+// Options - настройки поиска и ранжирования.
+type Options struct {
+	// если точных совпадений меньше, ISearch добавляет совпадения по звучанию;
+	// 0 отключает фонетический поиск
+	PhoneticMinHits int
+	// если точных совпадений меньше, поиск пробует исправить опечатки в словах
+	// запроса; 0 отключает исправление
+	FuzzyMinHits int
+	// параметры BM25: насыщение частоты термина и нормализация по длине комикса
+	K1 float64
+	B  float64
+	// усиление BM25 для самого нового комикса в ранжировщике recency
+	RecencyBoost float64
+	// ранжировщик по умолчанию, если эксперимент не задан
+	Ranker string
+	// A/B эксперимент: запросы распределяются между ранжировщиками по весам
+	Experiment []ExperimentArm
+	// максимальный размер страницы, больший limit уменьшается до него;
+	// 0 снимает ограничение
+	MaxLimit int64
+	// подсветка совпадений: маркеры вокруг совпавших слов и примерная длина
+	// фрагмента в байтах; 0 отключает подсветку
+	HighlightPreTag  string
+	HighlightPostTag string
+	SnippetSize      int
+	// количество запомненных результатов ISearch; 0 отключает кэш
+	CacheSize int
+	// веса совпадений в полях title, alt и transcript для bm25 и recency;
+	// поле без веса - 1
+	FieldBoosts map[string]float64
+}
+
+type ExperimentArm struct {
+	Ranker string
+	Weight int
+}
+
+type SearchRequest struct {
+	Phrase   string
+	Limit    int64
+	Offset   int64
+	ClientID string // по нему запрос закрепляется за группой эксперимента
+	Explain  bool   // добавить к комиксам разбор оценки
+	Filters  Filters
+	Sort     SortOrder // пустой - по релевантности
+}
+
+// Filters - ограничения выдачи по году публикации, номеру и признакам комикса.
+// Нулевые значения ничего не ограничивают, границы включаются в диапазон.
+type Filters struct {
+	YearFrom int
+	YearTo   int
+	IDFrom   int64
+	IDTo     int64
+	Link     bool // только комиксы со ссылкой: большие и интерактивные
+	News     bool // только комиксы с новостью
+}
+
+// YearFacet - количество найденных комиксов, опубликованных в году.
+type YearFacet struct {
+	Year  int
+	Count int64
+}
+
+type SearchResult struct {
+	Comics    []Comic
+	TotalHits int64  // количество найденных комиксов без учета страницы
+	Ranker    string // ранжировщик, выбранный для запроса
+	// фраза с исправленными опечатками, если результаты найдены по ней,
+	// потому что по исходной не нашлось ничего
+	CorrectedQuery string
+	// фраза с исправленными опечатками, по которой нашлось бы больше
+	DidYouMean string
+	// номер версии индекса, по которой выполнен ISearch
+	IndexGeneration uint64
+	// распределение найденных комиксов по годам без учета фильтра по году,
+	// чтобы по нему можно было выбрать другой год
+	Years []YearFacet
+	// теги, которыми в фрагментах отмечены совпадения
+	HighlightPreTag  string
+	HighlightPostTag string
+}
+
+// Suggestion - слово словаря индекса для автодополнения.
+type Suggestion struct {
+	Text  string
+	Count int64 // количество комиксов со словом
 }
 
 type Comic struct {
 	ID  int64  `db:"id"`
 	URL string `db:"url"`
+
+	// исходный текст полей, по нему строятся фрагменты с подсветкой
+	Title      string `db:"title"`
+	Alt        string `db:"alt"`
+	Transcript string `db:"transcript"`
+
+	Published *time.Time `db:"published"` // nil - дата публикации неизвестна
+	// ссылка есть у больших и интерактивных комиксов, новость - у анонсов
+	Link string `db:"link"`
+	News string `db:"news"`
+
+	Matches []FieldMatch
+
+	Score        float64
+	MatchedTerms []string     // совпавшие основы запроса
+	Explanation  *Explanation // только по запросу с Explain
+}
+
+// ComicDetail - комикс с номерами соседних комиксов индекса; 0 - соседа нет.
+type ComicDetail struct {
+	Comic
+	PrevID int64
+	NextID int64
+}
+
+// Explanation - разбор оценки комикса ранжировщиком:
+// Score = сумма Terms[i].Score * Boost + Bonus.
+type Explanation struct {
+	Score float64
+	Terms []TermScore
+	Boost float64 // множитель суммы, например за новизну; 1 - без усиления
+	Bonus float64 // слагаемое, не относящееся к отдельным словам
+	// комикс найден только по звучанию слов и стоит после точных совпадений
+	Phonetic bool
+}
+
+// TermScore - вклад совпавшего слова в оценку комикса.
+type TermScore struct {
+	Term        string
+	TF          int     // частота слова в комиксе
+	DF          int     // количество комиксов со словом
+	IDF         float64 // обратная частота в коллекции
+	FieldWeight float64 // вес поля, в котором найдено слово
+	Score       float64
+}
+
+// FieldMatch - совпавшие с запросом основы в поле комикса
+// и фрагмент текста поля, в котором они отмечены.
+type FieldMatch struct {
+	Field   string
+	Terms   []string
+	Snippet string
+}
+
+// Token - слово текста с его смещением в байтах и основой.
+type Token struct {
+	Text     string
+	Start    int
+	End      int
+	Stem     string
+	StopWord bool
 }
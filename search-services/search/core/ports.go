@@ -7,21 +7,58 @@ import (
 //go:generate mockgen -source=ports.go -destination=mocks.go -package=core
 
 type DB interface {
-	GetComicsByIds(ctx context.Context, ids []int64) ([]Comic, error)
 	GetAllComicsInfo(ctx context.Context) ([]ComicInfo, error)
+	GetComicsInfoByIds(ctx context.Context, ids []int64) ([]ComicInfo, error)
+	// FindComicsInfo возвращает комиксы, в которых есть хотя бы одно из слов.
+	FindComicsInfo(ctx context.Context, words []string) ([]ComicInfo, error)
+	CorpusStats(ctx context.Context, words []string) (CorpusStats, error)
 }
 
 type Words interface {
 	Norm(ctx context.Context, phrase string) ([]string, error)
+	Phonetics(ctx context.Context, phrase string) ([]string, error)
+	Terms(ctx context.Context, phrase string) ([]string, error)
+	// TokenizeAll разбивает на слова несколько текстов, результат в порядке texts.
+	TokenizeAll(ctx context.Context, texts []string) ([][]Token, error)
 }
 
 type Searcher interface {
-	Search(ctx context.Context, phrase string, limit int64) ([]Comic, error)
-	ISearch(ctx context.Context, phrase string, limit int64) ([]Comic, error)
+	Search(ctx context.Context, req SearchRequest) (SearchResult, error)
+	ISearch(ctx context.Context, req SearchRequest) (SearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int64) ([]Suggestion, error)
+	Similar(ctx context.Context, id int64, limit int64) (SearchResult, error)
+	GetComic(ctx context.Context, id int64) (ComicDetail, error)
+	// GetComics возвращает комиксы в порядке ids, пропуская отсутствующие.
+	GetComics(ctx context.Context, ids []int64) ([]Comic, error)
+	RandomComic(ctx context.Context) (ComicDetail, error)
 	UpdateIndex(ctx context.Context) error
 	ResetIndex()
+	IndexStatus(ctx context.Context) IndexStatus
+	IndexStats(ctx context.Context) IndexStats
+	CacheStats(ctx context.Context) CacheStats
+}
+
+// Snapshotter сохраняет индекс ISearch между запусками.
+type Snapshotter interface {
+	SaveSnapshot(ctx context.Context) error
+	LoadSnapshot(ctx context.Context) error
+}
+
+type SnapshotStore interface {
+	Save(ctx context.Context, snapshot IndexSnapshot) error
+	// Load возвращает ErrSnapshotNotFound, если снимка нет,
+	// и ErrSnapshotCorrupt, если его не удалось прочитать.
+	Load(ctx context.Context) (IndexSnapshot, error)
+}
+
+// Ranker оценивает релевантность кандидата: чем больше оценка, тем выше комикс в выдаче.
+type Ranker interface {
+	Name() string
+	Score(candidate Candidate, corpus Corpus) float64
+	// Explain раскладывает оценку Score на вклады совпавших слов и усиления.
+	Explain(candidate Candidate, corpus Corpus) Explanation
 }
 
 type EventHandler interface {
-	HandleEvent(ctx context.Context, eventType EventType) error
+	HandleEvent(ctx context.Context, event Event) error
 }
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// postingList - возрастающий список id комиксов, сжатый разностями соседних id
+// в varint. Список не меняется на месте: with и without возвращают новый,
+// прежний могут читать другие версии индекса.
+type postingList struct {
+	data  []byte
+	count int
+	last  int64
+}
+
+func newPostingList(ids []int64) postingList {
+	sorted := slices.Compact(slices.Sorted(slices.Values(ids)))
+	var list postingList
+	for _, id := range sorted {
+		list = list.appendID(id)
+	}
+	return list
+}
+
+func (p postingList) len() int {
+	return p.count
+}
+
+// size возвращает объем сжатых данных в байтах.
+func (p postingList) size() int {
+	return len(p.data)
+}
+
+// ids распаковывает список по возрастанию.
+func (p postingList) ids() []int64 {
+	ids := make([]int64, 0, p.count)
+	var id int64
+	for data := p.data; len(data) > 0; {
+		delta, n := binary.Uvarint(data)
+		data = data[n:]
+		id += int64(delta)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// with возвращает список с добавленным id. При сборке id идут по возрастанию,
+// и список только дописывается.
+func (p postingList) with(id int64) postingList {
+	switch {
+	case p.count == 0 || id > p.last:
+		return p.appendID(id)
+	case id == p.last:
+		return p
+	}
+	ids := p.ids()
+	pos, found := slices.BinarySearch(ids, id)
+	if found {
+		return p
+	}
+	return newPostingList(slices.Insert(ids, pos, id))
+}
+
+func (p postingList) without(id int64) postingList {
+	ids := p.ids()
+	pos, found := slices.BinarySearch(ids, id)
+	if !found {
+		return p
+	}
+	return newPostingList(slices.Delete(ids, pos, pos+1))
+}
+
+// appendID дописывает id больше последнего. Данные обрезаны по длине
+// (см. clonePostings), поэтому append не затирает чужой список.
+func (p postingList) appendID(id int64) postingList {
+	p.data = binary.AppendUvarint(p.data, uint64(id-p.last))
+	p.count++
+	p.last = id
+	return p
+}
+
+// intersect пересекает возрастающие списки слиянием.
+func intersect(a, b []int64) []int64 {
+	result := make([]int64, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// union объединяет возрастающие списки слиянием.
+func union(a, b []int64) []int64 {
+	result := make([]int64, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			result = append(result, a[i])
+			i++
+		case a[i] > b[j]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
+
+// subtract возвращает id из a, которых нет в b.
+func subtract(a, b []int64) []int64 {
+	result := make([]int64, 0, len(a))
+	j := 0
+	for _, id := range a {
+		for j < len(b) && b[j] < id {
+			j++
+		}
+		if j == len(b) || b[j] != id {
+			result = append(result, id)
+		}
+	}
+	return result
+}
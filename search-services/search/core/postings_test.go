@@ -0,0 +1,180 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostingList(t *testing.T) {
+	list := newPostingList([]int64{300, 5, 1, 5, 1000000})
+	require.Equal(t, []int64{1, 5, 300, 1000000}, list.ids())
+	require.Equal(t, 4, list.len())
+	// разности 1, 4 и 295 занимают 1, 1 и 2 байта, 999700 - 3
+	require.Equal(t, 7, list.size())
+
+	require.Equal(t, []int64{1, 5, 42, 300, 1000000}, list.with(42).ids())
+	require.Equal(t, []int64{1, 5, 300, 1000000, 1000001}, list.with(1000001).ids())
+	require.Equal(t, list, list.with(5))
+	require.Equal(t, []int64{1, 300, 1000000}, list.without(5).ids())
+	require.Equal(t, list, list.without(7))
+	require.Equal(t, 0, list.without(1).without(5).without(300).without(1000000).len())
+
+	// исходный список не меняется
+	require.Equal(t, []int64{1, 5, 300, 1000000}, list.ids())
+}
+
+func TestPostingListAppendAfterClip(t *testing.T) {
+	var base postingList
+	for id := range int64(10) {
+		base = base.with(id)
+	}
+	idx := newInvertedIndex()
+	idx.postings.set("linux", base)
+
+	// дописывание в копию индекса не затрагивает исходный список
+	clone := idx.clone()
+	addPosting(clone.postings, "linux", 100)
+	other := idx.clone()
+	addPosting(other.postings, "linux", 200)
+
+	require.Equal(t, int64(100), clone.postings.lookup("linux").ids()[10])
+	require.Equal(t, int64(200), other.postings.lookup("linux").ids()[10])
+	require.Len(t, idx.postings.lookup("linux").ids(), 10)
+}
+
+func TestMergeOperations(t *testing.T) {
+	a := []int64{1, 3, 5, 7, 9}
+	b := []int64{2, 3, 4, 9, 10}
+
+	require.Equal(t, []int64{3, 9}, intersect(a, b))
+	require.Equal(t, []int64{1, 2, 3, 4, 5, 7, 9, 10}, union(a, b))
+	require.Equal(t, []int64{1, 5, 7}, subtract(a, b))
+	require.Empty(t, intersect(a, nil))
+	require.Equal(t, a, union(nil, a))
+	require.Equal(t, a, subtract(a, nil))
+}
+
+// benchCorpus - синтетическая коллекция: частоты слов убывают по закону Ципфа,
+// как в описаниях комиксов.
+func benchCorpus(docs, vocabulary, length int) []ComicInfo {
+	r := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(vocabulary-1))
+	comics := make([]ComicInfo, docs)
+	for i := range comics {
+		info := ComicInfo{Comic: Comic{ID: int64(i + 1)}}
+		seen := map[string]bool{}
+		for range length {
+			term := fmt.Sprintf("w%d", zipf.Uint64())
+			info.Terms = append(info.Terms, term)
+			if !seen[term] {
+				seen[term] = true
+				info.Words = append(info.Words, term)
+			}
+		}
+		comics[i] = info
+	}
+	// база отдает комиксы в произвольном порядке
+	r.Shuffle(len(comics), func(i, j int) { comics[i], comics[j] = comics[j], comics[i] })
+	return comics
+}
+
+// mapPostings - прежнее представление: несжатые id в порядке добавления.
+func mapPostings(comics []ComicInfo) map[string][]int64 {
+	postings := map[string][]int64{}
+	for _, info := range comics {
+		for _, term := range info.Words {
+			postings[term] = append(postings[term], info.ID)
+		}
+	}
+	return postings
+}
+
+// mapIntersect пересекает списки через множество, как прежний поиск.
+func mapIntersect(postings map[string][]int64, terms []string) map[int64]struct{} {
+	result := map[int64]struct{}{}
+	for _, id := range postings[terms[0]] {
+		result[id] = struct{}{}
+	}
+	for _, term := range terms[1:] {
+		set := map[int64]struct{}{}
+		for _, id := range postings[term] {
+			set[id] = struct{}{}
+		}
+		for id := range result {
+			if _, ok := set[id]; !ok {
+				delete(result, id)
+			}
+		}
+	}
+	return result
+}
+
+var benchQueries = [][]string{
+	{"w1", "w2"},
+	{"w1", "w5", "w20"},
+	{"w3", "w100"},
+}
+
+func BenchmarkPostingsBuild(b *testing.B) {
+	comics := benchCorpus(3000, 20000, 150)
+
+	b.Run("map", func(b *testing.B) {
+		var postings map[string][]int64
+		for b.Loop() {
+			postings = mapPostings(comics)
+		}
+		bytes := 0
+		for term, ids := range postings {
+			bytes += len(term) + 8*len(ids)
+		}
+		b.ReportMetric(float64(bytes), "index-bytes")
+	})
+	b.Run("compressed", func(b *testing.B) {
+		sorted := slices.SortedFunc(slices.Values(comics), func(a, b ComicInfo) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+		var postings map[string]postingList
+		for b.Loop() {
+			postings = map[string]postingList{}
+			for _, info := range sorted {
+				for _, term := range info.Words {
+					postings[term] = postings[term].with(info.ID)
+				}
+			}
+		}
+		bytes := 0
+		for term, list := range postings {
+			bytes += len(term) + list.size()
+		}
+		b.ReportMetric(float64(bytes), "index-bytes")
+	})
+}
+
+func BenchmarkPostingsIntersect(b *testing.B) {
+	comics := benchCorpus(3000, 20000, 150)
+	postings := mapPostings(comics)
+	idx := buildIndex(comics)
+
+	b.Run("map", func(b *testing.B) {
+		for b.Loop() {
+			for _, terms := range benchQueries {
+				mapIntersect(postings, terms)
+			}
+		}
+	})
+	b.Run("compressed", func(b *testing.B) {
+		for b.Loop() {
+			for _, terms := range benchQueries {
+				ids := idx.postings.lookup(terms[0]).ids()
+				for _, term := range terms[1:] {
+					ids = intersect(ids, idx.postings.lookup(term).ids())
+				}
+			}
+		}
+	})
+}
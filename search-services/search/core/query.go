@@ -0,0 +1,348 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Поля комикса, которыми можно ограничить условие запроса: title:linux.
+const (
+	FieldTitle      = "title"
+	FieldAlt        = "alt"
+	FieldTranscript = "transcript"
+)
+
+var queryFields = map[string]bool{
+	FieldTitle:      true,
+	FieldAlt:        true,
+	FieldTranscript: true,
+}
+
+// Синтаксис запроса:
+//
+//	linux windows       - любое из слов, как и раньше
+//	linux AND windows   - оба слова; AND связывает сильнее OR
+//	linux OR windows    - любое из слов
+//	linux -windows      - исключение для группы, в которой стоит условие
+//	"exact phrase"      - основы подряд, без учета стоп-слов
+//	(a OR b) AND c      - группировка
+//	title:linux         - условие только по полю; также title:"..." и title:(...)
+type queryNode interface{}
+
+// termsNode - слова, любое из которых должно встретиться.
+// Соседние слова без операторов объединяются в один узел,
+// чтобы нормализовать их одним запросом к words.
+type termsNode struct {
+	field string
+	text  string
+	terms []string
+}
+
+// phraseNode - основы, которые должны идти подряд.
+type phraseNode struct {
+	field string
+	text  string
+	terms []string
+}
+
+type notNode struct {
+	child queryNode
+}
+
+type boolNode struct {
+	and      bool
+	children []queryNode
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenField
+	tokenNot
+	tokenAnd
+	tokenOr
+	tokenLParen
+	tokenRParen
+	tokenEOF
+)
+
+type queryToken struct {
+	kind tokenKind
+	text string
+	pos  int // позиция в символах от начала фразы
+}
+
+// parseQuery разбирает фразу в дерево условий. Пустая фраза дает nil.
+func parseQuery(phrase string) (queryNode, error) {
+	tokens, err := lexQuery(phrase)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+	node, err := p.parseOr("")
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, unexpected(tok)
+	}
+	return node, nil
+}
+
+func lexQuery(phrase string) ([]queryToken, error) {
+	runes := []rune(phrase)
+	var tokens []queryToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, &QueryError{Pos: i, Msg: "unterminated quote"}
+			}
+			tokens = append(tokens, queryToken{kind: tokenPhrase, text: string(runes[i+1 : end]), pos: i})
+			i = end + 1
+		case r == '-' && i+1 < len(runes) && startsOperand(runes[i+1]):
+			tokens = append(tokens, queryToken{kind: tokenNot, text: "-", pos: i})
+			i++
+		default:
+			end := i
+			for end < len(runes) && !isQueryDelimiter(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, wordTokens(string(runes[i:end]), i)...)
+			i = end
+		}
+	}
+	return append(tokens, queryToken{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// startsOperand сообщает, что минус относится к следующему условию,
+// а не является частью текста вроде "->".
+func startsOperand(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '"' || r == '('
+}
+
+func isQueryDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+}
+
+// wordTokens распознает операторы и префикс поля в слове.
+// Двоеточие после неизвестного имени остается частью слова: 10:30.
+func wordTokens(word string, pos int) []queryToken {
+	switch word {
+	case "AND":
+		return []queryToken{{kind: tokenAnd, text: word, pos: pos}}
+	case "OR":
+		return []queryToken{{kind: tokenOr, text: word, pos: pos}}
+	}
+	if name, rest, ok := strings.Cut(word, ":"); ok && queryFields[strings.ToLower(name)] {
+		field := queryToken{kind: tokenField, text: strings.ToLower(name), pos: pos}
+		if rest == "" {
+			return []queryToken{field}
+		}
+		restPos := pos + len([]rune(name)) + 1
+		return append([]queryToken{field}, wordTokens(rest, restPos)...)
+	}
+	return []queryToken{{kind: tokenWord, text: word, pos: pos}}
+}
+
+// maxQueryDepth ограничивает вложенность групп и отрицаний: разбор
+// рекурсивный, и слишком глубокий запрос переполнил бы стек.
+const maxQueryDepth = 64
+
+type queryParser struct {
+	tokens []queryToken
+	next   int
+	depth  int // вложенность текущего условия
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) consume() queryToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+// parseOr разбирает условия, связанные OR явно или простым соседством.
+func (p *queryParser) parseOr(field string) (queryNode, error) {
+	var children []queryNode
+	for {
+		node, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		children = appendMerged(children, node)
+
+		switch tok := p.peek(); {
+		case tok.kind == tokenOr:
+			p.consume()
+			if !startsUnary(p.peek()) {
+				return nil, missingOperand(tok, p.peek())
+			}
+		case !startsUnary(tok):
+			if len(children) == 1 {
+				return children[0], nil
+			}
+			return &boolNode{children: children}, nil
+		}
+	}
+}
+
+func (p *queryParser) parseAnd(field string) (queryNode, error) {
+	node, err := p.parseUnary(field)
+	if err != nil {
+		return nil, err
+	}
+	children := []queryNode{node}
+	for p.peek().kind == tokenAnd {
+		tok := p.consume()
+		if !startsUnary(p.peek()) {
+			return nil, missingOperand(tok, p.peek())
+		}
+		node, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, node)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &boolNode{and: true, children: children}, nil
+}
+
+func (p *queryParser) parseUnary(field string) (queryNode, error) {
+	tok := p.consume()
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxQueryDepth {
+		return nil, &QueryError{Pos: tok.pos, Msg: "query is nested too deeply"}
+	}
+	switch tok.kind {
+	case tokenNot:
+		child, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		// двойное отрицание снимает исключение
+		if not, ok := child.(*notNode); ok {
+			return not.child, nil
+		}
+		return &notNode{child: child}, nil
+	case tokenField:
+		if next := p.peek(); next.kind != tokenWord && next.kind != tokenPhrase && next.kind != tokenLParen {
+			return nil, &QueryError{Pos: tok.pos, Msg: "missing term after " + tok.text + ":"}
+		}
+		return p.parseUnary(tok.text)
+	case tokenWord:
+		return &termsNode{field: field, text: tok.text}, nil
+	case tokenPhrase:
+		return &phraseNode{field: field, text: tok.text}, nil
+	case tokenLParen:
+		if p.peek().kind == tokenRParen {
+			return nil, &QueryError{Pos: tok.pos, Msg: "empty group"}
+		}
+		node, err := p.parseOr(field)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, &QueryError{Pos: tok.pos, Msg: "missing closing parenthesis"}
+		}
+		p.consume()
+		return node, nil
+	}
+	return nil, unexpected(tok)
+}
+
+func startsUnary(tok queryToken) bool {
+	switch tok.kind {
+	case tokenWord, tokenPhrase, tokenField, tokenNot, tokenLParen:
+		return true
+	}
+	return false
+}
+
+// appendMerged объединяет соседние слова одного поля в один узел.
+func appendMerged(children []queryNode, node queryNode) []queryNode {
+	terms, ok := node.(*termsNode)
+	if !ok || len(children) == 0 {
+		return append(children, node)
+	}
+	last, ok := children[len(children)-1].(*termsNode)
+	if !ok || last.field != terms.field {
+		return append(children, node)
+	}
+	last.text += " " + terms.text
+	return children
+}
+
+func unexpected(tok queryToken) error {
+	if tok.kind == tokenEOF {
+		return &QueryError{Pos: tok.pos, Msg: "unexpected end of query"}
+	}
+	return &QueryError{Pos: tok.pos, Msg: "unexpected " + tok.text}
+}
+
+func missingOperand(op, next queryToken) error {
+	if next.kind == tokenEOF {
+		return &QueryError{Pos: op.pos, Msg: "missing operand after " + op.text}
+	}
+	return unexpected(next)
+}
+
+// keywords возвращает основы положительных условий запроса для ранжирования.
+func keywords(node queryNode) []string {
+	seen := map[string]bool{}
+	var result []string
+	var walk func(queryNode)
+	walk = func(node queryNode) {
+		var terms []string
+		switch n := node.(type) {
+		case *termsNode:
+			terms = n.terms
+		case *phraseNode:
+			terms = n.terms
+		case *boolNode:
+			for _, child := range n.children {
+				walk(child)
+			}
+		}
+		for _, term := range terms {
+			if !seen[term] {
+				seen[term] = true
+				result = append(result, term)
+			}
+		}
+	}
+	walk(node)
+	return result
+}
+
+// isPlainQuery сообщает, что запрос - просто слова без операторов и полей.
+func isPlainQuery(node queryNode) bool {
+	terms, ok := node.(*termsNode)
+	return ok && terms.field == ""
+}
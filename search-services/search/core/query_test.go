@@ -0,0 +1,230 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"search-service/search/core"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func fieldComic(id int64, title, transcript, alt []string) core.ComicInfo {
+	info := core.ComicInfo{
+		Comic:           core.Comic{ID: id},
+		TitleTerms:      title,
+		TranscriptTerms: transcript,
+		AltTerms:        alt,
+	}
+	seen := map[string]bool{}
+	for _, terms := range [][]string{title, transcript, alt} {
+		info.Terms = append(info.Terms, terms...)
+		for _, term := range terms {
+			if !seen[term] {
+				seen[term] = true
+				info.Words = append(info.Words, term)
+			}
+		}
+	}
+	return info
+}
+
+// textComic - fieldComic с текстами полей из тех же слов, чтобы при
+// индексации у комикса были слова в написании.
+func textComic(id int64, title, transcript, alt []string) core.ComicInfo {
+	info := fieldComic(id, title, transcript, alt)
+	info.Title = strings.Join(title, " ")
+	info.Transcript = strings.Join(transcript, " ")
+	info.Alt = strings.Join(alt, " ")
+	return info
+}
+
+// fakeNorm приводит слова к нижнему регистру и убирает стоп-слова.
+func fakeNorm(_ context.Context, phrase string) ([]string, error) {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(phrase)) {
+		if word != "the" && word != "a" {
+			terms = append(terms, word)
+		}
+	}
+	return terms, nil
+}
+
+// expectFindComics отвечает на запросы Search к базе так, как ответил бы
+// Postgres с комиксами indexed.
+func expectFindComics(mockDB *core.MockDB, indexed []core.ComicInfo) {
+	mockDB.EXPECT().FindComicsInfo(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, words []string) ([]core.ComicInfo, error) {
+			var found []core.ComicInfo
+			for _, info := range indexed {
+				if slices.ContainsFunc(words, func(word string) bool { return slices.Contains(info.Words, word) }) {
+					found = append(found, info)
+				}
+			}
+			return found, nil
+		}).AnyTimes()
+	mockDB.EXPECT().CorpusStats(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, words []string) (core.CorpusStats, error) {
+			stats := core.CorpusStats{Docs: len(indexed), DF: map[string]int{}}
+			for _, info := range indexed {
+				stats.MaxID = max(stats.MaxID, info.ID)
+				stats.TotalLen += len(info.Terms)
+				for _, word := range words {
+					if slices.Contains(info.Words, word) {
+						stats.DF[word]++
+					}
+				}
+			}
+			return stats, nil
+		}).AnyTimes()
+}
+
+func TestSearchQuery(t *testing.T) {
+	indexed := []core.ComicInfo{
+		fieldComic(1, []string{"linux", "kernel"}, []string{"cat", "keyboard"}, []string{"sudo", "sandwich"}),
+		fieldComic(2, []string{"windows", "update"}, []string{"linux", "user", "cat"}, []string{"reboot"}),
+		fieldComic(3, []string{"cat"}, []string{"kernel", "panic"}, []string{"linux", "kernel"}),
+		fieldComic(4, []string{"sandwich"}, []string{"make", "sandwich"}, []string{"sudo"}),
+	}
+	testCases := []struct {
+		desc     string
+		phrase   string
+		expected []int64
+	}{
+		{desc: "plain words", phrase: "linux", expected: []int64{1, 2, 3}},
+		{desc: "and", phrase: "linux AND sudo", expected: []int64{1}},
+		{desc: "or", phrase: "kernel OR sandwich", expected: []int64{1, 3, 4}},
+		{desc: "exclusion", phrase: "linux -windows", expected: []int64{1, 3}},
+		{desc: "phrase", phrase: `"linux kernel"`, expected: []int64{1, 3}},
+		{desc: "phrase in field", phrase: `title:"linux kernel"`, expected: []int64{1}},
+		{desc: "field", phrase: "transcript:linux", expected: []int64{2}},
+		{desc: "field case insensitive", phrase: "Title:Kernel", expected: []int64{1}},
+		{desc: "field group", phrase: "alt:(sudo OR reboot)", expected: []int64{1, 2, 4}},
+		{desc: "grouping", phrase: "(linux OR sandwich) AND -title:cat", expected: []int64{1, 2, 4}},
+		{desc: "and binds tighter than or", phrase: "windows OR linux AND sudo", expected: []int64{1, 2}},
+		{desc: "stop words ignored", phrase: "the AND linux", expected: []int64{1, 2, 3}},
+		{desc: "only exclusion", phrase: "-linux", expected: []int64{}},
+		{desc: "unknown field is a word", phrase: "10:30", expected: []int64{}},
+		{desc: "dash in text is not exclusion", phrase: "sudo -> sandwich", expected: []int64{1, 4}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockWords := core.NewMockWords(ctrl)
+
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+			expectFindComics(mockDB, indexed)
+			mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+			mockWords.EXPECT().Terms(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			req := core.SearchRequest{Phrase: tc.phrase, Limit: 10}
+			for _, search := range []func(context.Context, core.SearchRequest) (core.SearchResult, error){
+				service.ISearch, service.Search,
+			} {
+				result, err := search(context.TODO(), req)
+				require.NoError(t, err)
+				ids := make([]int64, len(result.Comics))
+				for i, comic := range result.Comics {
+					ids[i] = comic.ID
+				}
+				require.ElementsMatch(t, tc.expected, ids)
+			}
+		})
+	}
+}
+
+func TestSearchQuerySyntaxError(t *testing.T) {
+	testCases := []struct {
+		phrase string
+		pos    int
+	}{
+		{phrase: `"linux`, pos: 0},
+		{phrase: `(linux`, pos: 0},
+		{phrase: `linux)`, pos: 5},
+		{phrase: `linux AND`, pos: 6},
+		{phrase: `linux OR OR`, pos: 9},
+		{phrase: `()`, pos: 0},
+		{phrase: `cat title:`, pos: 4},
+		{phrase: `«linux» -"kernel`, pos: 9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.phrase, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// до words и базы запрос с ошибкой не доходит
+			service, err := core.NewService(slog.Default(), core.NewMockDB(ctrl), core.NewMockWords(ctrl), nil, core.Options{})
+			require.NoError(t, err)
+
+			_, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10})
+			require.ErrorIs(t, err, core.ErrBadArguments)
+			var queryErr *core.QueryError
+			require.True(t, errors.As(err, &queryErr))
+			require.Equal(t, tc.pos, queryErr.Pos)
+		})
+	}
+}
+
+func TestSearchQueryTooDeep(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		phrase  string
+		wantErr bool
+	}{
+		{
+			desc:   "nesting within limit",
+			phrase: strings.Repeat("(", 20) + "linux" + strings.Repeat(")", 20),
+		},
+		{
+			desc:    "deep nesting",
+			phrase:  strings.Repeat("(", 100) + "linux" + strings.Repeat(")", 100),
+			wantErr: true,
+		},
+		{
+			// без ограничения такой запрос переполняет стек
+			desc:    "huge nesting",
+			phrase:  strings.Repeat("(", 1<<20),
+			wantErr: true,
+		},
+		{
+			desc:    "deep field groups",
+			phrase:  strings.Repeat("title:(", 100) + "linux" + strings.Repeat(")", 100),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWords := core.NewMockWords(ctrl)
+			mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+			service, err := core.NewService(slog.Default(), core.NewMockDB(ctrl), mockWords, nil, core.Options{})
+			require.NoError(t, err)
+
+			_, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10})
+			if !tc.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, core.ErrBadArguments)
+			var queryErr *core.QueryError
+			require.True(t, errors.As(err, &queryErr))
+			require.Equal(t, "query is nested too deeply", queryErr.Msg)
+		})
+	}
+}
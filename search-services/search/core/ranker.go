@@ -0,0 +1,291 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+const (
+	RankerMatches = "matches"
+	RankerRatio   = "ratio"
+	RankerBM25    = "bm25"
+	RankerRecency = "recency"
+)
+
+// Candidate - комикс, совпавший с запросом, и его статистика для ранжирования.
+type Candidate struct {
+	ID      int64
+	Matched int            // количество совпавших ключевых слов запроса
+	Unique  int            // количество уникальных слов комикса
+	Length  int            // длина комикса в терминах, с повторами
+	TF      map[string]int // частоты совпавших ключевых слов в комиксе
+	Terms   []string       // совпавшие ключевые слова в порядке запроса
+	// частоты совпавших слов по полям: слово -> поле -> частота
+	FieldTF map[string]map[string]int
+}
+
+// Corpus - статистика всей коллекции комиксов на момент поиска.
+type Corpus struct {
+	Docs   int
+	AvgLen float64
+	DF     map[string]int // количество комиксов с ключевым словом запроса
+	MaxID  int64
+}
+
+func NewRanker(name string, opts Options) (Ranker, error) {
+	for field, boost := range opts.FieldBoosts {
+		if boost < 0 {
+			return nil, fmt.Errorf("negative boost %v for field %q", boost, field)
+		}
+	}
+	bm25 := bm25Ranker{k1: opts.K1, b: opts.B, boosts: opts.FieldBoosts}
+	switch name {
+	case RankerMatches:
+		return matchesRanker{}, nil
+	case RankerRatio:
+		return ratioRanker{}, nil
+	case "", RankerBM25:
+		return bm25, nil
+	case RankerRecency:
+		return recencyRanker{bm25: bm25, boost: opts.RecencyBoost}, nil
+	}
+	return nil, fmt.Errorf("unknown ranker %q", name)
+}
+
+// matchesRanker ранжирует по количеству совпавших слов, при равенстве - по их доле.
+type matchesRanker struct{}
+
+func (matchesRanker) Name() string {
+	return RankerMatches
+}
+
+func (matchesRanker) Score(c Candidate, _ Corpus) float64 {
+	// доля не превышает 1, поэтому половина доли не меняет порядок по количеству
+	return float64(c.Matched) + ratio(c)/2
+}
+
+func (matchesRanker) Explain(c Candidate, corpus Corpus) Explanation {
+	return explainTerms(c, corpus, func(string, int, float64) float64 { return 1 }, ratio(c)/2)
+}
+
+// ratioRanker ранжирует по доле совпавших слов среди слов комикса.
+type ratioRanker struct{}
+
+func (ratioRanker) Name() string {
+	return RankerRatio
+}
+
+func (ratioRanker) Score(c Candidate, _ Corpus) float64 {
+	return ratio(c)
+}
+
+func (ratioRanker) Explain(c Candidate, corpus Corpus) Explanation {
+	return explainTerms(c, corpus, func(string, int, float64) float64 {
+		if c.Unique == 0 {
+			return 0
+		}
+		return 1 / float64(c.Unique)
+	}, 0)
+}
+
+func ratio(c Candidate) float64 {
+	if c.Unique == 0 {
+		return 0
+	}
+	return float64(c.Matched) / float64(c.Unique)
+}
+
+// bm25Ranker - Okapi BM25: k1 ограничивает вклад частоты термина,
+// b определяет нормализацию по длине комикса. Вхождение слова в поле
+// учитывается с весом поля из boosts, как в BM25F: совпадение в заголовке
+// весит больше, чем то же слово в длинном транскрипте.
+type bm25Ranker struct {
+	k1     float64
+	b      float64
+	boosts map[string]float64 // поле без веса - 1
+}
+
+func (bm25Ranker) Name() string {
+	return RankerBM25
+}
+
+func (r bm25Ranker) Score(c Candidate, corpus Corpus) float64 {
+	if corpus.Docs == 0 || corpus.AvgLen == 0 {
+		return 0
+	}
+	var score float64
+	// порядок запроса, а не обход map: сумма float64 должна быть воспроизводимой
+	for _, term := range c.Terms {
+		score += r.termScore(c, corpus, r.weightedTF(c, term), idf(corpus, term))
+	}
+	return score
+}
+
+func (r bm25Ranker) Explain(c Candidate, corpus Corpus) Explanation {
+	if corpus.Docs == 0 || corpus.AvgLen == 0 {
+		return explainTerms(c, corpus, func(string, int, float64) float64 { return 0 }, 0)
+	}
+	explanation := explainTerms(c, corpus, func(term string, _ int, idf float64) float64 {
+		return r.termScore(c, corpus, r.weightedTF(c, term), idf)
+	}, 0)
+	for i := range explanation.Terms {
+		if term := &explanation.Terms[i]; term.TF > 0 {
+			term.FieldWeight = r.weightedTF(c, term.Term) / float64(term.TF)
+		}
+	}
+	return explanation
+}
+
+func (r bm25Ranker) termScore(c Candidate, corpus Corpus, tf float64, idf float64) float64 {
+	norm := 1 - r.b + r.b*float64(c.Length)/corpus.AvgLen
+	return idf * tf * (r.k1 + 1) / (tf + r.k1*norm)
+}
+
+// weightedTF - частота слова, в которой вхождения в поля умножены на их веса.
+func (r bm25Ranker) weightedTF(c Candidate, term string) float64 {
+	fields := c.FieldTF[term]
+	if len(fields) == 0 {
+		return float64(c.TF[term])
+	}
+	var tf float64
+	for field, n := range fields {
+		boost, ok := r.boosts[field]
+		if !ok {
+			boost = 1
+		}
+		tf += boost * float64(n)
+	}
+	return tf
+}
+
+func idf(corpus Corpus, term string) float64 {
+	df := float64(corpus.DF[term])
+	return math.Log(1 + (float64(corpus.Docs)-df+0.5)/(df+0.5))
+}
+
+// recencyRanker усиливает BM25 для новых комиксов: номера xkcd растут со временем,
+// поэтому самый новый комикс получает множитель 1+boost.
+type recencyRanker struct {
+	bm25  bm25Ranker
+	boost float64
+}
+
+func (recencyRanker) Name() string {
+	return RankerRecency
+}
+
+func (r recencyRanker) Score(c Candidate, corpus Corpus) float64 {
+	return r.bm25.Score(c, corpus) * r.multiplier(c, corpus)
+}
+
+func (r recencyRanker) Explain(c Candidate, corpus Corpus) Explanation {
+	explanation := r.bm25.Explain(c, corpus)
+	explanation.Boost = r.multiplier(c, corpus)
+	explanation.Score *= explanation.Boost
+	return explanation
+}
+
+func (r recencyRanker) multiplier(c Candidate, corpus Corpus) float64 {
+	if corpus.MaxID <= 0 {
+		return 1
+	}
+	return 1 + r.boost*float64(c.ID)/float64(corpus.MaxID)
+}
+
+// explainTerms раскладывает оценку на вклады совпавших слов в порядке запроса;
+// bonus - слагаемое, не относящееся к отдельным словам.
+func explainTerms(c Candidate, corpus Corpus, score func(term string, tf int, idf float64) float64, bonus float64) Explanation {
+	explanation := Explanation{Boost: 1, Bonus: bonus, Score: bonus}
+	for _, term := range c.Terms {
+		termIDF := 0.0
+		if corpus.Docs > 0 {
+			termIDF = idf(corpus, term)
+		}
+		contribution := TermScore{
+			Term:        term,
+			TF:          c.TF[term],
+			DF:          corpus.DF[term],
+			IDF:         termIDF,
+			FieldWeight: 1,
+		}
+		contribution.Score = score(term, contribution.TF, contribution.IDF)
+		explanation.Terms = append(explanation.Terms, contribution)
+		explanation.Score += contribution.Score
+	}
+	return explanation
+}
+
+// rank упорядочивает кандидатов по убыванию оценки, при равенстве - по возрастанию ID.
+func rank(ranker Ranker, candidates []Candidate, corpus Corpus) ([]int64, map[int64]float64) {
+	scores := make(map[int64]float64, len(candidates))
+	ids := make([]int64, len(candidates))
+	for i, c := range candidates {
+		scores[c.ID] = ranker.Score(c, corpus)
+		ids[i] = c.ID
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids, scores
+}
+
+type experimentArm struct {
+	ranker Ranker
+	weight int
+}
+
+// experiment распределяет запросы между ранжировщиками пропорционально весам.
+// Один и тот же клиент всегда попадает в одну группу.
+type experiment struct {
+	arms  []experimentArm
+	total int
+}
+
+func newExperiment(opts Options) (*experiment, error) {
+	arms := opts.Experiment
+	if len(arms) == 0 {
+		arms = []ExperimentArm{{Ranker: opts.Ranker, Weight: 1}}
+	}
+	e := &experiment{}
+	for _, arm := range arms {
+		if arm.Weight <= 0 {
+			continue
+		}
+		ranker, err := NewRanker(arm.Ranker, opts)
+		if err != nil {
+			return nil, err
+		}
+		e.arms = append(e.arms, experimentArm{ranker: ranker, weight: arm.Weight})
+		e.total += arm.Weight
+	}
+	if e.total == 0 {
+		return nil, fmt.Errorf("experiment has no rankers with positive weight")
+	}
+	return e, nil
+}
+
+// choose выбирает ранжировщик по хэшу идентификатора клиента,
+// а для запросов без идентификатора - случайно.
+func (e *experiment) choose(clientID string) Ranker {
+	var bucket int
+	if clientID == "" {
+		bucket = rand.IntN(e.total)
+	} else {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(clientID))
+		bucket = int(h.Sum32() % uint32(e.total))
+	}
+	for _, arm := range e.arms {
+		if bucket < arm.weight {
+			return arm.ranker
+		}
+		bucket -= arm.weight
+	}
+	return e.arms[len(e.arms)-1].ranker
+}
@@ -0,0 +1,236 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"search-service/search/core"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRankers(t *testing.T) {
+	corpus := core.Corpus{Docs: 3, AvgLen: 4, DF: map[string]int{"comic": 3, "robot": 1}, MaxID: 30}
+	// короткий комикс, совпали все слова
+	short := core.Candidate{ID: 10, Matched: 1, Unique: 1, Length: 1, TF: map[string]int{"comic": 1}, Terms: []string{"comic"}}
+	// длинный комикс с редким словом
+	rare := core.Candidate{ID: 20, Matched: 1, Unique: 6, Length: 8, TF: map[string]int{"robot": 1}, Terms: []string{"robot"}}
+	// новый комикс с двумя совпадениями
+	recent := core.Candidate{ID: 30, Matched: 2, Unique: 4, Length: 4, TF: map[string]int{"comic": 1, "robot": 1}, Terms: []string{"comic", "robot"}}
+
+	testCases := []struct {
+		ranker string
+		better core.Candidate
+		worse  core.Candidate
+	}{
+		{ranker: core.RankerMatches, better: recent, worse: short},
+		{ranker: core.RankerRatio, better: short, worse: recent},
+		{ranker: core.RankerBM25, better: rare, worse: short},
+		{ranker: core.RankerRecency, better: recent, worse: rare},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.ranker, func(t *testing.T) {
+			ranker, err := core.NewRanker(tc.ranker, core.Options{K1: 1.2, B: 0.75, RecencyBoost: 0.5})
+			require.NoError(t, err)
+			require.Equal(t, tc.ranker, ranker.Name())
+			require.Greater(t, ranker.Score(tc.better, corpus), ranker.Score(tc.worse, corpus))
+		})
+	}
+
+	_, err := core.NewRanker("random", core.Options{})
+	require.Error(t, err)
+}
+
+func TestRankersExplain(t *testing.T) {
+	corpus := core.Corpus{Docs: 3, AvgLen: 4, DF: map[string]int{"comic": 3, "robot": 1}, MaxID: 30}
+	candidate := core.Candidate{
+		ID: 15, Matched: 2, Unique: 4, Length: 5,
+		TF:    map[string]int{"robot": 2, "comic": 1},
+		Terms: []string{"robot", "comic"},
+	}
+
+	for _, name := range []string{core.RankerMatches, core.RankerRatio, core.RankerBM25, core.RankerRecency} {
+		t.Run(name, func(t *testing.T) {
+			ranker, err := core.NewRanker(name, core.Options{K1: 1.2, B: 0.75, RecencyBoost: 0.5})
+			require.NoError(t, err)
+
+			explanation := ranker.Explain(candidate, corpus)
+			require.InDelta(t, ranker.Score(candidate, corpus), explanation.Score, 1e-9)
+
+			// оценка складывается из вкладов слов, усиления и слагаемого
+			var sum float64
+			for _, term := range explanation.Terms {
+				sum += term.Score
+			}
+			require.InDelta(t, explanation.Score, sum*explanation.Boost+explanation.Bonus, 1e-9)
+
+			require.Len(t, explanation.Terms, 2)
+			require.Equal(t, "robot", explanation.Terms[0].Term)
+			require.Equal(t, 2, explanation.Terms[0].TF)
+			require.Equal(t, 1, explanation.Terms[0].DF)
+			require.Equal(t, 1.0, explanation.Terms[0].FieldWeight)
+			// редкое слово весит больше частого
+			require.Greater(t, explanation.Terms[0].IDF, explanation.Terms[1].IDF)
+		})
+	}
+
+	recency, err := core.NewRanker(core.RankerRecency, core.Options{K1: 1.2, B: 0.75, RecencyBoost: 0.5})
+	require.NoError(t, err)
+	require.InDelta(t, 1.25, recency.Explain(candidate, corpus).Boost, 1e-9)
+}
+
+func TestExplainSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+		{Comic: core.Comic{ID: 1}, Words: []string{"robot", "comic"}, Phonetics: []string{"RPT"}},
+		{Comic: core.Comic{ID: 2}, Words: []string{"rabbit"}, Phonetics: []string{"RPT"}},
+	}, nil).AnyTimes()
+	mockWords.EXPECT().Norm(gomock.Any(), "robot").Return([]string{"robot"}, nil).Times(2)
+	mockWords.EXPECT().Phonetics(gomock.Any(), "robot").Return([]string{"RPT"}, nil).Times(2)
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{PhoneticMinHits: 5, Ranker: core.RankerMatches})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "robot", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1.25, result.Comics[0].Score)
+	require.Equal(t, []string{"robot"}, result.Comics[0].MatchedTerms)
+	require.Nil(t, result.Comics[0].Explanation)
+
+	result, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: "robot", Limit: 10, Explain: true})
+	require.NoError(t, err)
+	require.Equal(t, &core.Explanation{
+		Score: 1.25,
+		Terms: []core.TermScore{{Term: "robot", TF: 1, DF: 1, IDF: result.Comics[0].Explanation.Terms[0].IDF, FieldWeight: 1, Score: 1}},
+		Boost: 1,
+		Bonus: 0.25,
+	}, result.Comics[0].Explanation)
+	// найденный только по звучанию комикс без оценки
+	require.Equal(t, 0.0, result.Comics[1].Score)
+	require.Equal(t, &core.Explanation{Boost: 1, Phonetic: true}, result.Comics[1].Explanation)
+}
+
+func TestExperiment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).Return([]string{}, nil).AnyTimes()
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{
+		Experiment: []core.ExperimentArm{
+			{Ranker: core.RankerBM25, Weight: 1},
+			{Ranker: core.RankerRecency, Weight: 1},
+			{Ranker: core.RankerRatio, Weight: 0},
+		},
+	})
+	require.NoError(t, err)
+
+	assigned := map[string]int{}
+	for i := range 100 {
+		clientID := fmt.Sprintf("client-%d", i)
+		first, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "test", Limit: 10, ClientID: clientID})
+		require.NoError(t, err)
+		second, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "test", Limit: 10, ClientID: clientID})
+		require.NoError(t, err)
+		// клиент всегда попадает в одну группу
+		require.Equal(t, first.Ranker, second.Ranker)
+		assigned[first.Ranker]++
+	}
+	require.Len(t, assigned, 2)
+	require.Zero(t, assigned[core.RankerRatio])
+
+	_, err = core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{
+		Experiment: []core.ExperimentArm{{Ranker: "unknown", Weight: 1}},
+	})
+	require.Error(t, err)
+}
+
+func TestFieldBoosts(t *testing.T) {
+	corpus := core.Corpus{Docs: 2, AvgLen: 2, DF: map[string]int{"linux": 2}}
+	inTitle := core.Candidate{ID: 1, Matched: 1, Unique: 2, Length: 2, TF: map[string]int{"linux": 1}, Terms: []string{"linux"},
+		FieldTF: map[string]map[string]int{"linux": {core.FieldTitle: 1}}}
+	inTranscript := core.Candidate{ID: 2, Matched: 1, Unique: 2, Length: 2, TF: map[string]int{"linux": 1}, Terms: []string{"linux"},
+		FieldTF: map[string]map[string]int{"linux": {core.FieldTranscript: 1}}}
+	// без частот по полям слово весит как без весов
+	legacy := core.Candidate{ID: 3, Matched: 1, Unique: 2, Length: 2, TF: map[string]int{"linux": 1}, Terms: []string{"linux"}}
+
+	boosts := map[string]float64{core.FieldTitle: 2, core.FieldTranscript: 1}
+	for _, name := range []string{core.RankerBM25, core.RankerRecency} {
+		t.Run(name, func(t *testing.T) {
+			ranker, err := core.NewRanker(name, core.Options{K1: 1.2, B: 0.75, FieldBoosts: boosts})
+			require.NoError(t, err)
+			plain, err := core.NewRanker(name, core.Options{K1: 1.2, B: 0.75})
+			require.NoError(t, err)
+
+			require.Greater(t, ranker.Score(inTitle, corpus), ranker.Score(inTranscript, corpus))
+			require.Equal(t, plain.Score(inTranscript, corpus), ranker.Score(inTranscript, corpus))
+			require.Equal(t, plain.Score(legacy, corpus), ranker.Score(legacy, corpus))
+
+			explanation := ranker.Explain(inTitle, corpus)
+			require.Equal(t, 2.0, explanation.Terms[0].FieldWeight)
+			require.InDelta(t, ranker.Score(inTitle, corpus), explanation.Score, 1e-9)
+		})
+	}
+
+	_, err := core.NewRanker(core.RankerBM25, core.Options{FieldBoosts: map[string]float64{core.FieldAlt: -1}})
+	require.Error(t, err)
+}
+
+func TestFieldBoostsSearch(t *testing.T) {
+	indexed := []core.ComicInfo{
+		fieldComic(1, []string{"cat"}, []string{"linux", "kernel"}, nil),
+		fieldComic(2, []string{"linux"}, []string{"cat", "kernel"}, nil),
+		fieldComic(3, []string{"kernel"}, []string{"cat"}, []string{"linux"}),
+	}
+	opts := core.Options{K1: 1.2, B: 0.75, FieldBoosts: map[string]float64{
+		core.FieldTitle: 3, core.FieldAlt: 2, core.FieldTranscript: 1,
+	}}
+
+	for _, method := range []string{"Search", "ISearch"} {
+		t.Run(method, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockWords := core.NewMockWords(ctrl)
+			mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, opts)
+			require.NoError(t, err)
+
+			search := service.ISearch
+			if method == "Search" {
+				expectFindComics(mockDB, indexed)
+				search = service.Search
+			} else {
+				mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+				require.NoError(t, service.UpdateIndex(context.TODO()))
+			}
+
+			result, err := search(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+			require.NoError(t, err)
+			ids := make([]int64, len(result.Comics))
+			for i, comic := range result.Comics {
+				ids[i] = comic.ID
+			}
+			// заголовок важнее подписи, подпись - транскрипта
+			require.Equal(t, []int64{2, 3, 1}, ids)
+		})
+	}
+}
+
+func TestFieldBoostsInvalid(t *testing.T) {
+	_, err := core.NewService(slog.Default(), nil, nil, nil, core.Options{
+		FieldBoosts: map[string]float64{core.FieldTitle: -2},
+	})
+	require.Error(t, err)
+}
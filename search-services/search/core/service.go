@@ -4,38 +4,55 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Service struct {
-	log   *slog.Logger
-	db    DB
-	words Words
-	index map[string][]int64
-	lock  sync.RWMutex
-}
-
-type comicRank struct {
-	Comic
-	matched int64
-	total   int64
+	log        *slog.Logger
+	db         DB
+	words      Words
+	snapshots  SnapshotStore
+	opts       Options
+	experiment *experiment
+	current    atomic.Pointer[snapshot]
+	rebuilds   rebuilds
+	cache      *resultCache
+	writes     sync.Mutex    // подмены индекса и частичные обновления идут по одной
+	journal    *journal      // не nil во время полной сборки, под writes
+	saved      atomic.Uint64 // версия индекса, сохраненная в снимок
 }
 
+// NewService создает сервис поиска. Без snapshots индекс ISearch не
+// сохраняется между запусками.
 func NewService(
-	log *slog.Logger, db DB, words Words) (*Service, error) {
-	return &Service{
-		log:   log,
-		db:    db,
-		words: words,
-		index: map[string][]int64{},
-	}, nil
+	log *slog.Logger, db DB, words Words, snapshots SnapshotStore, opts Options) (*Service, error) {
+	experiment, err := newExperiment(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ranking options: %w", err)
+	}
+	s := &Service{
+		log:        log,
+		db:         db,
+		words:      words,
+		snapshots:  snapshots,
+		opts:       opts,
+		experiment: experiment,
+		cache:      newResultCache(opts.CacheSize),
+	}
+	s.current.Store(newSnapshot(0, IndexSourceEmpty, time.Time{}, newInvertedIndex(), time.Now()))
+	return s, nil
 }
 
-func (s *Service) Search(ctx context.Context, phrase string, limit int64) ([]Comic, error) {
-	if phrase == "" || limit <= 0 {
-		return nil, ErrBadArguments
+func (s *Service) Search(ctx context.Context, req SearchRequest) (SearchResult, error) {
+	if req.Phrase == "" || req.Limit <= 0 || req.Offset < 0 || !req.Filters.valid() || !req.Sort.valid() {
+		return SearchResult{}, ErrBadArguments
 	}
 
 	s.log.Info("search started")
@@ -43,167 +60,634 @@ func (s *Service) Search(ctx context.Context, phrase string, limit int64) ([]Com
 		s.log.Info("search finished", "duration", time.Since(start))
 	}(time.Now())
 
-	keywords, err := s.words.Norm(ctx, phrase)
+	// результат не кэшируется: база меняется раньше, чем версия индекса,
+	// и по версии индекса закэшированный ответ базы устаревал бы
+	current := s.current.Load()
+	// кандидатов находит база по GIN-индексу на words, опечатки
+	// исправляются по словарю версии индекса
+	return s.search(ctx, req, s.experiment.choose(req.ClientID), &dbSource{db: s.db}, current, nil)
+}
+
+func (s *Service) ISearch(ctx context.Context, req SearchRequest) (SearchResult, error) {
+	if req.Phrase == "" || req.Limit <= 0 || req.Offset < 0 || !req.Filters.valid() || !req.Sort.valid() {
+		return SearchResult{}, ErrBadArguments
+	}
+
+	s.log.Info("isearch started")
+	defer func(start time.Time) {
+		s.log.Info("isearch finished", "duration", time.Since(start))
+	}(time.Now())
+
+	// весь запрос обслуживает одна версия индекса, даже если ее подменят
+	current := s.current.Load()
+	return s.cached("isearch", req, current.generation, func(ranker Ranker) (SearchResult, error) {
+		result, err := s.search(ctx, req, ranker, &memorySource{index: current.index}, current, current.index)
+		result.IndexGeneration = current.generation
+		return result, err
+	})
+}
+
+// cached возвращает результат запроса из кэша или ищет его и кэширует.
+func (s *Service) cached(
+	method string, req SearchRequest, generation uint64, search func(Ranker) (SearchResult, error),
+) (SearchResult, error) {
+	ranker := s.experiment.choose(req.ClientID)
+	key := cacheKey(method, req, ranker.Name(), generation)
+	if result, ok := s.cache.get(key); ok {
+		s.log.Debug("search result from cache", "method", method)
+		return result, nil
+	}
+	result, err := search(ranker)
 	if err != nil {
-		s.log.Error("failed to normalized phrase", "error", err)
-		return nil, fmt.Errorf("failed to normalized phrase: %w", err)
+		return SearchResult{}, err
 	}
-	setOfPhrase := map[string]bool{}
-	for _, word := range keywords {
-		setOfPhrase[word] = true
+	s.cache.add(key, result)
+	return result, nil
+}
+
+// search ищет запрос по индексу из source и собирает страницу результатов.
+// Опечатки исправляются по словарю spelling, совпадения по звучанию ищутся
+// в phonetic, если он есть.
+func (s *Service) search(
+	ctx context.Context, req SearchRequest, ranker Ranker,
+	source indexSource, spelling *snapshot, phonetic *invertedIndex,
+) (SearchResult, error) {
+	query, err := s.parseQuery(ctx, req.Phrase)
+	if err != nil {
+		return SearchResult{}, err
 	}
 
-	comicsInfo, err := s.db.GetAllComicsInfo(ctx)
+	hits, err := s.searchIndex(ctx, source, spelling, req.Phrase, query, ranker)
 	if err != nil {
-		s.log.Error("failed to get all comics info", "error", err)
-		return nil, fmt.Errorf("failed to get all comics: %w", err)
+		return SearchResult{}, err
 	}
-	return s.rankedSearch(comicsInfo, setOfPhrase, limit), nil
-}
+	s.log.Debug("found comic ids for query", "count", len(hits.ids))
+	ids := hits.ids
 
-func (s *Service) rankedSearch(comicsInfo []ComicInfo, setOfPhrase map[string]bool, limit int64) []Comic {
-	if len(comicsInfo) == 0 {
-		return []Comic{}
+	// совпадения только по звучанию идут после точных;
+	// для запросов с операторами звучание не учитывается
+	if phonetic != nil && len(ids) < s.opts.PhoneticMinHits && isPlainQuery(hits.query) {
+		ids = append(ids, s.phoneticSearch(ctx, phonetic, hits.phrase, ids)...)
 	}
 
-	var comicsRanks []comicRank
-	for _, comic := range comicsInfo {
-		var matched int64
-		for _, word := range comic.Words {
-			if setOfPhrase[word] {
-				matched++
+	// фильтры, распределение по годам и порядок - по всем найденным, а не по странице
+	comic := func(id int64) (Comic, bool) {
+		if doc, ok := hits.index.docs.get(id); ok {
+			return doc.info.Comic, true
+		}
+		if phonetic != nil {
+			if doc, ok := phonetic.docs.get(id); ok {
+				return doc.info.Comic, true
 			}
 		}
-		if matched == 0 {
+		return Comic{}, false
+	}
+	ids, years := req.Filters.filter(ids, comic)
+	sortHits(ids, req.Sort, comic)
+
+	// запрос из одного номера комикса показывает этот комикс первым,
+	// даже если он не подходит под фильтры
+	pinned, err := s.pinnedComic(ctx, source, req.Phrase)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	if pinned != nil {
+		ids = slices.Insert(slices.DeleteFunc(ids, func(id int64) bool { return id == pinned.ID }), 0, pinned.ID)
+	}
+	totalHits := int64(len(ids))
+	ids = s.page(ids, req)
+
+	// поля для выдачи хранятся в индексе, база для ответа не нужна
+	// комикс по номеру может не совпадать с запросом, поэтому не оценивается
+	pinnedFirst := pinned != nil && len(ids) > 0 && ids[0] == pinned.ID
+	if pinnedFirst {
+		ids = ids[1:]
+	}
+	comics := hits.index.stored(ids)
+	s.score(comics, hits, ranker, req.Explain)
+	s.highlight(ctx, comics, hits.index, hits.query)
+	if pinnedFirst {
+		comics = slices.Insert(comics, 0, *pinned)
+	}
+
+	s.log.Debug("search results",
+		"ranker", ranker.Name(),
+		"relevant", totalHits,
+		"returned", len(comics),
+	)
+	result := SearchResult{
+		Comics:         comics,
+		TotalHits:      totalHits,
+		Ranker:         ranker.Name(),
+		CorrectedQuery: hits.corrected,
+		DidYouMean:     hits.didYouMean,
+		Years:          years,
+	}
+	// клиент отмечает совпадения по тегам, а не по зашитой в него разметке
+	if s.opts.SnippetSize > 0 {
+		result.HighlightPreTag = s.opts.HighlightPreTag
+		result.HighlightPostTag = s.opts.HighlightPostTag
+	}
+	return result, nil
+}
+
+// pinnedComic возвращает комикс, если фраза - только его номер: 353 или #353.
+func (s *Service) pinnedComic(ctx context.Context, source indexSource, phrase string) (*Comic, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(phrase), "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, nil
+	}
+	comic, ok, err := source.comic(ctx, id)
+	if err != nil {
+		s.log.Error("failed to get comic by number", "id", id, "error", err)
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &comic, nil
+}
+
+// indexHits - упорядоченные результаты поиска по индексу и фраза, по которой
+// они найдены, если она отличается от запрошенной.
+type indexHits struct {
+	ids        []int64
+	scores     map[int64]float64
+	candidates map[int64]Candidate
+	corpus     Corpus
+	index      *invertedIndex // индекс, по которому найдены результаты
+	query      queryNode
+	phrase     string
+	corrected  string // фраза с исправленными опечатками, по которой найдены результаты
+	didYouMean string // фраза с исправленными опечатками, по которой найдено больше
+}
+
+// searchIndex находит и ранжирует комиксы по индексу. Если точных совпадений
+// меньше FuzzyMinHits, пробует фразу с исправленными опечатками: когда точных
+// совпадений нет, выдает результаты исправленной фразы, иначе только предлагает ее.
+func (s *Service) searchIndex(
+	ctx context.Context, source indexSource, spelling *snapshot,
+	phrase string, query queryNode, ranker Ranker,
+) (indexHits, error) {
+	idx, err := source.lookup(ctx, query)
+	if err != nil {
+		s.log.Error("failed to get index", "error", err)
+		return indexHits{}, err
+	}
+	hits := rankIndex(idx, query, ranker)
+	hits.phrase = phrase
+	if len(hits.ids) >= s.opts.FuzzyMinHits {
+		return hits, nil
+	}
+
+	corrected, ok, err := s.correctPhrase(ctx, phrase, spelling)
+	if err != nil {
+		s.log.Error("failed to correct phrase", "error", err)
+		return indexHits{}, fmt.Errorf("failed to correct phrase: %w", err)
+	}
+	if !ok {
+		return hits, nil
+	}
+	correctedQuery, err := s.parseQuery(ctx, corrected)
+	if err != nil {
+		return indexHits{}, err
+	}
+	if idx, err = source.lookup(ctx, correctedQuery); err != nil {
+		s.log.Error("failed to get index", "error", err)
+		return indexHits{}, err
+	}
+	correctedHits := rankIndex(idx, correctedQuery, ranker)
+	s.log.Debug("typo correction", "corrected", corrected, "exact", len(hits.ids), "found", len(correctedHits.ids))
+
+	switch {
+	case len(hits.ids) == 0 && len(correctedHits.ids) > 0:
+		correctedHits.phrase = corrected
+		correctedHits.corrected = corrected
+		return correctedHits, nil
+	case len(correctedHits.ids) > len(hits.ids):
+		hits.didYouMean = corrected
+	}
+	return hits, nil
+}
+
+func rankIndex(idx *invertedIndex, query queryNode, ranker Ranker) indexHits {
+	candidates, corpus := idx.candidates(query)
+	hits := indexHits{
+		candidates: make(map[int64]Candidate, len(candidates)),
+		corpus:     corpus,
+		index:      idx,
+		query:      query,
+	}
+	for _, candidate := range candidates {
+		hits.candidates[candidate.ID] = candidate
+	}
+	hits.ids, hits.scores = rank(ranker, candidates, corpus)
+	return hits
+}
+
+// score добавляет к комиксам страницы оценку, совпавшие слова и, если
+// запрошено, разбор оценки. Комиксы, найденные только по звучанию, без оценки.
+func (s *Service) score(comics []Comic, hits indexHits, ranker Ranker, explain bool) {
+	for i := range comics {
+		comic := &comics[i]
+		candidate, ok := hits.candidates[comic.ID]
+		if !ok {
+			if explain {
+				comic.Explanation = &Explanation{Boost: 1, Phonetic: true}
+			}
 			continue
 		}
-		comicsRanks = append(comicsRanks, comicRank{
-			Comic:   comic.Comic,
-			matched: matched,
-			total:   int64(len(comic.Words)),
-		})
-	}
-	if len(comicsRanks) == 0 {
-		return []Comic{}
-	}
-
-	// сортировка по убыванию приоритетов:
-	// 1. количество абсолютных совпадений
-	// 2. соотношение matched/total
-	sort.Slice(comicsRanks, func(i, j int) bool {
-		if comicsRanks[i].matched != comicsRanks[j].matched {
-			return comicsRanks[i].matched > comicsRanks[j].matched
+		comic.Score = hits.scores[comic.ID]
+		comic.MatchedTerms = candidate.Terms
+		if explain {
+			explanation := ranker.Explain(candidate, hits.corpus)
+			comic.Explanation = &explanation
 		}
-		crossI := comicsRanks[i].matched * comicsRanks[j].total
-		crossJ := comicsRanks[j].matched * comicsRanks[i].total
-		return crossI > crossJ
-	})
+	}
+}
 
-	limit = min(int64(len(comicsRanks)), limit)
-	rankedComics := make([]Comic, limit)
-	for i, comicRank := range comicsRanks[:limit] {
-		rankedComics[i] = comicRank.Comic
+// Similar возвращает комиксы, похожие на комикс id по словам описания.
+func (s *Service) Similar(_ context.Context, id int64, limit int64) (SearchResult, error) {
+	if id <= 0 || limit <= 0 {
+		return SearchResult{}, ErrBadArguments
 	}
-	s.log.Debug("search results",
-		"relevant", len(comicsRanks),
-		"returned", limit,
-	)
-	return rankedComics
+
+	current := s.current.Load()
+	similar, ok := current.similarity.get().similar(id)
+	if !ok {
+		return SearchResult{}, ErrNotFound
+	}
+	ids := make([]int64, len(similar))
+	for i, comic := range similar {
+		ids[i] = comic.id
+	}
+	comics := current.index.stored(s.page(ids, SearchRequest{Limit: limit}))
+	for i := range comics {
+		comics[i].Score = similar[i].score
+		comics[i].MatchedTerms = similar[i].terms
+	}
+	s.log.Debug("similar comics", "id", id, "found", len(similar), "returned", len(comics))
+	return SearchResult{
+		Comics:          comics,
+		TotalHits:       int64(len(similar)),
+		IndexGeneration: current.generation,
+	}, nil
 }
 
-func (s *Service) ISearch(ctx context.Context, phrase string, limit int64) ([]Comic, error) {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
+// GetComic возвращает комикс из индекса ISearch с соседями по номеру.
+func (s *Service) GetComic(_ context.Context, id int64) (ComicDetail, error) {
+	if id <= 0 {
+		return ComicDetail{}, ErrBadArguments
+	}
+	detail, ok := s.current.Load().detail(id)
+	if !ok {
+		return ComicDetail{}, ErrNotFound
+	}
+	return detail, nil
+}
+
+func (s *Service) GetComics(_ context.Context, ids []int64) ([]Comic, error) {
+	return s.current.Load().index.stored(ids), nil
+}
+
+// RandomComic возвращает случайный комикс из индекса ISearch.
+func (s *Service) RandomComic(_ context.Context) (ComicDetail, error) {
+	current := s.current.Load()
+	ids := current.ids.get()
+	if len(ids) == 0 {
+		return ComicDetail{}, ErrNotFound
+	}
+	detail, _ := current.detail(ids[rand.IntN(len(ids))])
+	return detail, nil
+}
 
-	if phrase == "" || limit <= 0 {
+// Suggest дополняет префикс словами комиксов в исходном написании.
+func (s *Service) Suggest(_ context.Context, prefix string, limit int64) ([]Suggestion, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
 		return nil, ErrBadArguments
 	}
+	if s.opts.MaxLimit > 0 {
+		limit = min(limit, s.opts.MaxLimit)
+	}
 
-	s.log.Info("isearch started")
-	defer func(start time.Time) {
-		s.log.Info("isearch finished", "duration", time.Since(start))
-	}(time.Now())
+	return s.current.Load().suggester.get().suggest(prefix, limit), nil
+}
 
-	keywords, err := s.words.Norm(ctx, phrase)
+// parseQuery разбирает фразу запроса и нормализует слова его условий.
+func (s *Service) parseQuery(ctx context.Context, phrase string) (queryNode, error) {
+	query, err := parseQuery(phrase)
 	if err != nil {
+		s.log.Debug("failed to parse query", "error", err)
+		return nil, err
+	}
+	if err := s.normalize(ctx, query); err != nil {
 		s.log.Error("failed to normalized phrase", "error", err)
 		return nil, fmt.Errorf("failed to normalized phrase: %w", err)
 	}
+	return query, nil
+}
 
-	scores := map[int64]int{}
-	uniqueIDs := []int64{}
-	for _, keyword := range keywords {
-		for _, id := range s.index[keyword] {
-			if _, ok := scores[id]; !ok {
-				uniqueIDs = append(uniqueIDs, id)
+func (s *Service) normalize(ctx context.Context, node queryNode) error {
+	var err error
+	switch n := node.(type) {
+	case *termsNode:
+		n.terms, err = s.words.Norm(ctx, n.text)
+	case *phraseNode:
+		n.terms, err = s.words.Terms(ctx, n.text)
+	case *notNode:
+		err = s.normalize(ctx, n.child)
+	case *boolNode:
+		for _, child := range n.children {
+			if err = s.normalize(ctx, child); err != nil {
+				break
 			}
-			scores[id]++
 		}
-		s.log.Debug("found comic ids for keyword", "keyword", keyword, "count", len(s.index[keyword]))
 	}
+	return err
+}
 
-	comics, err := s.db.GetComicsByIds(ctx, uniqueIDs)
+// page вырезает из упорядоченных результатов запрошенную страницу.
+// Размер страницы ограничен MaxLimit.
+func (s *Service) page(ids []int64, req SearchRequest) []int64 {
+	limit := req.Limit
+	if s.opts.MaxLimit > 0 {
+		limit = min(limit, s.opts.MaxLimit)
+	}
+	start := min(req.Offset, int64(len(ids)))
+	// start+limit может переполниться, если limit не ограничен
+	end := start + min(limit, int64(len(ids))-start)
+	return ids[start:end]
+}
+
+// phoneticSearch возвращает комиксы, совпавшие с фразой только по звучанию,
+// по убыванию количества совпавших кодов. Ошибка words не прерывает поиск:
+// остаются только точные совпадения.
+func (s *Service) phoneticSearch(ctx context.Context, idx *invertedIndex, phrase string, exact []int64) []int64 {
+	codes, err := s.words.Phonetics(ctx, phrase)
 	if err != nil {
-		s.log.Error("failed to get comics by comics ids", "error", err)
-		return nil, fmt.Errorf("failed to get comics by comics ids: %w", err)
+		s.log.Warn("failed to get phonetic codes", "error", err)
+		return nil
+	}
+	matches := idx.phoneticMatches(codes)
+	for _, id := range exact {
+		delete(matches, id)
 	}
 
-	// сортировка по убыванию количества совпадений
-	sort.Slice(comics, func(i, j int) bool {
-		return scores[comics[i].ID] > scores[comics[j].ID]
+	ids := make([]int64, 0, len(matches))
+	for id := range matches {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if matches[ids[i]] != matches[ids[j]] {
+			return matches[ids[i]] > matches[ids[j]]
+		}
+		return ids[i] < ids[j]
 	})
-
-	limit = min(int64(len(comics)), limit)
-	s.log.Debug("isearch results",
-		"relevant", len(comics),
-		"returned", limit,
-	)
-	return comics[:limit], nil
+	s.log.Debug("phonetic fallback", "codes", len(codes), "found", len(ids))
+	return ids
 }
 
+// UpdateIndex пересобирает индекс ISearch по базе. Поиск во время сборки
+// идет по прежней версии. Запросы, пришедшие во время сборки, ждут одну общую
+// следующую сборку, поэтому после возврата индекс не старше момента вызова.
 func (s *Service) UpdateIndex(ctx context.Context) error {
-	// Lock() гарантирует обновление индекса свежими данными, даже если scheduler его уже обновляет
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	rebuild := s.rebuilds.request(ctx, s.rebuild)
+	select {
+	case <-rebuild.done:
+		return rebuild.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
+func (s *Service) rebuild(ctx context.Context) error {
 	s.log.Info("update index started")
 	defer func(start time.Time) {
 		s.log.Info("update index finished", "duration", time.Since(start))
 	}(time.Now())
 
+	// база читается и разбивается на слова без s.writes: события тем временем
+	// применяются к текущей версии и записываются в журнал, а после сборки
+	// повторяются поверх прочитанного
+	s.writes.Lock()
+	s.journal = &journal{}
+	s.writes.Unlock()
+
+	updatedAt := time.Now()
 	comicsInfo, err := s.db.GetAllComicsInfo(ctx)
 	if err != nil {
+		s.takeJournal()
 		s.log.Error("failed to get all comics", "error", err)
 		return fmt.Errorf("failed to get all comics info: %w", err)
 	}
+	s.addForms(ctx, comicsInfo)
+	index := buildIndex(comicsInfo)
+	next := newSnapshot(0, IndexSourceDatabase, updatedAt, index, updatedAt)
+	next.warm()
+
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	journal := s.journal
+	s.journal = nil
+	if journal.reset {
+		s.log.Info("index has been reset during update, result discarded")
+		return nil
+	}
+	if len(journal.deltas) > 0 {
+		// словари прогретой версии не знают об изменениях и строятся заново
+		for _, d := range journal.deltas {
+			d.apply(index)
+		}
+		next = newSnapshot(0, IndexSourceDatabase, updatedAt, index, updatedAt)
+	}
+	base := s.current.Load()
+	next.generation = base.generation + 1
+	if s.publish(base, next) {
+		s.log.Info("index has been updated", "generation", next.generation, "comics", len(comicsInfo),
+			"replayed", len(journal.deltas))
+	}
+	return nil
+}
+
+// takeJournal заканчивает запись журнала и возвращает его.
+func (s *Service) takeJournal() *journal {
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	journal := s.journal
+	s.journal = nil
+	return journal
+}
+
+// journal - изменения индекса за время полной сборки.
+type journal struct {
+	deltas []delta
+	reset  bool // после сброса прочитанное сборкой устарело
+}
+
+// delta - изменение индекса по событию: комиксы removed убираются,
+// added добавляются заново.
+type delta struct {
+	removed []int64
+	added   []ComicInfo
+}
+
+func (d delta) apply(index *invertedIndex) {
+	for _, id := range d.removed {
+		index.remove(id)
+	}
+	for _, info := range d.added {
+		index.add(info)
+	}
+}
 
-	clear(s.index)
+// applyDelta обновляет в индексе ISearch только комиксы из события: измененные
+// перечитывает из базы, удаленные убирает. Полная сборка по расписанию
+// остается проверкой согласованности.
+func (s *Service) applyDelta(ctx context.Context, event Event) error {
+	s.writes.Lock()
+	defer s.writes.Unlock()
 
-	for _, comicInfo := range comicsInfo {
-		for _, keyword := range comicInfo.Words {
-			s.index[keyword] = append(s.index[keyword], comicInfo.ID)
+	updatedAt := time.Now()
+	changed := slices.Concat(event.Added, event.Updated)
+	d := delta{removed: slices.Concat(changed, event.Deleted)}
+	if len(changed) > 0 {
+		var err error
+		if d.added, err = s.db.GetComicsInfoByIds(ctx, changed); err != nil {
+			s.log.Error("failed to get changed comics", "error", err)
+			return fmt.Errorf("failed to get comics info by ids: %w", err)
+		}
+		s.addForms(ctx, d.added)
+	}
+
+	base := s.current.Load()
+	index := base.index.clone()
+	d.apply(index)
+	next := newSnapshot(base.generation+1, IndexSourceEvents, updatedAt, index, updatedAt)
+	if s.publish(base, next) {
+		s.log.Info("index has been patched", "generation", next.generation,
+			"added", len(event.Added), "updated", len(event.Updated), "deleted", len(event.Deleted))
+		if s.journal != nil {
+			s.journal.deltas = append(s.journal.deltas, d)
 		}
 	}
 	return nil
 }
 
+// publish подменяет версию base версией next. Все подмены идут под s.writes,
+// и base должна оставаться текущей; иначе next построена по устаревшей версии
+// и отбрасывается.
+func (s *Service) publish(base, next *snapshot) bool {
+	if !s.current.CompareAndSwap(base, next) {
+		s.log.Warn("index has been changed during update, result discarded")
+		return false
+	}
+	s.cache.purge()
+	return true
+}
+
 func (s *Service) ResetIndex() {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	clear(s.index)
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	current := s.current.Load()
+	now := time.Now()
+	s.current.Store(newSnapshot(current.generation+1, IndexSourceEvents, now, newInvertedIndex(), now))
+	if s.journal != nil {
+		s.journal.reset = true
+	}
+	s.cache.purge()
 	s.log.Info("index has been reset")
 }
 
-func (s *Service) HandleEvent(ctx context.Context, eventType EventType) error {
-	switch eventType {
+func (s *Service) IndexStatus(_ context.Context) IndexStatus {
+	current := s.current.Load()
+	return IndexStatus{
+		Generation: current.generation,
+		Source:     current.source,
+		UpdatedAt:  current.updatedAt,
+		Comics:     current.index.docs.len(),
+	}
+}
+
+func (s *Service) CacheStats(_ context.Context) CacheStats {
+	return s.cache.stats()
+}
+
+func (s *Service) IndexStats(_ context.Context) IndexStats {
+	return s.current.Load().stats()
+}
+
+// SaveSnapshot сохраняет текущую версию индекса ISearch, если она еще не сохранена.
+func (s *Service) SaveSnapshot(ctx context.Context) error {
+	current := s.current.Load()
+	if s.snapshots == nil || current.source == IndexSourceEmpty || current.generation == s.saved.Load() {
+		return nil
+	}
+	err := s.snapshots.Save(ctx, IndexSnapshot{
+		Generation: current.generation,
+		UpdatedAt:  current.updatedAt,
+		Comics:     current.index.comics(),
+	})
+	if err != nil {
+		s.log.Error("failed to save index snapshot", "error", err)
+		return fmt.Errorf("failed to save index snapshot: %w", err)
+	}
+	s.saved.Store(current.generation)
+	s.log.Info("index snapshot saved", "generation", current.generation)
+	return nil
+}
+
+// LoadSnapshot загружает индекс ISearch из снимка, чтобы поиск был готов
+// до первой сборки по базе. Индекс из снимка отстает от базы на его возраст.
+func (s *Service) LoadSnapshot(ctx context.Context) error {
+	if s.snapshots == nil {
+		return ErrSnapshotNotFound
+	}
+	saved, err := s.snapshots.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load index snapshot: %w", err)
+	}
+
+	// снимки, сохраненные до слов в написании, дополняются ими
+	s.addForms(ctx, saved.Comics)
+
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	started := time.Now()
+	base := s.current.Load()
+	// номера версий продолжаются с сохраненного
+	next := newSnapshot(max(saved.Generation, base.generation+1), IndexSourceSnapshot, saved.UpdatedAt,
+		buildIndex(saved.Comics), started)
+	next.warm()
+	if !s.publish(base, next) {
+		return nil
+	}
+	s.saved.Store(next.generation)
+	s.log.Info("index loaded from snapshot",
+		"generation", next.generation, "comics", len(saved.Comics), "age", time.Since(saved.UpdatedAt))
+	return nil
+}
+
+func (s *Service) HandleEvent(ctx context.Context, event Event) error {
+	switch event.Type {
 	case EventUpdate:
+		if event.changed() {
+			if err := s.applyDelta(ctx, event); err != nil {
+				return fmt.Errorf("failed to apply index delta: %w", err)
+			}
+			break
+		}
 		if err := s.UpdateIndex(ctx); err != nil {
 			return fmt.Errorf("failed to update index: %w", err)
 		}
 	case EventReset:
 		s.ResetIndex()
 	default:
-		s.log.Warn("unknown event type", "event", string(eventType))
+		s.log.Warn("unknown event type", "event", string(event.Type))
 	}
 	return nil
 }
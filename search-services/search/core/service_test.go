@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"math"
 	"search-service/search/core"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -28,16 +30,20 @@ func TestSearch(t *testing.T) {
 				words.EXPECT().Norm(gomock.Any(), "test phrase is unknown").Do(func(ctx context.Context, phrase string) {
 					require.Equal(t, "test phrase is unknown", phrase)
 				}).Return([]string{"test", "phrase", "is", "unknown"}, nil)
-				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+				terms := []string{"test", "phrase", "is", "unknown"}
+				db.EXPECT().FindComicsInfo(gomock.Any(), terms).Return([]core.ComicInfo{
 					{Comic: core.Comic{ID: 1, URL: "url1"}, Words: []string{"test", "phrase"}},
 					{Comic: core.Comic{ID: 2, URL: "url2"}, Words: []string{"test", "phrase", "unknown"}},
 					{Comic: core.Comic{ID: 3, URL: "url3"}, Words: []string{"test"}},
 				}, nil)
+				db.EXPECT().CorpusStats(gomock.Any(), terms).Return(core.CorpusStats{
+					Docs: 4, TotalLen: 7, MaxID: 4, DF: map[string]int{"test": 3, "phrase": 2, "unknown": 1},
+				}, nil)
 			},
 			expected: []core.Comic{
-				{ID: 2, URL: "url2"},
-				{ID: 1, URL: "url1"},
-				{ID: 3, URL: "url3"},
+				{ID: 2, URL: "url2", Score: 3.5, MatchedTerms: []string{"test", "phrase", "unknown"}},
+				{ID: 1, URL: "url1", Score: 2.5, MatchedTerms: []string{"test", "phrase"}},
+				{ID: 3, URL: "url3", Score: 1.5, MatchedTerms: []string{"test"}},
 			},
 			wantErr: false,
 		},
@@ -79,7 +85,19 @@ func TestSearch(t *testing.T) {
 				words.EXPECT().Norm(gomock.Any(), "test").Do(func(ctx context.Context, phrase string) {
 					require.Equal(t, "test", phrase)
 				}).Return([]string{"test"}, nil)
-				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return(nil, errors.New("db error"))
+				db.EXPECT().FindComicsInfo(gomock.Any(), []string{"test"}).Return(nil, errors.New("db error"))
+			},
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			desc:   "error - corpus stats failed",
+			phrase: "test",
+			limit:  10,
+			prepare: func(db *core.MockDB, words *core.MockWords) {
+				words.EXPECT().Norm(gomock.Any(), "test").Return([]string{"test"}, nil)
+				db.EXPECT().FindComicsInfo(gomock.Any(), []string{"test"}).Return(nil, nil)
+				db.EXPECT().CorpusStats(gomock.Any(), []string{"test"}).Return(core.CorpusStats{}, errors.New("db error"))
 			},
 			expected: nil,
 			wantErr:  true,
@@ -92,8 +110,9 @@ func TestSearch(t *testing.T) {
 				words.EXPECT().Norm(gomock.Any(), "test").Do(func(ctx context.Context, phrase string) {
 					require.Equal(t, "test", phrase)
 				}).Return([]string{"test"}, nil)
-				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
-					{Comic: core.Comic{ID: 1, URL: "url"}, Words: []string{"other"}},
+				db.EXPECT().FindComicsInfo(gomock.Any(), []string{"test"}).Return(nil, nil)
+				db.EXPECT().CorpusStats(gomock.Any(), []string{"test"}).Return(core.CorpusStats{
+					Docs: 1, TotalLen: 1, MaxID: 1, DF: map[string]int{"test": 0},
 				}, nil)
 			},
 			expected: []core.Comic{},
@@ -107,13 +126,17 @@ func TestSearch(t *testing.T) {
 				words.EXPECT().Norm(gomock.Any(), "test,phrase").Do(func(ctx context.Context, phrase string) {
 					require.Equal(t, "test,phrase", phrase)
 				}).Return([]string{"test", "phrase"}, nil)
-				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+				terms := []string{"test", "phrase"}
+				db.EXPECT().FindComicsInfo(gomock.Any(), terms).Return([]core.ComicInfo{
 					{Comic: core.Comic{ID: 1, URL: "url1"}, Words: []string{"test", "phrase"}},
 					{Comic: core.Comic{ID: 2, URL: "url2"}, Words: []string{"test"}},
 				}, nil)
+				db.EXPECT().CorpusStats(gomock.Any(), terms).Return(core.CorpusStats{
+					Docs: 2, TotalLen: 3, MaxID: 2, DF: map[string]int{"test": 2, "phrase": 1},
+				}, nil)
 			},
 			expected: []core.Comic{
-				{ID: 1, URL: "url1"},
+				{ID: 1, URL: "url1", Score: 2.5, MatchedTerms: []string{"test", "phrase"}},
 			},
 			wantErr: false,
 		},
@@ -129,16 +152,17 @@ func TestSearch(t *testing.T) {
 
 			tc.prepare(mockDB, mockWords)
 
-			service, err := core.NewService(slog.Default(), mockDB, mockWords)
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{Ranker: core.RankerMatches})
 			require.NoError(t, err)
 
-			comics, err := service.Search(context.TODO(), tc.phrase, tc.limit)
+			result, err := service.Search(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: tc.limit})
 
 			if tc.wantErr {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tc.expected, comics)
+				require.Equal(t, tc.expected, result.Comics)
+				require.Equal(t, core.RankerMatches, result.Ranker)
 			}
 		})
 	}
@@ -149,6 +173,7 @@ func TestISearch(t *testing.T) {
 		desc     string
 		phrase   string
 		limit    int64
+		indexed  []core.ComicInfo
 		prepare  func(*core.MockDB, *core.MockWords)
 		expected []core.Comic
 		wantErr  bool
@@ -159,7 +184,6 @@ func TestISearch(t *testing.T) {
 			limit:  10,
 			prepare: func(db *core.MockDB, words *core.MockWords) {
 				words.EXPECT().Norm(gomock.Any(), "test phrase").Return([]string{"test", "phrase"}, nil)
-				db.EXPECT().GetComicsByIds(gomock.Any(), []int64{}).Return([]core.Comic{}, nil)
 			},
 			expected: []core.Comic{},
 			wantErr:  false,
@@ -202,15 +226,21 @@ func TestISearch(t *testing.T) {
 			wantErr:  true,
 		},
 		{
-			desc:   "error - failed to get comics by ids from db",
+			desc:   "success - answered without db",
 			phrase: "test",
 			limit:  10,
+			indexed: []core.ComicInfo{
+				{Comic: core.Comic{ID: 1, URL: "url1", Title: "Test"}, Words: []string{"test", "linux"}},
+				{Comic: core.Comic{ID: 2, URL: "url2", Title: "Other"}, Words: []string{"linux"}},
+			},
 			prepare: func(db *core.MockDB, words *core.MockWords) {
+				// база нужна только для сборки индекса, поля для выдачи берутся из него
 				words.EXPECT().Norm(gomock.Any(), "test").Return([]string{"test"}, nil)
-				db.EXPECT().GetComicsByIds(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
 			},
-			expected: nil,
-			wantErr:  true,
+			expected: []core.Comic{
+				{ID: 1, URL: "url1", Title: "Test", Score: 1.25, MatchedTerms: []string{"test"}},
+			},
+			wantErr: false,
 		},
 	}
 
@@ -220,51 +250,287 @@ func TestISearch(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockDB := core.NewMockDB(ctrl)
-			mockWords := core.NewMockWords(ctrl)
+			mockWords := newIndexingWords(ctrl)
 
 			tc.prepare(mockDB, mockWords)
 
-			service, err := core.NewService(slog.Default(), mockDB, mockWords)
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{Ranker: core.RankerMatches})
 			require.NoError(t, err)
+			if tc.indexed != nil {
+				mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(tc.indexed, nil)
+				require.NoError(t, service.UpdateIndex(context.TODO()))
+			}
 
-			comics, err := service.ISearch(context.TODO(), tc.phrase, tc.limit)
+			result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: tc.limit})
 
 			if tc.wantErr {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tc.expected, comics)
+				require.Equal(t, tc.expected, result.Comics)
+			}
+		})
+	}
+}
+
+func TestISearchBM25(t *testing.T) {
+	indexed := []core.ComicInfo{
+		{Comic: core.Comic{ID: 1, URL: "url1"}, Words: []string{"xkcd", "comic"}, Terms: []string{"xkcd", "comic", "comic"}},
+		{Comic: core.Comic{ID: 2, URL: "url2"}, Words: []string{"xkcd", "robot"}, Terms: []string{"xkcd", "robot"}},
+		{Comic: core.Comic{ID: 3, URL: "url3"}, Words: []string{"xkcd", "comic"}, Terms: []string{"xkcd", "comic"}},
+	}
+	testCases := []struct {
+		desc     string
+		keywords []string
+		opts     core.Options
+		expected []int64
+	}{
+		{
+			desc:     "rare term outweighs common",
+			keywords: []string{"xkcd", "robot"},
+			opts:     core.Options{Ranker: core.RankerBM25, K1: 1.2, B: 0.75},
+			expected: []int64{2, 3, 1},
+		},
+		{
+			desc:     "term frequency",
+			keywords: []string{"comic"},
+			opts:     core.Options{Ranker: core.RankerBM25, K1: 1.2, B: 0.75},
+			expected: []int64{1, 3},
+		},
+		{
+			desc:     "ties broken by id",
+			keywords: []string{"xkcd"},
+			opts:     core.Options{Ranker: core.RankerBM25, K1: 1.2, B: 0},
+			expected: []int64{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockWords := core.NewMockWords(ctrl)
+
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+			mockWords.EXPECT().Norm(gomock.Any(), "phrase").Return(tc.keywords, nil)
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, tc.opts)
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "phrase", Limit: 10})
+			require.NoError(t, err)
+			require.Equal(t, core.RankerBM25, result.Ranker)
+			ids := make([]int64, len(result.Comics))
+			for i, comic := range result.Comics {
+				ids[i] = comic.ID
 			}
+			require.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestISearchPaging(t *testing.T) {
+	indexed := []core.ComicInfo{
+		{Comic: core.Comic{ID: 1}, Words: []string{"xkcd"}},
+		{Comic: core.Comic{ID: 2}, Words: []string{"xkcd"}},
+		{Comic: core.Comic{ID: 3}, Words: []string{"xkcd"}},
+		{Comic: core.Comic{ID: 4}, Words: []string{"xkcd"}},
+		{Comic: core.Comic{ID: 5}, Words: []string{"xkcd"}},
+	}
+	testCases := []struct {
+		desc     string
+		limit    int64
+		offset   int64
+		maxLimit int64
+		expected []int64
+	}{
+		{
+			desc:     "first page",
+			limit:    2,
+			expected: []int64{1, 2},
+		},
+		{
+			desc:     "middle page",
+			limit:    2,
+			offset:   2,
+			expected: []int64{3, 4},
+		},
+		{
+			desc:     "last partial page",
+			limit:    2,
+			offset:   4,
+			expected: []int64{5},
+		},
+		{
+			desc:     "offset past the end",
+			limit:    2,
+			offset:   10,
+			expected: []int64{},
+		},
+		{
+			desc:     "limit capped by max limit",
+			limit:    10,
+			offset:   1,
+			maxLimit: 3,
+			expected: []int64{2, 3, 4},
+		},
+		{
+			desc:     "huge limit without max limit",
+			limit:    math.MaxInt64,
+			offset:   1,
+			expected: []int64{2, 3, 4, 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockWords := core.NewMockWords(ctrl)
+
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+			mockWords.EXPECT().Norm(gomock.Any(), "xkcd").Return([]string{"xkcd"}, nil)
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{MaxLimit: tc.maxLimit})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			result, err := service.ISearch(context.TODO(), core.SearchRequest{
+				Phrase: "xkcd",
+				Limit:  tc.limit,
+				Offset: tc.offset,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(len(indexed)), result.TotalHits)
+			ids := make([]int64, len(result.Comics))
+			for i, comic := range result.Comics {
+				ids[i] = comic.ID
+			}
+			require.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestISearchPhonetic(t *testing.T) {
+	indexed := []core.ComicInfo{
+		{Comic: core.Comic{ID: 1, URL: "url1"}, Words: []string{"randal", "munro"}, Phonetics: []string{"RNTL", "MNR"}},
+		{Comic: core.Comic{ID: 2, URL: "url2"}, Words: []string{"bobbi", "tabl"}, Phonetics: []string{"PP", "TPLS"}},
+		{Comic: core.Comic{ID: 3, URL: "url3"}, Words: []string{"munro", "tabl"}, Phonetics: []string{"MNR", "TPLS"}},
+	}
+	testCases := []struct {
+		desc     string
+		phrase   string
+		minHits  int
+		prepare  func(*core.MockDB, *core.MockWords)
+		expected []core.Comic
+	}{
+		{
+			desc:    "phonetic matches ranked after exact",
+			phrase:  "randal munroe",
+			minHits: 5,
+			prepare: func(db *core.MockDB, words *core.MockWords) {
+				words.EXPECT().Norm(gomock.Any(), "randal munroe").Return([]string{"randal", "munro"}, nil)
+				words.EXPECT().Phonetics(gomock.Any(), "randal munroe").Return([]string{"RNTL", "MNR"}, nil)
+			},
+			expected: []core.Comic{{ID: 1, URL: "url1"}, {ID: 3, URL: "url3"}},
+		},
+		{
+			desc:    "misspelled phrase found by sound",
+			phrase:  "bobby tabels",
+			minHits: 2,
+			prepare: func(db *core.MockDB, words *core.MockWords) {
+				words.EXPECT().Norm(gomock.Any(), "bobby tabels").Return([]string{"bobbi", "tabel"}, nil)
+				words.EXPECT().Phonetics(gomock.Any(), "bobby tabels").Return([]string{"PP", "TPLS"}, nil)
+			},
+			expected: []core.Comic{{ID: 2, URL: "url2"}, {ID: 3, URL: "url3"}},
+		},
+		{
+			desc:    "enough exact hits",
+			phrase:  "munro",
+			minHits: 2,
+			prepare: func(db *core.MockDB, words *core.MockWords) {
+				words.EXPECT().Norm(gomock.Any(), "munro").Return([]string{"munro"}, nil)
+			},
+			expected: []core.Comic{{ID: 1, URL: "url1"}, {ID: 3, URL: "url3"}},
+		},
+		{
+			desc:    "phonetic error keeps exact matches",
+			phrase:  "randal",
+			minHits: 5,
+			prepare: func(db *core.MockDB, words *core.MockWords) {
+				words.EXPECT().Norm(gomock.Any(), "randal").Return([]string{"randal"}, nil)
+				words.EXPECT().Phonetics(gomock.Any(), "randal").Return(nil, errors.New("words error"))
+			},
+			expected: []core.Comic{{ID: 1, URL: "url1"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockWords := core.NewMockWords(ctrl)
+
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+			tc.prepare(mockDB, mockWords)
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{PhoneticMinHits: tc.minHits})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, withoutScores(result.Comics))
 		})
 	}
 }
 
+// withoutScores убирает оценки, чтобы сравнивать только порядок комиксов.
+func withoutScores(comics []core.Comic) []core.Comic {
+	for i := range comics {
+		comics[i].Score = 0
+		comics[i].MatchedTerms = nil
+	}
+	return comics
+}
+
 func TestUpdateIndex(t *testing.T) {
 	testCases := []struct {
-		desc    string
-		prepare func(*core.MockDB)
-		wantErr bool
+		desc       string
+		prepare    func(*core.MockDB)
+		generation uint64
+		wantErr    bool
 	}{
 		{
 			desc: "success - updated index",
 			prepare: func(db *core.MockDB) {
 				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{{Comic: core.Comic{}, Words: []string{"test"}}}, nil)
 			},
-			wantErr: false,
+			generation: 1,
+			wantErr:    false,
 		},
 		{
 			desc: "success - empty db",
 			prepare: func(db *core.MockDB) {
 				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{}, nil)
 			},
-			wantErr: false,
+			generation: 1,
+			wantErr:    false,
 		},
 		{
 			desc: "error - failed to get all comcis info from db",
 			prepare: func(db *core.MockDB) {
 				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return(nil, errors.New("db error"))
 			},
-			wantErr: true,
+			generation: 0,
+			wantErr:    true,
 		},
 	}
 
@@ -278,7 +544,7 @@ func TestUpdateIndex(t *testing.T) {
 
 			tc.prepare(mockDB)
 
-			service, err := core.NewService(slog.Default(), mockDB, mockWords)
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
 			require.NoError(t, err)
 
 			err = service.UpdateIndex(context.TODO())
@@ -288,20 +554,175 @@ func TestUpdateIndex(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 			}
+			require.Equal(t, tc.generation, service.IndexStatus(context.TODO()).Generation)
 		})
 	}
 }
 
+func TestIndexStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+		fieldComic(2, []string{"linux"}, []string{"cat"}, nil),
+		fieldComic(1, []string{"linux", "kernel"}, nil, nil),
+	}, nil)
+
+	service, err := core.NewService(slog.Default(), mockDB, core.NewMockWords(ctrl), nil, core.Options{})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	// linux, kernel и cat по всему описанию, linux и kernel в заголовке, cat в расшифровке;
+	// каждый id занимает байт
+	stats := service.IndexStats(context.TODO())
+	require.Equal(t, 3, stats.Terms)
+	require.Equal(t, 8, stats.Postings)
+	require.Equal(t, 2+5+1+6+1+3+2+5+1+6+1+3, stats.Bytes)
+	require.False(t, stats.BuiltAt.IsZero())
+
+	require.NoError(t, service.HandleEvent(context.TODO(), core.Event{Type: core.EventUpdate, Deleted: []int64{1}}))
+	stats = service.IndexStats(context.TODO())
+	require.Equal(t, 2, stats.Terms)
+	require.Equal(t, 4, stats.Postings)
+	require.Equal(t, 1+5+1+3+1+5+1+3, stats.Bytes)
+}
+
+// blockingUpdate возвращает первую сборку индекса, которая ждет release,
+// и канал, закрывающийся, когда она началась.
+func blockingUpdate(db *core.MockDB, release chan struct{}, infos []core.ComicInfo) (*gomock.Call, chan struct{}) {
+	started := make(chan struct{})
+	call := db.EXPECT().GetAllComicsInfo(gomock.Any()).DoAndReturn(
+		func(context.Context) ([]core.ComicInfo, error) {
+			close(started)
+			<-release
+			return infos, nil
+		})
+	return call, started
+}
+
+func TestUpdateIndexConcurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+
+	release := make(chan struct{})
+	first, started := blockingUpdate(mockDB, release, []core.ComicInfo{
+		{Comic: core.Comic{ID: 1}, Words: []string{"linux"}},
+	})
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+		{Comic: core.Comic{ID: 1}, Words: []string{"linux"}},
+		{Comic: core.Comic{ID: 2}, Words: []string{"linux"}},
+	}, nil).After(first)
+	mockWords.EXPECT().Norm(gomock.Any(), "linux").Return([]string{"linux"}, nil).AnyTimes()
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- service.UpdateIndex(context.TODO()) }()
+	<-started
+
+	// сборка не блокирует поиск: он идет по прежней версии
+	result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, result.Comics)
+	require.Equal(t, uint64(0), result.IndexGeneration)
+
+	// запросы во время сборки склеиваются в одну следующую сборку,
+	// которая не отменяется вместе с контекстом вызвавшего
+	canceled, cancel := context.WithCancel(context.TODO())
+	cancel()
+	for range 3 {
+		require.ErrorIs(t, service.UpdateIndex(canceled), context.Canceled)
+	}
+
+	close(release)
+	require.NoError(t, <-errs)
+	require.Eventually(t, func() bool { return service.IndexStatus(context.TODO()).Generation == 2 }, time.Second, time.Millisecond)
+
+	result, err = service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Comics, 2)
+	require.Equal(t, uint64(2), result.IndexGeneration)
+}
+
+func TestResetIndexDuringUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	release := make(chan struct{})
+	_, started := blockingUpdate(mockDB, release, []core.ComicInfo{
+		{Comic: core.Comic{ID: 1}, Words: []string{"linux"}},
+	})
+
+	service, err := core.NewService(slog.Default(), mockDB, core.NewMockWords(ctrl), nil, core.Options{})
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- service.UpdateIndex(context.TODO()) }()
+	<-started
+	service.ResetIndex()
+	close(release)
+
+	// данные, прочитанные до сброса, не публикуются
+	require.NoError(t, <-errs)
+	require.Equal(t, uint64(1), service.IndexStatus(context.TODO()).Generation)
+	suggestions, err := service.Suggest(context.TODO(), "li", 10)
+	require.NoError(t, err)
+	require.Empty(t, suggestions)
+}
+
+func TestHandleEventDuringUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	release := make(chan struct{})
+	// сборка прочитала базу до появления комикса 2
+	_, started := blockingUpdate(mockDB, release, []core.ComicInfo{
+		{Comic: core.Comic{ID: 1}, Words: []string{"linux"}},
+	})
+	mockDB.EXPECT().GetComicsInfoByIds(gomock.Any(), []int64{2}).Return([]core.ComicInfo{
+		{Comic: core.Comic{ID: 2}, Words: []string{"linux"}},
+	}, nil)
+	mockWords := core.NewMockWords(ctrl)
+	mockWords.EXPECT().Norm(gomock.Any(), "linux").Return([]string{"linux"}, nil).AnyTimes()
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() { errs <- service.UpdateIndex(context.TODO()) }()
+	<-started
+
+	// событие не ждет окончания сборки
+	require.NoError(t, service.HandleEvent(context.TODO(), core.Event{Type: core.EventUpdate, Added: []int64{2}}))
+	require.Equal(t, uint64(1), service.IndexStatus(context.TODO()).Generation)
+
+	close(release)
+	require.NoError(t, <-errs)
+
+	// изменение из события повторено поверх прочитанного сборкой
+	result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Comics, 2)
+	require.Equal(t, uint64(2), result.IndexGeneration)
+}
+
 func TestHandleEvent(t *testing.T) {
 	testCases := []struct {
 		desc    string
-		event   core.EventType
+		event   core.Event
 		prepare func(*core.MockDB)
 		wantErr bool
 	}{
 		{
 			desc:  "success - handled 'update' event",
-			event: "update",
+			event: core.Event{Type: core.EventUpdate},
 			prepare: func(db *core.MockDB) {
 				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{{Comic: core.Comic{}, Words: []string{"test"}}}, nil)
 			},
@@ -309,19 +730,35 @@ func TestHandleEvent(t *testing.T) {
 		},
 		{
 			desc:    "success - handled 'reset' event",
-			event:   "reset",
+			event:   core.Event{Type: core.EventReset},
 			prepare: func(db *core.MockDB) {},
 			wantErr: false,
 		},
 		{
 			desc:    "success - unknown event is not error",
-			event:   "reset",
+			event:   core.Event{Type: core.EventReset},
 			prepare: func(db *core.MockDB) {},
 			wantErr: false,
 		},
+		{
+			desc:  "success - handled 'update' event with ids",
+			event: core.Event{Type: core.EventUpdate, Added: []int64{2}},
+			prepare: func(db *core.MockDB) {
+				db.EXPECT().GetComicsInfoByIds(gomock.Any(), []int64{2}).Return([]core.ComicInfo{{Comic: core.Comic{ID: 2}, Words: []string{"test"}}}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			desc:  "error - failed to get changed comics",
+			event: core.Event{Type: core.EventUpdate, Updated: []int64{2}},
+			prepare: func(db *core.MockDB) {
+				db.EXPECT().GetComicsInfoByIds(gomock.Any(), []int64{2}).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
 		{
 			desc:  "error - failed to update index",
-			event: "update",
+			event: core.Event{Type: core.EventUpdate},
 			prepare: func(db *core.MockDB) {
 				db.EXPECT().GetAllComicsInfo(gomock.Any()).Return(nil, errors.New("db error"))
 			},
@@ -339,7 +776,7 @@ func TestHandleEvent(t *testing.T) {
 
 			tc.prepare(mockDB)
 
-			service, err := core.NewService(slog.Default(), mockDB, mockWords)
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
 			require.NoError(t, err)
 
 			err = service.HandleEvent(context.TODO(), tc.event)
@@ -352,3 +789,59 @@ func TestHandleEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleEventDelta(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := newIndexingWords(ctrl)
+
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+		textComic(1, []string{"linux"}, nil, nil),
+		textComic(2, []string{"linux", "kernel"}, nil, nil),
+		textComic(3, []string{"kernel"}, nil, nil),
+	}, nil)
+	mockDB.EXPECT().GetComicsInfoByIds(gomock.Any(), []int64{4, 2}).Return([]core.ComicInfo{
+		textComic(4, []string{"kernel", "panic"}, nil, nil),
+		textComic(2, []string{"windows"}, nil, nil),
+	}, nil)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	err = service.HandleEvent(context.TODO(), core.Event{
+		Type:    core.EventUpdate,
+		Added:   []int64{4},
+		Updated: []int64{2},
+		Deleted: []int64{3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), service.IndexStatus(context.TODO()).Generation)
+
+	testCases := []struct {
+		phrase   string
+		expected []int64
+	}{
+		{phrase: "linux", expected: []int64{1}},
+		{phrase: "kernel", expected: []int64{4}},
+		{phrase: "title:windows", expected: []int64{2}},
+		{phrase: "panic", expected: []int64{4}},
+	}
+	for _, tc := range testCases {
+		result, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: tc.phrase, Limit: 10})
+		require.NoError(t, err)
+		ids := make([]int64, len(result.Comics))
+		for i, comic := range result.Comics {
+			ids[i] = comic.ID
+		}
+		require.ElementsMatch(t, tc.expected, ids, tc.phrase)
+	}
+
+	// словари пересобраны по новой версии
+	suggestions, err := service.Suggest(context.TODO(), "k", 10)
+	require.NoError(t, err)
+	require.Equal(t, []core.Suggestion{{Text: "kernel", Count: 1}}, suggestions)
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// similarity ищет похожие комиксы по косинусу между векторами TF-IDF их слов:
+// вес слова - tf * ln(N / df). Нормы векторов считаются один раз на версию индекса.
+type similarity struct {
+	index *invertedIndex
+	norms map[int64]float64
+}
+
+type similarComic struct {
+	id    int64
+	score float64
+	terms []string // общие слова по убыванию вклада
+}
+
+func newSimilarity(idx *invertedIndex) *similarity {
+	s := &similarity{index: idx, norms: make(map[int64]float64, idx.docs.len())}
+	for id, doc := range idx.docs.all() {
+		var norm float64
+		for term, tf := range doc.tf {
+			weight := float64(tf) * s.idf(term)
+			norm += weight * weight
+		}
+		s.norms[id] = math.Sqrt(norm)
+	}
+	return s
+}
+
+func (s *similarity) idf(term string) float64 {
+	df := s.index.postings.lookup(term).len()
+	if df == 0 {
+		return 0
+	}
+	return math.Log(float64(s.index.docs.len()) / float64(df))
+}
+
+// similar возвращает комиксы, похожие на комикс id, по убыванию сходства,
+// без него самого. ok - комикс есть в индексе.
+func (s *similarity) similar(id int64) (comics []similarComic, ok bool) {
+	doc, ok := s.index.docs.get(id)
+	if !ok {
+		return nil, false
+	}
+	norm := s.norms[id]
+	if norm == 0 {
+		return nil, true
+	}
+
+	type contribution struct {
+		term   string
+		weight float64
+	}
+	shared := map[int64][]contribution{}
+	dots := map[int64]float64{}
+	for term, tf := range doc.tf {
+		idf := s.idf(term)
+		if idf == 0 {
+			continue
+		}
+		for _, other := range s.index.postings.lookup(term).ids() {
+			if other == id {
+				continue
+			}
+			weight := float64(tf) * float64(s.index.docs.lookup(other).tf[term]) * idf * idf
+			dots[other] += weight
+			shared[other] = append(shared[other], contribution{term: term, weight: weight})
+		}
+	}
+
+	comics = make([]similarComic, 0, len(dots))
+	for other, dot := range dots {
+		contributions := shared[other]
+		slices.SortFunc(contributions, func(a, b contribution) int {
+			return cmp.Or(cmp.Compare(b.weight, a.weight), cmp.Compare(a.term, b.term))
+		})
+		terms := make([]string, len(contributions))
+		for i, c := range contributions {
+			terms[i] = c.term
+		}
+		comics = append(comics, similarComic{id: other, score: dot / (norm * s.norms[other]), terms: terms})
+	}
+	slices.SortFunc(comics, func(a, b similarComic) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.id, b.id))
+	})
+	return comics, true
+}
@@ -0,0 +1,105 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"search-service/search/core"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSimilar(t *testing.T) {
+	indexed := []core.ComicInfo{
+		fieldComic(1, []string{"linux", "kernel", "cat"}, nil, nil),
+		fieldComic(2, []string{"linux", "kernel"}, nil, nil),
+		fieldComic(3, []string{"cat"}, []string{"dog"}, nil),
+		fieldComic(4, []string{"python"}, nil, nil),
+		fieldComic(5, []string{"cat", "kernel", "linux"}, nil, nil),
+	}
+
+	testCases := []struct {
+		desc      string
+		id        int64
+		limit     int64
+		ids       []int64
+		terms     [][]string
+		totalHits int64
+		wantErr   error
+	}{
+		{
+			desc:      "success - ordered by similarity without the comic itself",
+			id:        1,
+			limit:     10,
+			ids:       []int64{5, 2, 3},
+			terms:     [][]string{{"cat", "kernel", "linux"}, {"kernel", "linux"}, {"cat"}},
+			totalHits: 3,
+		},
+		{
+			desc:      "success - limited",
+			id:        1,
+			limit:     1,
+			ids:       []int64{5},
+			terms:     [][]string{{"cat", "kernel", "linux"}},
+			totalHits: 3,
+		},
+		{
+			desc:      "success - no shared words",
+			id:        4,
+			limit:     10,
+			ids:       []int64{},
+			terms:     [][]string{},
+			totalHits: 0,
+		},
+		{
+			desc:    "error - comic not in index",
+			id:      42,
+			limit:   10,
+			wantErr: core.ErrNotFound,
+		},
+		{
+			desc:    "error - bad limit",
+			id:      1,
+			wantErr: core.ErrBadArguments,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+
+			service, err := core.NewService(slog.Default(), mockDB, core.NewMockWords(ctrl), nil, core.Options{})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			result, err := service.Similar(context.TODO(), tc.id, tc.limit)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.totalHits, result.TotalHits)
+			require.Equal(t, uint64(1), result.IndexGeneration)
+
+			ids := make([]int64, len(result.Comics))
+			terms := make([][]string, len(result.Comics))
+			for i, comic := range result.Comics {
+				ids[i] = comic.ID
+				terms[i] = comic.MatchedTerms
+			}
+			require.Equal(t, tc.ids, ids)
+			require.Equal(t, tc.terms, terms)
+			for i := 1; i < len(result.Comics); i++ {
+				require.Greater(t, result.Comics[i-1].Score, result.Comics[i].Score)
+			}
+			if len(result.Comics) > 0 && tc.ids[0] == 5 {
+				require.InDelta(t, 1, result.Comics[0].Score, 1e-9)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// snapshot - неизменяемая версия индекса ISearch вместе со словарями.
+// Поиск берет текущую версию без блокировок, обновление строит новую
+// и подменяет указатель.
+//
+// Словари, нормы для похожих комиксов, список номеров и размер индекса
+// строятся при первом обращении: при потоке событий версии сменяют друг друга
+// быстрее, чем до них доходят запросы, и пересобирать все на каждое событие
+// незачем.
+type snapshot struct {
+	generation    uint64
+	source        IndexSource
+	updatedAt     time.Time
+	index         *invertedIndex
+	suggester     *lazy[*suggester]
+	vocabulary    *lazy[*bkTree]
+	similarity    *lazy[*similarity]
+	ids           *lazy[[]int64] // номера комиксов по возрастанию
+	size          *lazy[[3]int]  // термины, записи в списках, байты
+	buildDuration time.Duration
+	builtAt       time.Time
+}
+
+// newSnapshot собирает версию индекса; started - начало ее сборки.
+func newSnapshot(
+	generation uint64, source IndexSource, updatedAt time.Time, index *invertedIndex, started time.Time,
+) *snapshot {
+	words := newLazy(index.vocabulary)
+	builtAt := time.Now()
+	return &snapshot{
+		generation: generation,
+		source:     source,
+		updatedAt:  updatedAt,
+		index:      index,
+		suggester:  newLazy(func() *suggester { return newSuggester(words.get()) }),
+		vocabulary: newLazy(func() *bkTree { return newBKTree(words.get()) }),
+		similarity: newLazy(func() *similarity { return newSimilarity(index) }),
+		ids:        newLazy(func() []int64 { return slices.Sorted(index.docs.keys()) }),
+		size: newLazy(func() [3]int {
+			terms, postings, bytes := index.size()
+			return [3]int{terms, postings, bytes}
+		}),
+		buildDuration: builtAt.Sub(started),
+		builtAt:       builtAt,
+	}
+}
+
+// warm строит все отложенное заранее. Полная сборка идет в фоне, и первые
+// запросы к новой версии не должны ждать словарей.
+func (s *snapshot) warm() {
+	s.suggester.get()
+	s.vocabulary.get()
+	s.similarity.get()
+	s.ids.get()
+	s.size.get()
+}
+
+func (s *snapshot) stats() IndexStats {
+	size := s.size.get()
+	return IndexStats{
+		Terms:         size[0],
+		Postings:      size[1],
+		Bytes:         size[2],
+		BuildDuration: s.buildDuration,
+		BuiltAt:       s.builtAt,
+	}
+}
+
+// lazy - значение, которое вычисляется один раз при первом обращении.
+type lazy[T any] struct {
+	once  sync.Once
+	build func() T
+	value T
+}
+
+func newLazy[T any](build func() T) *lazy[T] {
+	return &lazy[T]{build: build}
+}
+
+func (l *lazy[T]) get() T {
+	l.once.Do(func() {
+		l.value = l.build()
+		l.build = nil
+	})
+	return l.value
+}
+
+// detail возвращает комикс с соседями по номеру.
+func (s *snapshot) detail(id int64) (ComicDetail, bool) {
+	ids := s.ids.get()
+	pos, ok := slices.BinarySearch(ids, id)
+	if !ok {
+		return ComicDetail{}, false
+	}
+	detail := ComicDetail{Comic: s.index.docs.lookup(id).info.Comic}
+	if pos > 0 {
+		detail.PrevID = ids[pos-1]
+	}
+	if pos+1 < len(ids) {
+		detail.NextID = ids[pos+1]
+	}
+	return detail, true
+}
+
+// rebuilds склеивает одновременные запросы на обновление индекса: пока идет
+// одна сборка, все новые запросы ждут одну следующую, которая прочитает базу
+// уже после них.
+type rebuilds struct {
+	mu      sync.Mutex
+	running bool
+	next    *rebuild
+}
+
+type rebuild struct {
+	done chan struct{}
+	err  error
+}
+
+// request ставит запрос в следующую сборку и запускает сборки, если они не
+// идут. Сборка не отменяется вместе с ctx: ее результат ждут и другие запросы.
+func (r *rebuilds) request(ctx context.Context, build func(context.Context) error) *rebuild {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next == nil {
+		r.next = &rebuild{done: make(chan struct{})}
+	}
+	next := r.next
+	if !r.running {
+		r.running = true
+		go r.run(context.WithoutCancel(ctx), build)
+	}
+	return next
+}
+
+func (r *rebuilds) run(ctx context.Context, build func(context.Context) error) {
+	for {
+		r.mu.Lock()
+		current := r.next
+		r.next = nil
+		if current == nil {
+			r.running = false
+			r.mu.Unlock()
+			return
+		}
+		r.mu.Unlock()
+
+		current.err = build(ctx)
+		close(current.done)
+	}
+}
@@ -0,0 +1,126 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"search-service/search/core"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSnapshotSaveLoad(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	indexed := []core.ComicInfo{
+		fieldComic(1, []string{"linux", "kernel"}, nil, nil),
+		fieldComic(2, []string{"cat"}, []string{"linux"}, nil),
+	}
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockStore := core.NewMockSnapshotStore(ctrl)
+
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+	var saved core.IndexSnapshot
+	// пока версия индекса не меняется, снимок пишется один раз
+	mockStore.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, snapshot core.IndexSnapshot) error {
+			saved = snapshot
+			return nil
+		})
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, mockStore, core.Options{})
+	require.NoError(t, err)
+	// пустой индекс не сохраняется
+	require.NoError(t, service.SaveSnapshot(context.TODO()))
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+	require.NoError(t, service.SaveSnapshot(context.TODO()))
+	require.NoError(t, service.SaveSnapshot(context.TODO()))
+
+	require.Equal(t, uint64(1), saved.Generation)
+	require.Equal(t, indexed, saved.Comics)
+	expected, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+
+	// новый сервис поднимает индекс из снимка без базы
+	mockStore.EXPECT().Load(gomock.Any()).Return(saved, nil)
+	restarted, err := core.NewService(slog.Default(), mockDB, mockWords, mockStore, core.Options{})
+	require.NoError(t, err)
+	require.NoError(t, restarted.LoadSnapshot(context.TODO()))
+
+	status := restarted.IndexStatus(context.TODO())
+	require.Equal(t, uint64(1), status.Generation)
+	require.Equal(t, core.IndexSourceSnapshot, status.Source)
+	require.Equal(t, saved.UpdatedAt, status.UpdatedAt)
+	require.Equal(t, 2, status.Comics)
+
+	result, err := restarted.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, expected.Comics, result.Comics)
+
+	// только что загруженный снимок не переписывается
+	require.NoError(t, restarted.SaveSnapshot(context.TODO()))
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		store   func(*gomock.Controller) core.SnapshotStore
+		wantErr error
+	}{
+		{
+			desc:    "snapshots disabled",
+			store:   func(*gomock.Controller) core.SnapshotStore { return nil },
+			wantErr: core.ErrSnapshotNotFound,
+		},
+		{
+			desc: "corrupt snapshot",
+			store: func(ctrl *gomock.Controller) core.SnapshotStore {
+				store := core.NewMockSnapshotStore(ctrl)
+				store.EXPECT().Load(gomock.Any()).Return(core.IndexSnapshot{}, core.ErrSnapshotCorrupt)
+				return store
+			},
+			wantErr: core.ErrSnapshotCorrupt,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, err := core.NewService(slog.Default(), core.NewMockDB(ctrl), core.NewMockWords(ctrl), tc.store(ctrl), core.Options{})
+			require.NoError(t, err)
+
+			err = service.LoadSnapshot(context.TODO())
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, core.IndexSourceEmpty, service.IndexStatus(context.TODO()).Source)
+		})
+	}
+}
+
+func TestSaveSnapshotError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockStore := core.NewMockSnapshotStore(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{fieldComic(1, []string{"linux"}, nil, nil)}, nil)
+	// после ошибки снимок пробуется снова
+	gomock.InOrder(
+		mockStore.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("disk full")),
+		mockStore.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	service, err := core.NewService(slog.Default(), mockDB, core.NewMockWords(ctrl), mockStore, core.Options{})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	require.Error(t, service.SaveSnapshot(context.TODO()))
+	require.NoError(t, service.SaveSnapshot(context.TODO()))
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// SortOrder - порядок результатов поиска.
+type SortOrder string
+
+const (
+	SortRelevance SortOrder = "relevance" // по оценке ранжировщика, по умолчанию
+	SortIDDesc    SortOrder = "id_desc"   // сначала новые по номеру
+	SortIDAsc     SortOrder = "id_asc"    // сначала старые по номеру
+	SortDate      SortOrder = "date"      // сначала новые по дате публикации
+)
+
+func (o SortOrder) valid() bool {
+	switch o {
+	case "", SortRelevance, SortIDDesc, SortIDAsc, SortDate:
+		return true
+	}
+	return false
+}
+
+// sortHits упорядочивает найденные комиксы на месте. По релевантности они
+// уже упорядочены ранжировщиком, остальные порядки не зависят от оценки,
+// и совпадения по звучанию перемешиваются с точными. При равных датах
+// выше комикс с большим номером, комиксы без даты - в конце.
+func sortHits(ids []int64, order SortOrder, comic func(int64) (Comic, bool)) {
+	switch order {
+	case SortIDDesc:
+		slices.SortFunc(ids, func(a, b int64) int { return cmp.Compare(b, a) })
+	case SortIDAsc:
+		slices.Sort(ids)
+	case SortDate:
+		published := make(map[int64]time.Time, len(ids))
+		for _, id := range ids {
+			if c, ok := comic(id); ok && c.Published != nil {
+				published[id] = *c.Published
+			}
+		}
+		slices.SortFunc(ids, func(a, b int64) int {
+			if c := published[b].Compare(published[a]); c != 0 {
+				return c
+			}
+			return cmp.Compare(b, a)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"search-service/search/core"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchSort(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		sort    core.SortOrder
+		limit   int64
+		offset  int64
+		ids     []int64
+		wantErr error
+	}{
+		{
+			desc:  "newest by number",
+			sort:  core.SortIDDesc,
+			limit: 10,
+			ids:   []int64{5, 4, 3, 2, 1},
+		},
+		{
+			desc:  "oldest by number",
+			sort:  core.SortIDAsc,
+			limit: 10,
+			ids:   []int64{1, 2, 3, 4, 5},
+		},
+		{
+			desc:  "by date - same date by number, unknown date last",
+			sort:  core.SortDate,
+			limit: 10,
+			ids:   []int64{4, 3, 2, 1, 5},
+		},
+		{
+			desc:   "by date - second page",
+			sort:   core.SortDate,
+			limit:  2,
+			offset: 2,
+			ids:    []int64{2, 1},
+		},
+		{
+			desc:   "oldest by number - last page",
+			sort:   core.SortIDAsc,
+			limit:  2,
+			offset: 4,
+			ids:    []int64{5},
+		},
+		{
+			desc:    "error - unknown order",
+			sort:    "popular",
+			limit:   10,
+			wantErr: core.ErrBadArguments,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, method := range []string{"Search", "ISearch"} {
+			t.Run(method+" - "+tc.desc, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				indexed := publishedComics()
+				mockDB := core.NewMockDB(ctrl)
+				mockWords := core.NewMockWords(ctrl)
+				mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+				service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+				require.NoError(t, err)
+
+				search := service.ISearch
+				if method == "Search" {
+					expectFindComics(mockDB, indexed)
+					search = service.Search
+				} else {
+					mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+					require.NoError(t, service.UpdateIndex(context.TODO()))
+				}
+
+				result, err := search(context.TODO(), core.SearchRequest{
+					Phrase: "linux", Limit: tc.limit, Offset: tc.offset, Sort: tc.sort,
+				})
+				if tc.wantErr != nil {
+					require.ErrorIs(t, err, tc.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				ids := make([]int64, len(result.Comics))
+				for i, comic := range result.Comics {
+					ids[i] = comic.ID
+				}
+				require.Equal(t, tc.ids, ids)
+				require.Equal(t, int64(5), result.TotalHits)
+			})
+		}
+	}
+}
+
+func TestSearchSortRelevanceIsDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	mockWords := core.NewMockWords(ctrl)
+	mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+		fieldComic(1, []string{"linux"}, nil, nil),
+		fieldComic(2, []string{"linux", "linux", "linux"}, nil, nil),
+		fieldComic(3, []string{"linux", "linux"}, nil, nil),
+	}, nil)
+	mockWords.EXPECT().Norm(gomock.Any(), gomock.Any()).DoAndReturn(fakeNorm).AnyTimes()
+
+	service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+
+	byDefault, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10})
+	require.NoError(t, err)
+	byRelevance, err := service.ISearch(context.TODO(), core.SearchRequest{Phrase: "linux", Limit: 10, Sort: core.SortRelevance})
+	require.NoError(t, err)
+	require.Equal(t, byDefault, byRelevance)
+}
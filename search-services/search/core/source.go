@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// indexSource дает индекс, по которому ищется запрос.
+type indexSource interface {
+	lookup(ctx context.Context, query queryNode) (*invertedIndex, error)
+	comic(ctx context.Context, id int64) (Comic, bool, error)
+}
+
+// memorySource - индекс ISearch, построенный заранее по всем комиксам.
+type memorySource struct {
+	index *invertedIndex
+}
+
+func (m *memorySource) lookup(context.Context, queryNode) (*invertedIndex, error) {
+	return m.index, nil
+}
+
+func (m *memorySource) comic(_ context.Context, id int64) (Comic, bool, error) {
+	doc, ok := m.index.docs.get(id)
+	if !ok {
+		return Comic{}, false, nil
+	}
+	return doc.info.Comic, true, nil
+}
+
+// dbSource строит на каждый запрос индекс только по комиксам, в которых есть
+// слова запроса: их находит база по GIN-индексу. Статистика коллекции для
+// ранжирования берется из базы целиком, поэтому оценки совпадают с ISearch.
+type dbSource struct {
+	db DB
+}
+
+func (d *dbSource) lookup(ctx context.Context, query queryNode) (*invertedIndex, error) {
+	// без положительных условий запрос ничего не находит
+	words := keywords(query)
+	if len(words) == 0 {
+		return newInvertedIndex(), nil
+	}
+
+	comicsInfo, err := d.db.FindComicsInfo(ctx, words)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comics info: %w", err)
+	}
+	stats, err := d.db.CorpusStats(ctx, words)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corpus stats: %w", err)
+	}
+	idx := buildIndex(comicsInfo)
+	idx.stats = &stats
+	return idx, nil
+}
+
+func (d *dbSource) comic(ctx context.Context, id int64) (Comic, bool, error) {
+	comicsInfo, err := d.db.GetComicsInfoByIds(ctx, []int64{id})
+	if err != nil {
+		return Comic{}, false, fmt.Errorf("failed to get comic info: %w", err)
+	}
+	if len(comicsInfo) == 0 {
+		return Comic{}, false, nil
+	}
+	return comicsInfo[0].Comic, true, nil
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// rankedPrefixLen - длина префиксов в рунах, для которых порядок подсказок
+// строится заранее: с короткими префиксами начинается большая часть словаря.
+const rankedPrefixLen = 2
+
+// suggester - слова комиксов в исходном написании, отсортированные по алфавиту:
+// слова с общим префиксом идут подряд, и их диапазон находится двоичным поиском.
+// Основы в подсказки не попадают, по ним считается только количество комиксов.
+type suggester struct {
+	terms []Suggestion
+	// слова с коротким префиксом в порядке выдачи, номера в terms
+	ranked map[string][]int32
+}
+
+func newSuggester(vocabulary map[string]int) *suggester {
+	terms := make([]Suggestion, 0, len(vocabulary))
+	for word, df := range vocabulary {
+		terms = append(terms, Suggestion{Text: word, Count: int64(df)})
+	}
+	slices.SortFunc(terms, func(a, b Suggestion) int {
+		return strings.Compare(a.Text, b.Text)
+	})
+
+	order := make([]int32, len(terms))
+	for i := range order {
+		order[i] = int32(i)
+	}
+	// номера в terms идут по алфавиту и разрешают равенство количеств
+	slices.SortFunc(order, func(a, b int32) int {
+		return cmp.Or(cmp.Compare(terms[b].Count, terms[a].Count), cmp.Compare(a, b))
+	})
+	ranked := map[string][]int32{}
+	for _, i := range order {
+		word, end := terms[i].Text, 0
+		for range rankedPrefixLen {
+			if end == len(word) {
+				break
+			}
+			_, size := utf8.DecodeRuneInString(word[end:])
+			end += size
+			ranked[word[:end]] = append(ranked[word[:end]], i)
+		}
+	}
+	return &suggester{terms: terms, ranked: ranked}
+}
+
+// suggest возвращает limit слов с префиксом по убыванию количества комиксов,
+// при равенстве - по алфавиту. Для коротких префиксов порядок готов, а слов
+// с длинным префиксом немного, и они сортируются на месте.
+func (s *suggester) suggest(prefix string, limit int64) []Suggestion {
+	if utf8.RuneCountInString(prefix) <= rankedPrefixLen {
+		ranked := s.ranked[prefix]
+		matches := make([]Suggestion, min(int64(len(ranked)), limit))
+		for i := range matches {
+			matches[i] = s.terms[ranked[i]]
+		}
+		return matches
+	}
+
+	start := sort.Search(len(s.terms), func(i int) bool {
+		return s.terms[i].Text >= prefix
+	})
+	end := start
+	for end < len(s.terms) && strings.HasPrefix(s.terms[end].Text, prefix) {
+		end++
+	}
+
+	matches := make([]Suggestion, end-start)
+	copy(matches, s.terms[start:end])
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Count > matches[j].Count
+	})
+	return matches[:min(int64(len(matches)), limit)]
+}
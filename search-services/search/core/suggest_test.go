@@ -0,0 +1,151 @@
+package core_test
+
+import (
+	"context"
+	"log/slog"
+	"search-service/search/core"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSuggest(t *testing.T) {
+	// в подсказках слова в написании из текста, количество - по основам
+	indexed := []core.ComicInfo{
+		{Comic: core.Comic{ID: 1, Title: "Linux lines", Alt: "kernels"}, Words: []string{"linux", "line", "kernel"}},
+		{Comic: core.Comic{ID: 2, Title: "Linux lion"}, Words: []string{"linux", "lion"}},
+		{Comic: core.Comic{ID: 3, Title: "linux line"}, Words: []string{"linux", "line"}},
+	}
+	testCases := []struct {
+		desc     string
+		prefix   string
+		limit    int64
+		expected []core.Suggestion
+		wantErr  bool
+	}{
+		{
+			desc:   "ranked by document frequency",
+			prefix: "li",
+			limit:  10,
+			expected: []core.Suggestion{
+				{Text: "linux", Count: 3},
+				{Text: "line", Count: 2},
+				{Text: "lines", Count: 2},
+				{Text: "lion", Count: 1},
+			},
+		},
+		{
+			desc:     "limit applied",
+			prefix:   "li",
+			limit:    1,
+			expected: []core.Suggestion{{Text: "linux", Count: 3}},
+		},
+		{
+			desc:     "prefix normalized",
+			prefix:   " KER ",
+			limit:    10,
+			expected: []core.Suggestion{{Text: "kernels", Count: 1}},
+		},
+		{
+			desc:     "whole word is a prefix",
+			prefix:   "line",
+			limit:    10,
+			expected: []core.Suggestion{{Text: "line", Count: 2}, {Text: "lines", Count: 2}},
+		},
+		{
+			desc:     "no completions",
+			prefix:   "xkcd",
+			limit:    10,
+			expected: []core.Suggestion{},
+		},
+		{
+			desc:    "error - empty prefix",
+			prefix:  " ",
+			limit:   10,
+			wantErr: true,
+		},
+		{
+			desc:    "error - zero limit",
+			prefix:  "li",
+			limit:   0,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := core.NewMockDB(ctrl)
+			mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return(indexed, nil)
+
+			mockWords := core.NewMockWords(ctrl)
+			mockWords.EXPECT().TokenizeAll(gomock.Any(), gomock.Any()).DoAndReturn(pluralTokenizeAll).AnyTimes()
+
+			service, err := core.NewService(slog.Default(), mockDB, mockWords, nil, core.Options{})
+			require.NoError(t, err)
+			require.NoError(t, service.UpdateIndex(context.TODO()))
+
+			suggestions, err := service.Suggest(context.TODO(), tc.prefix, tc.limit)
+			if tc.wantErr {
+				require.ErrorIs(t, err, core.ErrBadArguments)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, suggestions)
+		})
+	}
+}
+
+func TestSuggestFollowsIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := core.NewMockDB(ctrl)
+	gomock.InOrder(
+		mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+			{Comic: core.Comic{ID: 1, Title: "Linux"}, Words: []string{"linux"}},
+		}, nil),
+		mockDB.EXPECT().GetAllComicsInfo(gomock.Any()).Return([]core.ComicInfo{
+			{Comic: core.Comic{ID: 1, Title: "Linux"}, Words: []string{"linux"}},
+			{Comic: core.Comic{ID: 2, Title: "Lisp"}, Words: []string{"lisp"}},
+		}, nil),
+	)
+
+	service, err := core.NewService(slog.Default(), mockDB, newIndexingWords(ctrl), nil, core.Options{})
+	require.NoError(t, err)
+
+	suggestions, err := service.Suggest(context.TODO(), "li", 10)
+	require.NoError(t, err)
+	require.Empty(t, suggestions)
+
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+	suggestions, err = service.Suggest(context.TODO(), "li", 10)
+	require.NoError(t, err)
+	require.Equal(t, []core.Suggestion{{Text: "linux", Count: 1}}, suggestions)
+
+	service.ResetIndex()
+	suggestions, err = service.Suggest(context.TODO(), "li", 10)
+	require.NoError(t, err)
+	require.Empty(t, suggestions)
+
+	require.NoError(t, service.UpdateIndex(context.TODO()))
+	suggestions, err = service.Suggest(context.TODO(), "li", 10)
+	require.NoError(t, err)
+	require.Equal(t, []core.Suggestion{{Text: "linux", Count: 1}, {Text: "lisp", Count: 1}}, suggestions)
+}
+
+// pluralTokenizeAll - fakeTokenizeAll, у которого основа - слово без
+// окончания множественного числа, как у стеммера.
+func pluralTokenizeAll(ctx context.Context, texts []string) ([][]core.Token, error) {
+	tokens, err := fakeTokenizeAll(ctx, texts)
+	for _, text := range tokens {
+		for i := range text {
+			text[i].Stem = strings.TrimSuffix(text[i].Stem, "s")
+		}
+	}
+	return tokens, err
+}
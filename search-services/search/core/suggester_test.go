@@ -0,0 +1,43 @@
+package core
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// benchVocabulary - словарь из слов с частотой букв английского текста,
+// количество комиксов у слов убывает по закону Ципфа.
+func benchVocabulary(size, docs int) map[string]int {
+	const letters = "eeeeeeeeeeeettttttttttaaaaaaaaooooooooiiiiiiinnnnnnnsssssshhhhhhrrrrrrddddllllccmmuuwwffggyyppbbvkjxqz"
+	r := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(docs-1))
+	vocabulary := make(map[string]int, size)
+	for len(vocabulary) < size {
+		var word strings.Builder
+		for range 3 + r.IntN(8) {
+			word.WriteByte(letters[r.IntN(len(letters))])
+		}
+		vocabulary[word.String()] = int(docs - int(zipf.Uint64()))
+	}
+	return vocabulary
+}
+
+func BenchmarkSuggest(b *testing.B) {
+	vocabulary := benchVocabulary(30000, 3000)
+
+	b.Run("build", func(b *testing.B) {
+		for b.Loop() {
+			newSuggester(vocabulary)
+		}
+	})
+	s := newSuggester(vocabulary)
+	// набор запроса: каждая буква - новый префикс
+	for _, prefix := range []string{"t", "th", "the", "ther"} {
+		b.Run(prefix, func(b *testing.B) {
+			for b.Loop() {
+				s.suggest(prefix, 10)
+			}
+		})
+	}
+}
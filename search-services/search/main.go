@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -12,6 +13,7 @@ import (
 	"search-service/search/adapters/db"
 	searchgrpc "search-service/search/adapters/grpc"
 	"search-service/search/adapters/scheduler"
+	"search-service/search/adapters/snapshot"
 	"search-service/search/adapters/subscriber"
 	"search-service/search/adapters/words"
 	"search-service/search/config"
@@ -58,18 +60,35 @@ func run(cfg config.Config, log *slog.Logger) error {
 	}
 	defer words.Close()
 
-	// Service
-	searcher, err := core.NewService(log, storage, words)
-	if err != nil {
-		return fmt.Errorf("failed create Search service: %w", err)
+	// Snapshot adapter
+	var snapshots core.SnapshotStore
+	if cfg.Snapshot.Path != "" {
+		snapshots = snapshot.NewFile(log, cfg.Snapshot.Path)
 	}
 
-	// Subscriber adapter
-	subscriber, err := subscriber.NewNatsSubscriber(cfg.Broker.Address, cfg.Broker.Subject, searcher, log)
+	// Service
+	searcher, err := core.NewService(log, storage, words, snapshots, core.Options{
+		PhoneticMinHits:  cfg.Phonetic.MinHits,
+		FuzzyMinHits:     cfg.Fuzzy.MinHits,
+		K1:               cfg.BM25.K1,
+		B:                cfg.BM25.B,
+		RecencyBoost:     cfg.Ranking.RecencyBoost,
+		Ranker:           cfg.Ranking.Ranker,
+		Experiment:       makeExperiment(cfg.Ranking.Experiment),
+		MaxLimit:         cfg.Paging.MaxLimit,
+		HighlightPreTag:  cfg.Highlight.PreTag,
+		HighlightPostTag: cfg.Highlight.PostTag,
+		SnippetSize:      cfg.Highlight.SnippetSize,
+		CacheSize:        cfg.Cache.Size,
+		FieldBoosts: map[string]float64{
+			core.FieldTitle:      cfg.FieldBoosts.Title,
+			core.FieldAlt:        cfg.FieldBoosts.Alt,
+			core.FieldTranscript: cfg.FieldBoosts.Transcript,
+		},
+	})
 	if err != nil {
-		return fmt.Errorf("failed create Nats subscriber: %w", err)
+		return fmt.Errorf("failed create Search service: %w", err)
 	}
-	defer subscriber.Unsubscribe()
 
 	// Searcher scheduler
 	searchSched := scheduler.NewSearcherScheduler(log, searcher, cfg.IndexTTL)
@@ -87,10 +106,25 @@ func run(cfg config.Config, log *slog.Logger) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	if err := searchSched.Start(ctx); err != nil {
+	// со снимком поиск готов сразу, без ожидания сборки по базе
+	if loadSnapshot(ctx, log, searcher) {
+		searchSched.StartWarm(ctx)
+	} else if err := searchSched.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start searcher scheduler: %w", err)
 	}
 
+	// Subscriber adapter: изменения применяются к уже опубликованному индексу,
+	// иначе загрузка снимка затерла бы их до следующей сборки
+	subscriber, err := subscriber.NewNatsSubscriber(cfg.Broker.Address, cfg.Broker.Subject, searcher, log)
+	if err != nil {
+		return fmt.Errorf("failed create Nats subscriber: %w", err)
+	}
+	defer subscriber.Unsubscribe()
+
+	if snapshots != nil {
+		scheduler.NewSnapshotScheduler(log, searcher, cfg.Snapshot.Interval).Start(ctx)
+	}
+
 	go func() {
 		<-ctx.Done()
 		log.Debug("shutting down Search service...")
@@ -115,9 +149,28 @@ func run(cfg config.Config, log *slog.Logger) error {
 		return fmt.Errorf("failed to serve: %w", err)
 	}
 
+	// свежий снимок ускорит следующий запуск
+	if err := searcher.SaveSnapshot(context.Background()); err != nil {
+		log.Warn("failed to save index snapshot on shutdown", "error", err)
+	}
 	return nil
 }
 
+// loadSnapshot загружает индекс из снимка. Поврежденный снимок не мешает
+// запуску: индекс собирается по базе целиком.
+func loadSnapshot(ctx context.Context, log *slog.Logger, searcher *core.Service) bool {
+	err := searcher.LoadSnapshot(ctx)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, core.ErrSnapshotNotFound):
+		log.Info("no index snapshot, building index from db")
+	default:
+		log.Warn("failed to load index snapshot, building index from db", "error", err)
+	}
+	return false
+}
+
 func mustMakeLogger(logLevel string) *slog.Logger {
 	var level slog.Level
 	switch logLevel {
@@ -133,3 +186,11 @@ func mustMakeLogger(logLevel string) *slog.Logger {
 	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{AddSource: true, Level: level})
 	return slog.New(handler)
 }
+
+func makeExperiment(arms []config.ExperimentArm) []core.ExperimentArm {
+	experiment := make([]core.ExperimentArm, len(arms))
+	for i, arm := range arms {
+		experiment[i] = core.ExperimentArm{Ranker: arm.Ranker, Weight: arm.Weight}
+	}
+	return experiment
+}
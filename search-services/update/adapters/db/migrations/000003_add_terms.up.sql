@@ -0,0 +1 @@
+ALTER TABLE comics ADD COLUMN IF NOT EXISTS terms TEXT[];
@@ -0,0 +1,4 @@
+ALTER TABLE comics
+    DROP COLUMN IF EXISTS title_terms,
+    DROP COLUMN IF EXISTS alt_terms,
+    DROP COLUMN IF EXISTS transcript_terms;
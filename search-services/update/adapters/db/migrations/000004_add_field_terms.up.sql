@@ -0,0 +1,4 @@
+ALTER TABLE comics
+    ADD COLUMN IF NOT EXISTS title_terms TEXT[],
+    ADD COLUMN IF NOT EXISTS alt_terms TEXT[],
+    ADD COLUMN IF NOT EXISTS transcript_terms TEXT[];
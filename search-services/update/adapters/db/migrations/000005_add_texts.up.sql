@@ -0,0 +1,4 @@
+ALTER TABLE comics
+    ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS alt TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS transcript TEXT NOT NULL DEFAULT '';
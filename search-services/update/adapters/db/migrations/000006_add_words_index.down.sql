@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS comics_words_idx;
+
+ALTER TABLE comics_stats
+    DROP COLUMN IF EXISTS terms_total;
@@ -0,0 +1,10 @@
+CREATE INDEX IF NOT EXISTS comics_words_idx ON comics USING GIN (words);
+
+ALTER TABLE comics_stats
+    ADD COLUMN IF NOT EXISTS terms_total BIGINT NOT NULL DEFAULT 0;
+
+UPDATE comics_stats
+SET terms_total = (
+    SELECT COALESCE(SUM(COALESCE(NULLIF(cardinality(terms), 0), cardinality(words), 0)), 0)
+    FROM comics
+);
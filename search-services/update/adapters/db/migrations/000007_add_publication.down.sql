@@ -0,0 +1,4 @@
+ALTER TABLE comics
+    DROP COLUMN IF EXISTS published,
+    DROP COLUMN IF EXISTS link,
+    DROP COLUMN IF EXISTS news;
@@ -0,0 +1,5 @@
+-- дата публикации неизвестна у комиксов, загруженных раньше, их дозаполняет update (см. 000008)
+ALTER TABLE comics
+    ADD COLUMN IF NOT EXISTS published DATE,
+    ADD COLUMN IF NOT EXISTS link TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS news TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+-- у комиксов, загруженных до 000007, дата публикации не запрашивалась:
+-- update дозапрашивает ее у xkcd и отмечает комикс проверенным.
+-- Новые комиксы сохраняются уже с датой, поэтому по умолчанию проверены
+ALTER TABLE comics
+    ADD COLUMN IF NOT EXISTS publication_checked BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE comics SET publication_checked = true WHERE published IS NOT NULL;
+
+ALTER TABLE comics
+    ALTER COLUMN publication_checked SET DEFAULT true;
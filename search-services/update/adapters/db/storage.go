@@ -15,13 +15,16 @@ import (
 const (
 	// insert
 	insertComic = `
-		INSERT INTO comics (id, url, words) 
-		VALUES (:id, :url, :words)
+		INSERT INTO comics (id, url, words, phonetics, terms, title_terms, alt_terms, transcript_terms, title, alt, transcript,
+			published, link, news)
+		VALUES (:id, :url, :words, :phonetics, :terms, :title_terms, :alt_terms, :transcript_terms, :title, :alt, :transcript,
+			:published, :link, :news)
 	`
 
 	// select
-	getIDs         = `SELECT id FROM comics`
-	getComicsStats = `SELECT * FROM comics_stats`
+	getIDs                  = `SELECT id FROM comics`
+	getPublicationUnchecked = `SELECT id FROM comics WHERE NOT publication_checked ORDER BY id`
+	getComicsStats          = `SELECT comics_fetched, words_total, words_unique FROM comics_stats`
 
 	// update
 	updateStats = `
@@ -32,7 +35,9 @@ const (
 			(
 				SELECT COUNT(DISTINCT word) 
 				FROM (SELECT unnest(words) as word FROM comics) t
-			) as words_unique
+			) as words_unique,
+			-- длина комиксов в терминах для ранжирования в search
+			COALESCE(SUM(COALESCE(NULLIF(cardinality(terms), 0), cardinality(words), 0)), 0) as terms_total
 			FROM comics
 		)
 
@@ -40,15 +45,22 @@ const (
 		SET 
 		comics_fetched = stats.comics_fetched,
 		words_total = stats.words_total,
-		words_unique = stats.words_unique
+		words_unique = stats.words_unique,
+		terms_total = stats.terms_total
 		FROM stats
 	`
+	setPublication = `
+		UPDATE comics
+		SET published = :published, link = :link, news = :news, publication_checked = true
+		WHERE id = :id
+	`
 	resetComicsStats = `
         UPDATE comics_stats 
         SET 
         comics_fetched = 0,
         words_total = 0,
-        words_unique = 0
+        words_unique = 0,
+        terms_total = 0
     `
 
 	// truncate
@@ -120,6 +132,43 @@ func (db *DB) IDs(ctx context.Context) ([]int64, error) {
 	return IDs, nil
 }
 
+func (db *DB) PublicationUnchecked(ctx context.Context) ([]int64, error) {
+	var IDs []int64
+	err := db.conn.SelectContext(ctx, &IDs, getPublicationUnchecked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select comics without publication from comics table: %w", err)
+	}
+	return IDs, nil
+}
+
+func (db *DB) SetPublication(ctx context.Context, comic ...core.Comic) error {
+	tx, err := db.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			db.log.Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	stmt, err := tx.PrepareNamedContext(ctx, setPublication)
+	if err != nil {
+		return fmt.Errorf("failed to prepare publication update: %w", err)
+	}
+	defer stmt.Close()
+	for _, c := range comic {
+		if _, err = stmt.ExecContext(ctx, c); err != nil {
+			return fmt.Errorf("failed to update publication of comic %d: %w", c.ID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (db *DB) Drop(ctx context.Context) error {
 	tx, err := db.conn.BeginTxx(ctx, nil)
 	if err != nil {
@@ -314,13 +314,53 @@ func TestDrop(t *testing.T) {
 	}
 }
 
+func TestSetPublication(t *testing.T) {
+	defer teardown(t, "comics")
+
+	// комиксы, загруженные до появления даты публикации
+	_, err := conn.Exec(`
+		INSERT INTO comics (id, url, words, publication_checked) VALUES
+		(1, 'http://example.com/1', ARRAY['test'], false),
+		(2, 'http://example.com/2', ARRAY['another'], false)
+	`)
+	require.NoError(t, err)
+	require.NoError(t, testDB.Add(context.TODO(), core.Comic{ID: 3, URL: "http://example.com/3"}))
+
+	ids, err := testDB.PublicationUnchecked(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+
+	published := time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC)
+	err = testDB.SetPublication(context.TODO(),
+		core.Comic{ID: 1, Published: &published, Link: "http://xkcd.com/1/large/"},
+		core.Comic{ID: 2, News: "news"},
+	)
+	require.NoError(t, err)
+
+	ids, err = testDB.PublicationUnchecked(context.TODO())
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	var comic struct {
+		Published *time.Time     `db:"published"`
+		Link      string         `db:"link"`
+		Words     pq.StringArray `db:"words"`
+	}
+	require.NoError(t, conn.Get(&comic, "SELECT published, link, words FROM comics WHERE id = 1"))
+	require.NotNil(t, comic.Published)
+	require.True(t, published.Equal(*comic.Published))
+	require.Equal(t, "http://xkcd.com/1/large/", comic.Link)
+	// ключевые слова не меняются
+	require.Equal(t, pq.StringArray{"test"}, comic.Words)
+}
+
 func teardown(t *testing.T, table string) {
 	switch table {
 	case "comics":
 		_, err := conn.Exec("TRUNCATE comics")
 		require.NoError(t, err)
 	case "comics_stats":
-		_, err := conn.Exec("UPDATE comics_stats SET comics_fetched = 0, words_total = 0, words_unique = 0")
+		_, err := conn.Exec("UPDATE comics_stats SET comics_fetched = 0, words_total = 0, words_unique = 0, terms_total = 0")
 		require.NoError(t, err)
 	}
 }
@@ -1,6 +1,7 @@
 package publisher
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"search-service/update/core"
@@ -48,13 +49,30 @@ func (np *NatsPublisher) Close() {
 	np.conn.Close()
 }
 
-func (np *NatsPublisher) Publish(event core.EventType) error {
-	if err := np.conn.Publish(np.subj, []byte(event)); err != nil {
+// message - событие в брокере, его читает подписчик в search.
+type message struct {
+	Type    core.EventType `json:"type"`
+	Added   []int64        `json:"added,omitempty"`
+	Updated []int64        `json:"updated,omitempty"`
+	Deleted []int64        `json:"deleted,omitempty"`
+}
+
+func (np *NatsPublisher) Publish(event core.Event) error {
+	data, err := json.Marshal(message{
+		Type:    event.Type,
+		Added:   event.Added,
+		Updated: event.Updated,
+		Deleted: event.Deleted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := np.conn.Publish(np.subj, data); err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
 	if err := np.conn.Flush(); err != nil {
 		return fmt.Errorf("failed to flush: %w", err)
 	}
-	np.log.Debug("message published successfully", "subject", np.subj, "event", event)
+	np.log.Debug("message published successfully", "subject", np.subj, "event", event.Type)
 	return nil
 }
@@ -60,17 +60,25 @@ func (c *Client) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (c *Client) Norm(ctx context.Context, phrase string) ([]string, error) {
-	reply, err := c.client.Norm(ctx, &wordspb.WordsRequest{Phrase: phrase})
+func (c *Client) Norm(ctx context.Context, phrase string) (core.Keywords, error) {
+	reply, err := c.client.Norm(ctx, &wordspb.WordsRequest{
+		Phrase:   phrase,
+		Phonetic: true,
+		Terms:    true,
+	})
 	if err != nil {
 		switch status.Code(err) {
 		case codes.Unavailable:
-			return nil, core.ErrServiceUnavailable
+			return core.Keywords{}, core.ErrServiceUnavailable
 		case codes.ResourceExhausted:
-			return nil, core.ErrBadArguments
+			return core.Keywords{}, core.ErrBadArguments
 		default:
-			return nil, err
+			return core.Keywords{}, err
 		}
 	}
-	return reply.GetWords(), nil
+	return core.Keywords{
+		Words:     reply.GetWords(),
+		Phonetics: reply.GetPhonetics(),
+		Terms:     reply.GetTerms(),
+	}, nil
 }
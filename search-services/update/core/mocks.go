@@ -169,6 +169,40 @@ func (mr *MockDBMockRecorder) IDs(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDs", reflect.TypeOf((*MockDB)(nil).IDs), ctx)
 }
 
+// PublicationUnchecked mocks base method.
+func (m *MockDB) PublicationUnchecked(ctx context.Context) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicationUnchecked", ctx)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublicationUnchecked indicates an expected call of PublicationUnchecked.
+func (mr *MockDBMockRecorder) PublicationUnchecked(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicationUnchecked", reflect.TypeOf((*MockDB)(nil).PublicationUnchecked), ctx)
+}
+
+// SetPublication mocks base method.
+func (m *MockDB) SetPublication(ctx context.Context, comic ...Comic) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range comic {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetPublication", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPublication indicates an expected call of SetPublication.
+func (mr *MockDBMockRecorder) SetPublication(ctx any, comic ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, comic...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPublication", reflect.TypeOf((*MockDB)(nil).SetPublication), varargs...)
+}
+
 // Stats mocks base method.
 func (m *MockDB) Stats(ctx context.Context) (DBStats, error) {
 	m.ctrl.T.Helper()
@@ -263,10 +297,10 @@ func (m *MockWords) EXPECT() *MockWordsMockRecorder {
 }
 
 // Norm mocks base method.
-func (m *MockWords) Norm(ctx context.Context, phrase string) ([]string, error) {
+func (m *MockWords) Norm(ctx context.Context, phrase string) (Keywords, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Norm", ctx, phrase)
-	ret0, _ := ret[0].([]string)
+	ret0, _ := ret[0].(Keywords)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -302,7 +336,7 @@ func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
 }
 
 // Publish mocks base method.
-func (m *MockPublisher) Publish(event EventType) error {
+func (m *MockPublisher) Publish(event Event) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Publish", event)
 	ret0, _ := ret[0].(error)
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type ServiceStatus string
 
 const (
@@ -14,6 +16,15 @@ const (
 	EventReset  EventType = "reset"
 )
 
+// Event - изменение комиксов в базе для подписчиков: по идентификаторам
+// поиск обновляет индекс только по этим комиксам.
+type Event struct {
+	Type    EventType
+	Added   []int64
+	Updated []int64
+	Deleted []int64
+}
+
 type DBStats struct {
 	WordsTotal    int64 `db:"words_total"`
 	WordsUnique   int64 `db:"words_unique"`
@@ -26,9 +37,35 @@ type ServiceStats struct {
 }
 
 type Comic struct {
-	ID    int64    `db:"id"`
-	URL   string   `db:"url"`
-	Words []string `db:"words"`
+	ID        int64    `db:"id"`
+	URL       string   `db:"url"`
+	Words     []string `db:"words"`
+	Phonetics []string `db:"phonetics"`
+	Terms     []string `db:"terms"`
+
+	// основы отдельных полей, по ним поиск ограничивает запрос полем
+	TitleTerms      []string `db:"title_terms"`
+	AltTerms        []string `db:"alt_terms"`
+	TranscriptTerms []string `db:"transcript_terms"`
+
+	// исходный текст полей для подсветки совпадений в выдаче
+	Title      string `db:"title"`
+	Alt        string `db:"alt"`
+	Transcript string `db:"transcript"`
+
+	// дата публикации, nil - неизвестна
+	Published *time.Time `db:"published"`
+	// ссылка есть у больших и интерактивных комиксов, новость - у анонсов
+	Link string `db:"link"`
+	News string `db:"news"`
+}
+
+// Keywords - нормализованные слова описания комикса и их фонетические коды.
+// Terms - основы в порядке следования с повторами для частот терминов.
+type Keywords struct {
+	Words     []string
+	Phonetics []string
+	Terms     []string
 }
 
 type XKCDInfo struct {
@@ -38,4 +75,9 @@ type XKCDInfo struct {
 	Title      string `json:"title"`
 	Alt        string `json:"alt"`
 	Transcript string `json:"transcript"`
+	Year       string `json:"year"`
+	Month      string `json:"month"`
+	Day        string `json:"day"`
+	Link       string `json:"link"`
+	News       string `json:"news"`
 }
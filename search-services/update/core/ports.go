@@ -18,6 +18,9 @@ type DB interface {
 	Stats(ctx context.Context) (DBStats, error)
 	Drop(ctx context.Context) error
 	IDs(ctx context.Context) ([]int64, error)
+	// комиксы, дата публикации которых еще не запрашивалась
+	PublicationUnchecked(ctx context.Context) ([]int64, error)
+	SetPublication(ctx context.Context, comic ...Comic) error
 }
 
 type XKCD interface {
@@ -26,9 +29,9 @@ type XKCD interface {
 }
 
 type Words interface {
-	Norm(ctx context.Context, phrase string) ([]string, error)
+	Norm(ctx context.Context, phrase string) (Keywords, error)
 }
 
 type Publisher interface {
-	Publish(event EventType) error
+	Publish(event Event) error
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -123,25 +125,109 @@ func (s *Service) Update(ctx context.Context) error {
 		}
 	}
 
+	var added []int64
 	if len(comics) == 0 {
 		s.log.Debug("no new comics to add")
-		return nil
+	} else {
+		// batch-запись извлеченных комиксов
+		if err := s.db.Add(ctx, comics...); err != nil {
+			s.log.Error("failed to add comics", "error", err)
+			return fmt.Errorf("failed to add comics: %w", err)
+		}
+		s.log.Debug("added new comics", "counter", len(comics))
+
+		added = make([]int64, len(comics))
+		for i, comic := range comics {
+			added[i] = comic.ID
+		}
+		slices.Sort(added)
 	}
 
-	// batch-запись извлеченных комиксов
-	if err := s.db.Add(ctx, comics...); err != nil {
-		s.log.Error("failed to add comics", "error", err)
-		return fmt.Errorf("failed to add comics: %w", err)
+	updated := s.backfillPublication(ctx)
+	if len(added) == 0 && len(updated) == 0 {
+		return nil
 	}
-	s.log.Debug("added new comics", "counter", len(comics))
 
 	// отправка сообщения через брокер-Nats после успешного обновления
-	if err := s.publisher.Publish(EventUpdate); err != nil {
+	if err := s.publisher.Publish(Event{Type: EventUpdate, Added: added, Updated: updated}); err != nil {
 		s.log.Error("failed to publish", "error", err)
 	}
 	return nil
 }
 
+// backfillPublication дозапрашивает дату публикации, ссылку и новость у комиксов,
+// загруженных до их появления в базе, и возвращает обновленные комиксы.
+// Ошибки не прерывают обновление: непроверенные комиксы запрашиваются
+// при следующем обновлении.
+func (s *Service) backfillPublication(ctx context.Context) []int64 {
+	IDs, err := s.db.PublicationUnchecked(ctx)
+	if err != nil {
+		s.log.Error("failed to get comics without publication", "error", err)
+		return nil
+	}
+	if len(IDs) == 0 {
+		return nil
+	}
+
+	jobs := make(chan int64, len(IDs))
+	results := make(chan *Comic, len(IDs))
+	for w := 1; w <= s.concurrency; w++ {
+		go s.publicationWorker(ctx, jobs, results)
+	}
+	for _, id := range IDs {
+		jobs <- id
+	}
+	close(jobs)
+
+	var comics []Comic
+	for range IDs {
+		if comic := <-results; comic != nil {
+			comics = append(comics, *comic)
+		}
+	}
+	if len(comics) == 0 {
+		return nil
+	}
+
+	if err := s.db.SetPublication(ctx, comics...); err != nil {
+		s.log.Error("failed to set publication", "error", err)
+		return nil
+	}
+	s.log.Debug("backfilled publication", "counter", len(comics))
+
+	updated := make([]int64, len(comics))
+	for i, comic := range comics {
+		updated[i] = comic.ID
+	}
+	slices.Sort(updated)
+	return updated
+}
+
+func (s *Service) publicationWorker(ctx context.Context, jobs <-chan int64, results chan<- *Comic) {
+	for id := range jobs {
+		// у 404 и пропавших из xkcd комиксов даты нет, они отмечаются проверенными
+		var info XKCDInfo
+		if id != 404 {
+			var err error
+			info, err = s.xkcd.Get(ctx, id)
+			switch {
+			case errors.Is(err, ErrNotFound):
+				s.log.Debug("comic not found", "comic_id", id)
+			case err != nil:
+				s.log.Error("failed to get XKCDInfo", "comic_id", id, "error", err)
+				results <- nil
+				continue
+			}
+		}
+		results <- &Comic{
+			ID:        id,
+			Published: publishedAt(info),
+			Link:      info.Link,
+			News:      info.News,
+		}
+	}
+}
+
 func (s *Service) worker(ctx context.Context, jobs <-chan int64, results chan<- *Comic) {
 	for id := range jobs {
 		// special case
@@ -164,27 +250,70 @@ func (s *Service) worker(ctx context.Context, jobs <-chan int64, results chan<-
 			continue
 		}
 
-		keywords, err := s.words.Norm(ctx, makeDescription(info))
+		comic, err := s.makeComic(ctx, info)
 		if err != nil {
 			s.log.Error("failed to normalize comic description", "comic_id", id, "error", err)
 			results <- nil
 			continue
 		}
-		results <- &Comic{
-			ID:    info.ID,
-			URL:   info.URL,
-			Words: keywords,
+		results <- comic
+	}
+}
+
+// makeComic нормализует поля комикса по отдельности и объединяет их
+// в ключевые слова всего описания в прежнем порядке: заголовок, транскрипт, подпись.
+func (s *Service) makeComic(ctx context.Context, info XKCDInfo) (*Comic, error) {
+	// safe_title почти всегда совпадает с title: по обоим частота слов
+	// заголовка удвоилась бы
+	title := info.Title
+	if strings.TrimSpace(title) == "" {
+		title = info.SafeTitle
+	}
+	comic := &Comic{
+		ID:         info.ID,
+		URL:        info.URL,
+		Title:      title,
+		Alt:        info.Alt,
+		Transcript: info.Transcript,
+		Published:  publishedAt(info),
+		Link:       info.Link,
+		News:       info.News,
+	}
+	fields := []struct {
+		text  string
+		terms *[]string
+	}{
+		{text: title, terms: &comic.TitleTerms},
+		{text: info.Transcript, terms: &comic.TranscriptTerms},
+		{text: info.Alt, terms: &comic.AltTerms},
+	}
+
+	seenWords := map[string]bool{}
+	seenPhonetics := map[string]bool{}
+	for _, field := range fields {
+		if strings.TrimSpace(field.text) == "" {
+			continue
+		}
+		keywords, err := s.words.Norm(ctx, field.text)
+		if err != nil {
+			return nil, err
 		}
+		*field.terms = keywords.Terms
+		comic.Terms = append(comic.Terms, keywords.Terms...)
+		comic.Words = appendUnique(comic.Words, keywords.Words, seenWords)
+		comic.Phonetics = appendUnique(comic.Phonetics, keywords.Phonetics, seenPhonetics)
 	}
+	return comic, nil
 }
 
-func makeDescription(info XKCDInfo) string {
-	return strings.Join([]string{
-		info.SafeTitle,
-		info.Title,
-		info.Transcript,
-		info.Alt,
-	}, " ")
+func appendUnique(dst, src []string, seen map[string]bool) []string {
+	for _, s := range src {
+		if !seen[s] {
+			seen[s] = true
+			dst = append(dst, s)
+		}
+	}
+	return dst
 }
 
 func (s *Service) Drop(ctx context.Context) error {
@@ -199,8 +328,24 @@ func (s *Service) Drop(ctx context.Context) error {
 		return fmt.Errorf("failed to drop db entries: %w", err)
 	}
 	// отправка сообщения через брокер-Nats после успешного "обнулениия" базы
-	if err := s.publisher.Publish(EventReset); err != nil {
+	if err := s.publisher.Publish(Event{Type: EventReset}); err != nil {
 		s.log.Error("failed to publish", "error", err)
 	}
 	return nil
 }
+
+// publishedAt собирает дату публикации из полей xkcd, nil - дата не указана или неверна.
+func publishedAt(info XKCDInfo) *time.Time {
+	year, errYear := strconv.Atoi(info.Year)
+	month, errMonth := strconv.Atoi(info.Month)
+	day, errDay := strconv.Atoi(info.Day)
+	if errYear != nil || errMonth != nil || errDay != nil {
+		return nil
+	}
+	published := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// time.Date нормализует 31 февраля в март
+	if published.Month() != time.Month(month) || published.Day() != day {
+		return nil
+	}
+	return &published
+}
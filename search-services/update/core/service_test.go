@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"search-service/update/core"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -14,6 +15,7 @@ import (
 const concurrency = 10
 
 func TestUpdate(t *testing.T) {
+	published := time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC)
 	testCases := []struct {
 		desc    string
 		prepare func(*core.MockDB, *core.MockXKCD, *core.MockWords, *core.MockPublisher)
@@ -24,6 +26,7 @@ func TestUpdate(t *testing.T) {
 			prepare: func(db *core.MockDB, xkcd *core.MockXKCD, words *core.MockWords, publisher *core.MockPublisher) {
 				db.EXPECT().IDs(gomock.Any()).Return([]int64{1, 2, 3}, nil)
 				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(3), nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return(nil, nil)
 				// не ожидаем вызовов Get, Norm, Add и Publish, т.к. все комиксы уже есть
 			},
 			wantErr: false,
@@ -37,13 +40,80 @@ func TestUpdate(t *testing.T) {
 				// обрабатываем только новые комиксы (3 и 4)
 				xkcd.EXPECT().Get(gomock.Any(), int64(3)).Return(core.XKCDInfo{ID: 3, Title: "New"}, nil)
 				xkcd.EXPECT().Get(gomock.Any(), int64(4)).Return(core.XKCDInfo{ID: 4, Title: "Newer"}, nil)
-				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return([]string{"new", "comic"}, nil).Times(2)
+				keywords := core.Keywords{
+					Words:     []string{"new", "comic"},
+					Phonetics: []string{"NK", "KMK"},
+					Terms:     []string{"new", "comic", "comic"},
+				}
+				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return(keywords, nil).Times(2)
 				db.EXPECT().Add(gomock.Any(), []core.Comic{
-					{ID: int64(3), Words: []string{"new", "comic"}},
-					{ID: int64(4), Words: []string{"new", "comic"}},
+					{ID: int64(3), Words: keywords.Words, Phonetics: keywords.Phonetics, Terms: keywords.Terms, TitleTerms: keywords.Terms, Title: "New"},
+					{ID: int64(4), Words: keywords.Words, Phonetics: keywords.Phonetics, Terms: keywords.Terms, TitleTerms: keywords.Terms, Title: "Newer"},
 				}).
 					Return(nil)
-				publisher.EXPECT().Publish(core.EventUpdate).Return(nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return(nil, nil)
+				publisher.EXPECT().Publish(core.Event{Type: core.EventUpdate, Added: []int64{3, 4}}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			desc: "success - fields normalized separately",
+			prepare: func(db *core.MockDB, xkcd *core.MockXKCD, words *core.MockWords, publisher *core.MockPublisher) {
+				db.EXPECT().IDs(gomock.Any()).Return([]int64{}, nil)
+				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(1), nil)
+				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{
+					ID:         1,
+					URL:        "url",
+					SafeTitle:  "Barrel",
+					Title:      "Barrel",
+					Transcript: "A boy sits in a barrel",
+					Alt:        "Don't we all",
+					Year:       "2006",
+					Month:      "1",
+					Day:        "1",
+					Link:       "http://xkcd.com/1/large/",
+				}, nil)
+				// одинаковые title и safe_title нормализуются один раз
+				words.EXPECT().Norm(gomock.Any(), "Barrel").Return(core.Keywords{
+					Words: []string{"barrel"}, Phonetics: []string{"PRL"}, Terms: []string{"barrel"},
+				}, nil)
+				words.EXPECT().Norm(gomock.Any(), "A boy sits in a barrel").Return(core.Keywords{
+					Words: []string{"boy", "sit", "barrel"}, Phonetics: []string{"P", "ST", "PRL"}, Terms: []string{"boy", "sit", "barrel"},
+				}, nil)
+				words.EXPECT().Norm(gomock.Any(), "Don't we all").Return(core.Keywords{}, nil)
+				db.EXPECT().Add(gomock.Any(), []core.Comic{{
+					ID:              1,
+					URL:             "url",
+					Words:           []string{"barrel", "boy", "sit"},
+					Phonetics:       []string{"PRL", "P", "ST"},
+					Terms:           []string{"barrel", "boy", "sit", "barrel"},
+					TitleTerms:      []string{"barrel"},
+					TranscriptTerms: []string{"boy", "sit", "barrel"},
+					Title:           "Barrel",
+					Alt:             "Don't we all",
+					Transcript:      "A boy sits in a barrel",
+					Published:       &published,
+					Link:            "http://xkcd.com/1/large/",
+				}}).Return(nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return(nil, nil)
+				publisher.EXPECT().Publish(core.Event{Type: core.EventUpdate, Added: []int64{1}}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			desc: "success - safe title used without title",
+			prepare: func(db *core.MockDB, xkcd *core.MockXKCD, words *core.MockWords, pub *core.MockPublisher) {
+				db.EXPECT().IDs(gomock.Any()).Return([]int64{}, nil)
+				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(1), nil)
+				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{ID: 1, SafeTitle: "Barrel"}, nil)
+				words.EXPECT().Norm(gomock.Any(), "Barrel").Return(core.Keywords{
+					Words: []string{"barrel"}, Terms: []string{"barrel"},
+				}, nil)
+				db.EXPECT().Add(gomock.Any(), []core.Comic{{
+					ID: 1, Words: []string{"barrel"}, Terms: []string{"barrel"}, TitleTerms: []string{"barrel"}, Title: "Barrel",
+				}}).Return(nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return(nil, nil)
+				pub.EXPECT().Publish(core.Event{Type: core.EventUpdate, Added: []int64{1}}).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -67,12 +137,12 @@ func TestUpdate(t *testing.T) {
 			prepare: func(db *core.MockDB, xkcd *core.MockXKCD, words *core.MockWords, pub *core.MockPublisher) {
 				db.EXPECT().IDs(gomock.Any()).Return([]int64{}, nil)
 				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(2), nil)
-				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{ID: 1}, nil)
-				xkcd.EXPECT().Get(gomock.Any(), int64(2)).Return(core.XKCDInfo{ID: 2}, nil)
-				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return([]string{"test"}, nil).Times(2)
+				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{ID: 1, Title: "Test"}, nil)
+				xkcd.EXPECT().Get(gomock.Any(), int64(2)).Return(core.XKCDInfo{ID: 2, Title: "Test"}, nil)
+				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return(core.Keywords{Words: []string{"test"}}, nil).Times(2)
 				db.EXPECT().Add(gomock.Any(), []core.Comic{
-					{ID: int64(1), Words: []string{"test"}},
-					{ID: int64(2), Words: []string{"test"}},
+					{ID: int64(1), Words: []string{"test"}, Title: "Test"},
+					{ID: int64(2), Words: []string{"test"}, Title: "Test"},
 				}).Return(errors.New("add error"))
 			},
 			wantErr: true,
@@ -82,10 +152,11 @@ func TestUpdate(t *testing.T) {
 			prepare: func(db *core.MockDB, xkcd *core.MockXKCD, words *core.MockWords, pub *core.MockPublisher) {
 				db.EXPECT().IDs(gomock.Any()).Return([]int64{}, nil)
 				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(1), nil)
-				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{ID: 1}, nil)
-				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return([]string{"test"}, nil)
-				db.EXPECT().Add(gomock.Any(), []core.Comic{{ID: int64(1), Words: []string{"test"}}}).Return(nil)
-				pub.EXPECT().Publish(core.EventUpdate).Return(errors.New("publish error"))
+				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{ID: 1, Title: "Test"}, nil)
+				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return(core.Keywords{Words: []string{"test"}}, nil)
+				db.EXPECT().Add(gomock.Any(), []core.Comic{{ID: int64(1), Words: []string{"test"}, Title: "Test"}}).Return(nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return(nil, nil)
+				pub.EXPECT().Publish(core.Event{Type: core.EventUpdate, Added: []int64{1}}).Return(errors.New("publish error"))
 			},
 			wantErr: false,
 		},
@@ -96,12 +167,52 @@ func TestUpdate(t *testing.T) {
 				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(2), nil)
 				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{ID: 1, Title: "First"}, nil)
 				xkcd.EXPECT().Get(gomock.Any(), int64(2)).Return(core.XKCDInfo{ID: 2, Title: "Second"}, nil)
-				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return([]string{"first"}, nil)
-				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return(nil, errors.New("normalization error"))
+				words.EXPECT().Norm(gomock.Any(), "First").Return(core.Keywords{Words: []string{"first"}}, nil)
+				words.EXPECT().Norm(gomock.Any(), "Second").Return(core.Keywords{}, errors.New("normalization error"))
 
 				// Добавляется только 1 комикс (второй пропущен из-за ошибки)
-				db.EXPECT().Add(gomock.Any(), []core.Comic{{ID: int64(1), Words: []string{"first"}}}).Return(nil)
-				pub.EXPECT().Publish(core.EventUpdate).Return(nil)
+				db.EXPECT().Add(gomock.Any(), []core.Comic{{ID: int64(1), Words: []string{"first"}, Title: "First"}}).Return(nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return(nil, nil)
+				pub.EXPECT().Publish(core.Event{Type: core.EventUpdate, Added: []int64{1}}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			desc: "success - publication backfilled for old comics",
+			prepare: func(db *core.MockDB, xkcd *core.MockXKCD, words *core.MockWords, pub *core.MockPublisher) {
+				db.EXPECT().IDs(gomock.Any()).Return([]int64{1, 2, 404}, nil)
+				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(2), nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return([]int64{1, 2, 404}, nil)
+				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{
+					ID: 1, Year: "2006", Month: "1", Day: "1", Link: "http://xkcd.com/1/large/",
+				}, nil)
+				// комикс пропал из xkcd - даты нет, но повторно не запрашивается
+				xkcd.EXPECT().Get(gomock.Any(), int64(2)).Return(core.XKCDInfo{}, core.ErrNotFound)
+				// Norm не вызывается: описание комиксов не меняется
+				db.EXPECT().SetPublication(gomock.Any(), gomock.InAnyOrder([]core.Comic{
+					{ID: 1, Published: &published, Link: "http://xkcd.com/1/large/"},
+					{ID: 2},
+					{ID: 404},
+				})).Return(nil)
+				pub.EXPECT().Publish(core.Event{Type: core.EventUpdate, Updated: []int64{1, 2, 404}}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			desc: "success - failed backfill retried on next update",
+			prepare: func(db *core.MockDB, xkcd *core.MockXKCD, words *core.MockWords, pub *core.MockPublisher) {
+				db.EXPECT().IDs(gomock.Any()).Return([]int64{1, 2}, nil)
+				xkcd.EXPECT().LastID(gomock.Any()).Return(int64(3), nil)
+				xkcd.EXPECT().Get(gomock.Any(), int64(3)).Return(core.XKCDInfo{ID: 3, Title: "New"}, nil)
+				words.EXPECT().Norm(gomock.Any(), gomock.Any()).Return(core.Keywords{Words: []string{"new"}}, nil)
+				db.EXPECT().Add(gomock.Any(), []core.Comic{{ID: 3, Words: []string{"new"}, Title: "New"}}).Return(nil)
+				db.EXPECT().PublicationUnchecked(gomock.Any()).Return([]int64{1, 2}, nil)
+				// комикс 1 не получен и остается непроверенным
+				xkcd.EXPECT().Get(gomock.Any(), int64(1)).Return(core.XKCDInfo{}, errors.New("xkcd error"))
+				xkcd.EXPECT().Get(gomock.Any(), int64(2)).Return(core.XKCDInfo{ID: 2, News: "news"}, nil)
+				db.EXPECT().SetPublication(gomock.Any(), []core.Comic{{ID: 2, News: "news"}}).Return(errors.New("db error"))
+				// добавленные комиксы публикуются, несмотря на ошибку дозаполнения
+				pub.EXPECT().Publish(core.Event{Type: core.EventUpdate, Added: []int64{3}}).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -288,7 +399,7 @@ func TestDrop(t *testing.T) {
 			desc: "success - drops database and publishes event",
 			prepare: func(db *core.MockDB, pub *core.MockPublisher) {
 				db.EXPECT().Drop(gomock.Any()).Return(nil)
-				pub.EXPECT().Publish(core.EventReset).Return(nil)
+				pub.EXPECT().Publish(core.Event{Type: core.EventReset}).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -303,7 +414,7 @@ func TestDrop(t *testing.T) {
 			desc: "success - publisher error ignored",
 			prepare: func(db *core.MockDB, pub *core.MockPublisher) {
 				db.EXPECT().Drop(gomock.Any()).Return(nil)
-				pub.EXPECT().Publish(core.EventReset).Return(errors.New("publish error"))
+				pub.EXPECT().Publish(core.Event{Type: core.EventReset}).Return(errors.New("publish error"))
 			},
 			wantErr: false,
 		},
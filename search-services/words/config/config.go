@@ -1,18 +1,44 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+type NormConfig struct {
+	NFKC           bool `yaml:"nfkc" env:"NORM_NFKC" env-default:"true"`
+	FoldDiacritics bool `yaml:"fold_diacritics" env:"NORM_FOLD_DIACRITICS" env-default:"true"`
+	Contractions   bool `yaml:"contractions" env:"NORM_CONTRACTIONS" env-default:"true"`
+	Compounds      bool `yaml:"compounds" env:"NORM_COMPOUNDS" env-default:"true"`
+	CaseFolding    bool `yaml:"case_folding" env:"NORM_CASE_FOLDING" env-default:"true"`
+}
+
+type CacheConfig struct {
+	Size int           `yaml:"size" env:"CACHE_SIZE" env-default:"10000"`
+	TTL  time.Duration `yaml:"ttl" env:"CACHE_TTL" env-default:"10m"`
+	// фразы длиннее (в байтах) нормализуются без кэша
+	MaxPhrase int `yaml:"max_phrase" env:"CACHE_MAX_PHRASE" env-default:"4096"`
+}
+
 type Config struct {
-	LogLevel string `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
-	Address  string `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"80"`
+	LogLevel string      `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
+	Address  string      `yaml:"words_address" env:"WORDS_ADDRESS" env-default:"80"`
+	Norm     NormConfig  `yaml:"norm"`
+	Cache    CacheConfig `yaml:"cache"`
 }
 
 func MustLoad(configPath string, cfg *Config) {
+	if err := Load(configPath, cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Load(configPath string, cfg *Config) error {
 	if err := cleanenv.ReadConfig(configPath, cfg); err != nil {
-		log.Fatalf("cannot read config %q: %s", configPath, err)
+		return fmt.Errorf("cannot read config %q: %w", configPath, err)
 	}
+	return nil
 }
@@ -8,10 +8,12 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"search-service/pkg/cache"
 	wordspb "search-service/proto/words"
 	"search-service/words/config"
 	"search-service/words/words"
 	"strconv"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -26,6 +28,28 @@ const maxPhraseLen = 1_048_576 // 1MB
 
 type server struct {
 	wordspb.UnimplementedWordsServer
+	normalizer atomic.Pointer[words.Normalizer]
+	cache      *cache.LRU[normResult]
+	// описания комиксов при загрузке не повторяются, и кэшировать их незачем
+	maxCached int
+}
+
+type normResult struct {
+	words     []string
+	phonetics []string
+	terms     []string
+}
+
+func newServer(normalizer *words.Normalizer, cfg config.CacheConfig) *server {
+	s := &server{cache: cache.New[normResult](cfg.Size, cfg.TTL), maxCached: cfg.MaxPhrase}
+	s.normalizer.Store(normalizer)
+	return s
+}
+
+// reload подменяет нормализатор и сбрасывает кэш, построенный на старых словарях.
+func (s *server) reload(normalizer *words.Normalizer) {
+	s.normalizer.Store(normalizer)
+	s.cache.Purge()
 }
 
 func (s *server) Ping(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
@@ -34,15 +58,114 @@ func (s *server) Ping(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, erro
 
 func (s *server) Norm(_ context.Context, in *wordspb.WordsRequest) (*wordspb.WordsReply, error) {
 	phrase := in.GetPhrase()
-	if len([]byte(phrase)) > maxPhraseLen {
-		return nil, status.Error(
+	if err := checkPhrase(phrase); err != nil {
+		return nil, err
+	}
+	// фонетические коды считаются только по запросу
+	normalizer := s.normalizer.Load().WithPhonetic(in.GetPhonetic())
+	if len(phrase) > s.maxCached {
+		return makeWordsReply(normalize(normalizer, in)), nil
+	}
+	key := cache.Key(fmt.Sprintf("%+v|%t", normalizer.Options(), in.GetTerms()), phrase)
+	result, ok := s.cache.Get(key)
+	if !ok {
+		result = normalize(normalizer, in)
+		s.cache.Add(key, result)
+	}
+	return makeWordsReply(result), nil
+}
+
+func normalize(normalizer *words.Normalizer, in *wordspb.WordsRequest) normResult {
+	var result normResult
+	tokens := normalizer.Tokenize(in.GetPhrase())
+	result.words = words.Keywords(tokens)
+	if normalizer.Options().Phonetic {
+		result.phonetics = words.Phonetics(tokens)
+	}
+	if in.GetTerms() {
+		result.terms = words.Terms(tokens)
+	}
+	return result
+}
+
+func makeWordsReply(result normResult) *wordspb.WordsReply {
+	return &wordspb.WordsReply{
+		Words:     result.words,
+		Phonetics: result.phonetics,
+		Terms:     result.terms,
+	}
+}
+
+func (s *server) CacheStats(_ context.Context, _ *emptypb.Empty) (*wordspb.CacheStatsReply, error) {
+	stats := s.cache.Stats()
+	return &wordspb.CacheStatsReply{
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		Size:      stats.Size,
+	}, nil
+}
+
+func (s *server) Tokenize(_ context.Context, in *wordspb.WordsRequest) (*wordspb.TokenizeReply, error) {
+	phrase := in.GetPhrase()
+	if err := checkPhrase(phrase); err != nil {
+		return nil, err
+	}
+	normalizer := s.normalizer.Load().WithPhonetic(in.GetPhonetic())
+	return makeTokenizeReply(normalizer.Tokenize(phrase)), nil
+}
+
+// TokenizeBatch разбивает фразы одним нормализатором: пакет не разойдется
+// по словарям, даже если их перезагрузят во время запроса.
+func (s *server) TokenizeBatch(_ context.Context, in *wordspb.TokenizeBatchRequest) (*wordspb.TokenizeBatchReply, error) {
+	// ограничение общее на весь пакет, как на одну фразу
+	var size int
+	for _, phrase := range in.GetPhrases() {
+		size += len(phrase)
+	}
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	normalizer := s.normalizer.Load().WithPhonetic(false)
+	reply := &wordspb.TokenizeBatchReply{
+		Replies: make([]*wordspb.TokenizeReply, len(in.GetPhrases())),
+	}
+	for i, phrase := range in.GetPhrases() {
+		reply.Replies[i] = makeTokenizeReply(normalizer.Tokenize(phrase))
+	}
+	return reply, nil
+}
+
+func makeTokenizeReply(tokens []words.Token) *wordspb.TokenizeReply {
+	reply := &wordspb.TokenizeReply{
+		Tokens: make([]*wordspb.Token, len(tokens)),
+	}
+	for i, token := range tokens {
+		reply.Tokens[i] = &wordspb.Token{
+			Text:      token.Text,
+			Start:     int64(token.Start),
+			End:       int64(token.End),
+			Stem:      token.Stem,
+			Stopword:  token.StopWord,
+			Position:  int64(token.Position),
+			Phonetics: token.Phonetic,
+		}
+	}
+	return reply
+}
+
+func checkPhrase(phrase string) error {
+	return checkSize(len(phrase))
+}
+
+func checkSize(size int) error {
+	if size > maxPhraseLen {
+		return status.Error(
 			codes.ResourceExhausted,
 			"phrase is large than "+strconv.Itoa(maxPhraseLen),
 		)
 	}
-	return &wordspb.WordsReply{
-		Words: words.Norm(phrase),
-	}, nil
+	return nil
 }
 
 func main() {
@@ -56,13 +179,13 @@ func main() {
 	// Logger
 	log := mustMakeLogger(cfg.LogLevel)
 
-	if err := run(cfg, log); err != nil {
+	if err := run(cfg, configPath, log); err != nil {
 		log.Error("server failed", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run(cfg config.Config, log *slog.Logger) error {
+func run(cfg config.Config, configPath string, log *slog.Logger) error {
 	log.Info("starting Words service...")
 	log.Debug("debug messages are enabled")
 
@@ -72,13 +195,36 @@ func run(cfg config.Config, log *slog.Logger) error {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
+	srv := newServer(makeNormalizer(cfg.Norm), cfg.Cache)
+
 	s := grpc.NewServer()
-	wordspb.RegisterWordsServer(s, &server{})
+	wordspb.RegisterWordsServer(s, srv)
 	reflection.Register(s)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	// SIGHUP перечитывает настройки нормализации и сбрасывает кэш
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				var reloaded config.Config
+				if err := config.Load(configPath, &reloaded); err != nil {
+					log.Error("failed to reload config", "error", err)
+					continue
+				}
+				srv.reload(makeNormalizer(reloaded.Norm))
+				log.Info("normalization settings reloaded, cache purged")
+			}
+		}
+	}()
+
 	go func() {
 		<-ctx.Done()
 		log.Debug("shutting down Words service...")
@@ -105,6 +251,16 @@ func run(cfg config.Config, log *slog.Logger) error {
 	return nil
 }
 
+func makeNormalizer(cfg config.NormConfig) *words.Normalizer {
+	return words.New(words.Options{
+		NFKC:           cfg.NFKC,
+		FoldDiacritics: cfg.FoldDiacritics,
+		Contractions:   cfg.Contractions,
+		Compounds:      cfg.Compounds,
+		CaseFolding:    cfg.CaseFolding,
+	})
+}
+
 func mustMakeLogger(logLevel string) *slog.Logger {
 	var level slog.Level
 	switch logLevel {
@@ -3,22 +3,281 @@ package words
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/kljensen/snowball/english"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
+// Token - слово фразы вместе с его исходным написанием и положением в тексте.
+type Token struct {
+	Text     string // исходное написание
+	Start    int    // смещение первого байта в исходной фразе
+	End      int    // смещение байта, следующего за последним
+	Stem     string
+	StopWord bool
+	Position int      // порядковый номер токена во фразе
+	Phonetic []string // коды Double Metaphone, если включены в Options
+}
+
+// Options включает отдельные шаги нормализации перед стеммингом.
+type Options struct {
+	NFKC           bool // приведение к форме NFKC: полноширинные и совместимые символы
+	FoldDiacritics bool // "café" -> "cafe"
+	Contractions   bool // "don't", "it's" остаются одним словом
+	Compounds      bool // "e-mail" дает "email" вместе с частями "e" и "mail"
+	CaseFolding    bool // юникодное приведение регистра ("STRASSE" == "straße")
+	Phonetic       bool // коды Double Metaphone для каждого токена
+}
+
+var DefaultOptions = Options{
+	NFKC:           true,
+	FoldDiacritics: true,
+	Contractions:   true,
+	Compounds:      true,
+	CaseFolding:    true,
+}
+
+var defaultNormalizer = New(DefaultOptions)
+
+// окончания, после которых апостроф не разрывает слово
+var contractionSuffixes = map[string]bool{
+	"s": true, "t": true, "d": true, "m": true, "re": true, "ve": true, "ll": true,
+}
+
+type Normalizer struct {
+	opts Options
+}
+
+func New(opts Options) *Normalizer {
+	return &Normalizer{opts: opts}
+}
+
+func (n *Normalizer) Options() Options {
+	return n.opts
+}
+
+// WithPhonetic возвращает нормализатор с теми же шагами, который считает
+// фонетические коды токенов, только если phonetic.
+func (n *Normalizer) WithPhonetic(phonetic bool) *Normalizer {
+	if n.opts.Phonetic == phonetic {
+		return n
+	}
+	opts := n.opts
+	opts.Phonetic = phonetic
+	return New(opts)
+}
+
 func Norm(phrase string) []string {
-	filteredSeq := strings.FieldsFunc(phrase, func(c rune) bool {
-		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
-	})
+	return defaultNormalizer.Norm(phrase)
+}
+
+// Tokenize разбивает фразу на токены без дедупликации и без отбрасывания стоп-слов.
+func Tokenize(phrase string) []Token {
+	return defaultNormalizer.Tokenize(phrase)
+}
+
+func (n *Normalizer) Norm(phrase string) []string {
+	return Keywords(n.Tokenize(phrase))
+}
+
+// Keywords возвращает уникальные основы токенов без стоп-слов.
+func Keywords(tokens []Token) []string {
 	keywords := make([]string, 0)
 	dict := make(map[string]bool)
-	for _, word := range filteredSeq {
-		stemmed := english.Stem(word, true)
-		if !english.IsStopWord(stemmed) && !dict[stemmed] {
-			keywords = append(keywords, stemmed)
-			dict[stemmed] = true
+	for _, token := range tokens {
+		if !token.StopWord && !dict[token.Stem] {
+			keywords = append(keywords, token.Stem)
+			dict[token.Stem] = true
 		}
 	}
 	return keywords
 }
+
+// Terms возвращает основы токенов без стоп-слов в порядке следования, с повторами.
+func Terms(tokens []Token) []string {
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !token.StopWord {
+			terms = append(terms, token.Stem)
+		}
+	}
+	return terms
+}
+
+// Phonetics возвращает уникальные фонетические коды токенов без стоп-слов.
+func Phonetics(tokens []Token) []string {
+	codes := make([]string, 0)
+	dict := make(map[string]bool)
+	for _, token := range tokens {
+		if token.StopWord {
+			continue
+		}
+		for _, code := range token.Phonetic {
+			if !dict[code] {
+				codes = append(codes, code)
+				dict[code] = true
+			}
+		}
+	}
+	return codes
+}
+
+// Tokenize разбивает фразу на токены без дедупликации и без отбрасывания стоп-слов.
+// Составное слово дает токен целиком с позицией первой части, а затем сами части.
+func (n *Normalizer) Tokenize(phrase string) []Token {
+	tokens := make([]Token, 0)
+	position := 0
+	for i := 0; i < len(phrase); {
+		c, size := utf8.DecodeRuneInString(phrase[i:])
+		if !isWordRune(c) {
+			i += size
+			continue
+		}
+		parts := n.scanCompound(phrase, i)
+		end := parts[len(parts)-1][1]
+		if len(parts) > 1 {
+			var joined strings.Builder
+			for _, part := range parts {
+				joined.WriteString(phrase[part[0]:part[1]])
+			}
+			token := n.makeToken(phrase[i:end], joined.String(), i, end, position)
+			tokens = append(tokens, token)
+		}
+		for _, part := range parts {
+			text := phrase[part[0]:part[1]]
+			tokens = append(tokens, n.makeToken(text, text, part[0], part[1], position))
+			position++
+		}
+		i = end
+	}
+	return tokens
+}
+
+// scanCompound возвращает границы слов, начиная с позиции start.
+// Слова, соединенные дефисом, возвращаются вместе как части одного составного слова.
+func (n *Normalizer) scanCompound(phrase string, start int) [][2]int {
+	var parts [][2]int
+	for {
+		end := n.scanWord(phrase, start)
+		parts = append(parts, [2]int{start, end})
+		if !n.opts.Compounds {
+			return parts
+		}
+		c, size := utf8.DecodeRuneInString(phrase[end:])
+		if !isHyphen(c) {
+			return parts
+		}
+		next, _ := utf8.DecodeRuneInString(phrase[end+size:])
+		if !isWordRune(next) {
+			return parts
+		}
+		start = end + size
+	}
+}
+
+func (n *Normalizer) scanWord(phrase string, start int) int {
+	i := start
+	for i < len(phrase) {
+		c, size := utf8.DecodeRuneInString(phrase[i:])
+		if isWordRune(c) {
+			i += size
+			continue
+		}
+		if n.opts.Contractions && isApostrophe(c) && i > start {
+			if suffixEnd, ok := contractionSuffixEnd(phrase, i, size); ok {
+				i = suffixEnd
+				continue
+			}
+		}
+		break
+	}
+	return i
+}
+
+// contractionSuffixEnd проверяет, что за апострофом идет окончание сокращения
+// ("don't", "we're"), и возвращает конец этого окончания.
+func contractionSuffixEnd(phrase string, apostrophe, size int) (int, bool) {
+	prev, _ := utf8.DecodeLastRuneInString(phrase[:apostrophe])
+	if !unicode.IsLetter(prev) {
+		return 0, false
+	}
+	start := apostrophe + size
+	end := start
+	for end < len(phrase) {
+		c, size := utf8.DecodeRuneInString(phrase[end:])
+		if !unicode.IsLetter(c) {
+			break
+		}
+		end += size
+	}
+	if end == start || !contractionSuffixes[strings.ToLower(phrase[start:end])] {
+		return 0, false
+	}
+	return end, true
+}
+
+func (n *Normalizer) makeToken(text, word string, start, end, position int) Token {
+	word = n.normalize(word)
+	stemmed := english.Stem(word, true)
+	stopWord := english.IsStopWord(stemmed)
+	if base, suffix, ok := strings.Cut(word, "'"); ok && !stopWord {
+		// "don't", "it'll", "doesn't": сокращение стоп-слова тоже стоп-слово
+		stopWord = isStopWord(base)
+		if negation, found := strings.CutSuffix(base, "n"); found && suffix == "t" && !stopWord {
+			stopWord = isStopWord(negation)
+		}
+	}
+	token := Token{
+		Text:     text,
+		Start:    start,
+		End:      end,
+		Stem:     stemmed,
+		StopWord: stopWord,
+		Position: position,
+	}
+	if n.opts.Phonetic {
+		token.Phonetic = phoneticCodes(word)
+	}
+	return token
+}
+
+func (n *Normalizer) normalize(word string) string {
+	if n.opts.NFKC {
+		word = norm.NFKC.String(word)
+	}
+	if n.opts.FoldDiacritics {
+		folded, _, err := transform.String(
+			transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), word,
+		)
+		if err == nil {
+			word = folded
+		}
+	}
+	if n.opts.CaseFolding {
+		word = cases.Fold().String(word)
+	}
+	if n.opts.Contractions {
+		word = strings.ReplaceAll(word, "’", "'")
+	}
+	return word
+}
+
+func isStopWord(word string) bool {
+	return english.IsStopWord(word) || english.IsStopWord(english.Stem(word, true))
+}
+
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsNumber(c) || unicode.IsMark(c)
+}
+
+func isApostrophe(c rune) bool {
+	return c == '\'' || c == '’'
+}
+
+func isHyphen(c rune) bool {
+	return c == '-' || c == '‐' || c == '‑'
+}
@@ -40,7 +40,7 @@ var testCases = []struct {
 	{
 		desc:     "weird",
 		given:    "Moscow!123'check-it'or   123, man,that,difficult:heck",
-		expected: []string{"moscow", "check", "123", "man", "difficult", "heck"},
+		expected: []string{"moscow", "checkit", "check", "123", "man", "difficult", "heck"},
 	},
 	{
 		desc:     "numbers only",
@@ -82,3 +82,230 @@ func TestWords(t *testing.T) {
 		})
 	}
 }
+
+// фрагменты транскриптов и alt-текстов xkcd
+var normOptionsCases = []struct {
+	desc     string
+	opts     words.Options
+	given    string
+	expected []string
+}{
+	{
+		desc:     "327: contractions stay whole",
+		opts:     words.DefaultOptions,
+		given:    "Oh, dear -- did he break something? We're having some computer trouble.",
+		expected: []string{"oh", "dear", "break", "someth", "comput", "troubl"},
+	},
+	{
+		desc:     "327: contractions split when disabled",
+		opts:     words.Options{},
+		given:    "Oh, dear -- did he break something? We're having some computer trouble.",
+		expected: []string{"oh", "dear", "break", "someth", "re", "comput", "troubl"},
+	},
+	{
+		desc:     "327: possessive is stemmed away",
+		opts:     words.DefaultOptions,
+		given:    "Help I'm trapped in a driver's license factory.",
+		expected: []string{"help", "trap", "driver", "licens", "factori"},
+	},
+	{
+		desc:     "1053: negations of stop words are stop words",
+		opts:     words.DefaultOptions,
+		given:    "what kind of an idiot doesn't know about the Yellowstone supervolcano",
+		expected: []string{"kind", "idiot", "know", "yellowston", "supervolcano"},
+	},
+	{
+		desc:     "979: hyphenated compound keeps its parts",
+		opts:     words.DefaultOptions,
+		given:    "a sticky globally-editable post",
+		expected: []string{"sticki", "globallyedit", "global", "edit", "post"},
+	},
+	{
+		desc:     "979: hyphen splits when compounds are disabled",
+		opts:     words.Options{},
+		given:    "a sticky globally-editable post",
+		expected: []string{"sticki", "global", "edit", "post"},
+	},
+	{
+		desc:     "1168: e-mail joins into email",
+		opts:     words.DefaultOptions,
+		given:    "tar -xzf and e-mail",
+		expected: []string{"tar", "xzf", "email", "e", "mail"},
+	},
+	{
+		desc:     "diacritics are folded",
+		opts:     words.DefaultOptions,
+		given:    "Café naïve résumé",
+		expected: []string{"cafe", "naiv", "resum"},
+	},
+	{
+		desc:     "diacritics are kept when folding is disabled",
+		opts:     words.Options{NFKC: true},
+		given:    "Café naïve",
+		expected: []string{"café", "naïv"},
+	},
+	{
+		desc:     "decomposed accents do not split words",
+		opts:     words.Options{},
+		given:    "Cafe\u0301 au lait",
+		expected: []string{"cafe\u0301", "au", "lait"},
+	},
+	{
+		desc:     "full-width characters",
+		opts:     words.DefaultOptions,
+		given:    "ｘｋｃｄ by Ｒａｎｄａｌｌ Munroe",
+		expected: []string{"xkcd", "randal", "munro"},
+	},
+	{
+		desc:     "case folding",
+		opts:     words.DefaultOptions,
+		given:    "STRASSE straße",
+		expected: []string{"strass"},
+	},
+	{
+		desc:     "typographic apostrophe",
+		opts:     words.DefaultOptions,
+		given:    "Little Bobby Tables’ mom doesn’t care",
+		expected: []string{"littl", "bobbi", "tabl", "mom", "care"},
+	},
+}
+
+func TestNormOptions(t *testing.T) {
+	for _, tc := range normOptionsCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			keywords := words.New(tc.opts).Norm(tc.given)
+			require.ElementsMatch(t, tc.expected, keywords)
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		given    string
+		expected []words.Token
+	}{
+		{
+			desc:     "empty",
+			given:    "",
+			expected: []words.Token{},
+		},
+		{
+			desc:  "keeps duplicates and stop words",
+			given: "I follow followers",
+			expected: []words.Token{
+				{Text: "I", Start: 0, End: 1, Stem: "i", StopWord: true, Position: 0},
+				{Text: "follow", Start: 2, End: 8, Stem: "follow", Position: 1},
+				{Text: "followers", Start: 9, End: 18, Stem: "follow", Position: 2},
+			},
+		},
+		{
+			desc:  "offsets skip punctuation",
+			given: "  Bobby, Tables!!",
+			expected: []words.Token{
+				{Text: "Bobby", Start: 2, End: 7, Stem: "bobbi", Position: 0},
+				{Text: "Tables", Start: 9, End: 15, Stem: "tabl", Position: 1},
+			},
+		},
+		{
+			desc:  "offsets are in bytes",
+			given: "привет мир",
+			expected: []words.Token{
+				{Text: "привет", Start: 0, End: 12, Stem: "привет", Position: 0},
+				{Text: "мир", Start: 13, End: 19, Stem: "мир", Position: 1},
+			},
+		},
+		{
+			desc:  "compound shares position with its first part",
+			given: "e-mail me",
+			expected: []words.Token{
+				{Text: "e-mail", Start: 0, End: 6, Stem: "email", Position: 0},
+				{Text: "e", Start: 0, End: 1, Stem: "e", Position: 0},
+				{Text: "mail", Start: 2, End: 6, Stem: "mail", Position: 1},
+				{Text: "me", Start: 7, End: 9, Stem: "me", StopWord: true, Position: 2},
+			},
+		},
+		{
+			desc:  "contraction is one token",
+			given: "Don't panic",
+			expected: []words.Token{
+				{Text: "Don't", Start: 0, End: 5, Stem: "don't", StopWord: true, Position: 0},
+				{Text: "panic", Start: 6, End: 11, Stem: "panic", Position: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tokens := words.Tokenize(tc.given)
+			require.Equal(t, tc.expected, tokens)
+			for _, token := range tokens {
+				require.Equal(t, token.Text, tc.given[token.Start:token.End])
+			}
+		})
+	}
+}
+
+func TestDoubleMetaphone(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		given     string
+		primary   string
+		alternate string
+	}{
+		{desc: "empty", given: "", primary: "", alternate: ""},
+		{desc: "randall", given: "Randall", primary: "RNTL", alternate: "RNTL"},
+		{desc: "randal", given: "randal", primary: "RNTL", alternate: "RNTL"},
+		{desc: "munroe", given: "Munroe", primary: "MNR", alternate: "MNR"},
+		{desc: "munro", given: "munro", primary: "MNR", alternate: "MNR"},
+		{desc: "tables", given: "Tables", primary: "TPLS", alternate: "TPLS"},
+		{desc: "tabels", given: "tabels", primary: "TPLS", alternate: "TPLS"},
+		{desc: "th", given: "thomas", primary: "TMS", alternate: "TMS"},
+		{desc: "theta", given: "theta", primary: "0T", alternate: "TT"},
+		{desc: "smith schmidt", given: "smith", primary: "SM0", alternate: "XMT"},
+		{desc: "schmidt", given: "schmidt", primary: "XMT", alternate: "SMT"},
+		{desc: "silent start", given: "knight", primary: "NT", alternate: "NT"},
+		{desc: "laugh", given: "laugh", primary: "LF", alternate: "LF"},
+		{desc: "caesar", given: "caesar", primary: "SSR", alternate: "SSR"},
+		{desc: "michael", given: "Michael", primary: "MKL", alternate: "MXL"},
+		{desc: "digits", given: "327", primary: "", alternate: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			primary, alternate := words.DoubleMetaphone(tc.given)
+			require.Equal(t, tc.primary, primary)
+			require.Equal(t, tc.alternate, alternate)
+		})
+	}
+}
+
+func TestPhonetics(t *testing.T) {
+	normalizer := words.New(words.Options{CaseFolding: true, Contractions: true, Phonetic: true})
+	exact := words.Phonetics(normalizer.Tokenize("Randall Munroe, Bobby Tables"))
+	misspelled := words.Phonetics(normalizer.Tokenize("Randal Munro and bobby tabels"))
+	require.Equal(t, exact, misspelled)
+	require.Empty(t, words.Phonetics(words.Tokenize("Randall Munroe")))
+}
+
+func TestWithPhonetic(t *testing.T) {
+	// коды по запросу не зависят от настроек нормализатора
+	normalizer := words.New(words.DefaultOptions)
+	phonetic := normalizer.WithPhonetic(true)
+	require.Equal(t,
+		words.Phonetics(words.New(words.Options{CaseFolding: true, Phonetic: true}).Tokenize("Munroe")),
+		words.Phonetics(phonetic.Tokenize("Munroe")),
+	)
+	require.NotEmpty(t, words.Phonetics(phonetic.Tokenize("Munroe")))
+	require.Empty(t, words.Phonetics(normalizer.Tokenize("Munroe")))
+	require.Empty(t, words.Phonetics(phonetic.WithPhonetic(false).Tokenize("Munroe")))
+
+	// остальные шаги сохраняются
+	require.Equal(t, normalizer.Tokenize("Café"), phonetic.WithPhonetic(false).Tokenize("Café"))
+	require.Same(t, normalizer, normalizer.WithPhonetic(false))
+}
+
+func TestTerms(t *testing.T) {
+	terms := words.Terms(words.Tokenize("The cat chased the cats, and the cat won"))
+	require.Equal(t, []string{"cat", "chase", "cat", "cat", "won"}, terms)
+	require.Equal(t, []string{"cat", "chase", "won"}, words.Norm("The cat chased the cats, and the cat won"))
+}